@@ -0,0 +1,96 @@
+package admin
+
+import (
+	"time"
+
+	"werk-ticketing/internal/user"
+)
+
+// UpdateRoleRequest incoming body for changing a user's role.
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required" validate:"required,oneof=requester agent admin"`
+}
+
+// RelinkInvGateRequest incoming body for linking a user to another InvGate user.
+// When InvGateUserID is omitted the InvGate user is looked up by email.
+type RelinkInvGateRequest struct {
+	InvGateUserID int `json:"invgate_user_id"`
+}
+
+// ListUsersQuery holds the query parameters of the user list.
+type ListUsersQuery struct {
+	Query  string
+	Role   string
+	Status string
+	Page   int
+	Limit  int
+}
+
+// UserResponse is the admin view of a local user.
+type UserResponse struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	LastName      string     `json:"lastname"`
+	Email         string     `json:"email"`
+	Role          string     `json:"role"`
+	InvGateUserID int        `json:"invgate_user_id"`
+	Active        bool       `json:"active"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	EmailVerified bool       `json:"email_verified"`
+	MFAEnabled    bool       `json:"mfa_enabled"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// UserListResponse is one page of users.
+type UserListResponse struct {
+	Data       []*UserResponse `json:"data"`
+	Pagination Pagination      `json:"pagination"`
+}
+
+// Pagination describes the current page of a list.
+type Pagination struct {
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+	HasNext    bool  `json:"has_next"`
+	HasPrev    bool  `json:"has_prev"`
+}
+
+// UserDetailResponse is a user together with the state of its InvGate link.
+type UserDetailResponse struct {
+	*UserResponse
+	InvGate InvGateLinkResponse `json:"invgate"`
+}
+
+// InvGateLinkResponse describes the InvGate user a local user is linked to.
+type InvGateLinkResponse struct {
+	UserID       int                    `json:"user_id"`
+	Found        bool                   `json:"found"`         // The InvGate user exists
+	EmailMatches bool                   `json:"email_matches"` // The InvGate user has the same email as the local user
+	User         map[string]interface{} `json:"user,omitempty"`
+	Error        string                 `json:"error,omitempty"` // Set when InvGate could not be queried
+}
+
+// PurgeCacheResponse reports how many cache entries were removed.
+type PurgeCacheResponse struct {
+	Removed int64 `json:"removed"`
+}
+
+func toUserResponse(u *user.User) *UserResponse {
+	return &UserResponse{
+		ID:            u.ID,
+		Name:          u.Name,
+		LastName:      u.LastName,
+		Email:         u.Email,
+		Role:          u.Role,
+		InvGateUserID: u.InvGateUserID,
+		Active:        u.IsActive(),
+		DeactivatedAt: u.DeactivatedAt,
+		EmailVerified: u.IsEmailVerified(),
+		MFAEnabled:    u.IsMFAEnabled(),
+		CreatedAt:     u.CreatedAt,
+		UpdatedAt:     u.UpdatedAt,
+	}
+}
@@ -0,0 +1,347 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/middleware"
+	"werk-ticketing/internal/registration"
+	"werk-ticketing/internal/response"
+	"werk-ticketing/internal/tenant"
+)
+
+// Handler exposes HTTP handlers for admin routes.
+type Handler struct {
+	service Service
+}
+
+// NewHandler wires admin service into http handler.
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// UpdateUserRole handles PUT /api/v1/admin/users/:id/role
+func (h *Handler) UpdateUserRole(c *gin.Context) {
+	userID := c.Param("id")
+	if userID == "" {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "user id is required")
+		return
+	}
+
+	var req UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+		return
+	}
+
+	resp, err := h.service.UpdateUserRole(c.Request.Context(), userID, req.Role, middleware.GetUserEmail(c))
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
+
+// UnlockUser handles POST /api/v1/admin/users/:id/unlock
+func (h *Handler) UnlockUser(c *gin.Context) {
+	userID := c.Param("id")
+	if userID == "" {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "user id is required")
+		return
+	}
+
+	if err := h.service.UnlockUser(c.Request.Context(), userID, middleware.GetUserEmail(c)); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, gin.H{"message": "user unlocked successfully"})
+}
+
+// ListUsers handles GET /api/v1/admin/users
+func (h *Handler) ListUsers(c *gin.Context) {
+	query := ListUsersQuery{
+		Query:  c.Query("q"),
+		Role:   c.Query("role"),
+		Status: c.Query("status"),
+	}
+	if page, err := strconv.Atoi(c.Query("page")); err == nil {
+		query.Page = page
+	}
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil {
+		query.Limit = limit
+	}
+
+	resp, err := h.service.ListUsers(c.Request.Context(), query)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
+
+// GetUser handles GET /api/v1/admin/users/:id
+func (h *Handler) GetUser(c *gin.Context) {
+	userID := c.Param("id")
+	if userID == "" {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "user id is required")
+		return
+	}
+
+	resp, err := h.service.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
+
+// DeactivateUser handles POST /api/v1/admin/users/:id/deactivate
+func (h *Handler) DeactivateUser(c *gin.Context) {
+	userID := c.Param("id")
+	if userID == "" {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "user id is required")
+		return
+	}
+
+	resp, err := h.service.DeactivateUser(c.Request.Context(), userID, middleware.GetUserEmail(c))
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
+
+// ReactivateUser handles POST /api/v1/admin/users/:id/reactivate
+func (h *Handler) ReactivateUser(c *gin.Context) {
+	userID := c.Param("id")
+	if userID == "" {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "user id is required")
+		return
+	}
+
+	resp, err := h.service.ReactivateUser(c.Request.Context(), userID, middleware.GetUserEmail(c))
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
+
+// RelinkInvGateUser handles PUT /api/v1/admin/users/:id/invgate
+func (h *Handler) RelinkInvGateUser(c *gin.Context) {
+	userID := c.Param("id")
+	if userID == "" {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "user id is required")
+		return
+	}
+
+	var req RelinkInvGateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+			return
+		}
+	}
+
+	resp, err := h.service.RelinkInvGateUser(c.Request.Context(), userID, req, middleware.GetUserEmail(c))
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
+
+// AssignDefaultScopes handles POST /api/v1/admin/users/:id/scopes
+func (h *Handler) AssignDefaultScopes(c *gin.Context) {
+	userID := c.Param("id")
+	if userID == "" {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "user id is required")
+		return
+	}
+
+	if err := h.service.AssignDefaultScopes(c.Request.Context(), userID, middleware.GetUserEmail(c)); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, gin.H{"message": "default scopes assigned successfully"})
+}
+
+// CreateInvite handles POST /api/v1/admin/invites
+func (h *Handler) CreateInvite(c *gin.Context) {
+	var req registration.CreateInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+		return
+	}
+
+	resp, err := h.service.CreateInvite(c.Request.Context(), middleware.GetUserEmail(c), req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusCreated, resp)
+}
+
+// ListInvites handles GET /api/v1/admin/invites
+func (h *Handler) ListInvites(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	resp, err := h.service.ListInvites(c.Request.Context(), page, limit)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
+
+// RevokeInvite handles DELETE /api/v1/admin/invites/:id
+func (h *Handler) RevokeInvite(c *gin.Context) {
+	inviteID := c.Param("id")
+	if inviteID == "" {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invite id is required")
+		return
+	}
+
+	if err := h.service.RevokeInvite(c.Request.Context(), inviteID, middleware.GetUserEmail(c)); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, gin.H{"message": "invite revoked successfully"})
+}
+
+// ListTenants handles GET /api/v1/admin/tenants
+func (h *Handler) ListTenants(c *gin.Context) {
+	resp, err := h.service.ListTenants(c.Request.Context())
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, gin.H{"data": resp})
+}
+
+// CreateTenant handles POST /api/v1/admin/tenants
+func (h *Handler) CreateTenant(c *gin.Context) {
+	var req tenant.CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+		return
+	}
+
+	resp, err := h.service.CreateTenant(c.Request.Context(), middleware.GetUserEmail(c), req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusCreated, resp)
+}
+
+// UpdateTenant handles PATCH /api/v1/admin/tenants/:id
+func (h *Handler) UpdateTenant(c *gin.Context) {
+	tenantID := c.Param("id")
+	if tenantID == "" {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "tenant id is required")
+		return
+	}
+
+	var req tenant.UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+		return
+	}
+
+	resp, err := h.service.UpdateTenant(c.Request.Context(), tenantID, middleware.GetUserEmail(c), req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
+
+// PurgeCache handles DELETE /api/v1/admin/cache
+func (h *Handler) PurgeCache(c *gin.Context) {
+	resp, err := h.service.PurgeCache(c.Request.Context(), middleware.GetUserEmail(c))
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
@@ -0,0 +1,142 @@
+package admin
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/auth"
+	"werk-ticketing/internal/cache"
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/invgate"
+	"werk-ticketing/internal/loginattempt"
+	"werk-ticketing/internal/registration"
+	"werk-ticketing/internal/session"
+	"werk-ticketing/internal/tenant"
+	"werk-ticketing/internal/ticketmeta"
+	"werk-ticketing/internal/user"
+)
+
+// Service exposes administrative use cases.
+type Service interface {
+	UpdateUserRole(ctx context.Context, userID, role, actorEmail string) (*UserResponse, error)
+	UnlockUser(ctx context.Context, userID, actorEmail string) error
+	ListUsers(ctx context.Context, query ListUsersQuery) (*UserListResponse, error)
+	GetUser(ctx context.Context, userID string) (*UserDetailResponse, error)
+	DeactivateUser(ctx context.Context, userID, actorEmail string) (*UserResponse, error)
+	ReactivateUser(ctx context.Context, userID, actorEmail string) (*UserResponse, error)
+	RelinkInvGateUser(ctx context.Context, userID string, req RelinkInvGateRequest, actorEmail string) (*UserResponse, error)
+	AssignDefaultScopes(ctx context.Context, userID, actorEmail string) error
+	CreateInvite(ctx context.Context, actorEmail string, req registration.CreateInviteRequest) (*registration.CreateInviteResponse, error)
+	ListInvites(ctx context.Context, page, limit int) (*registration.InviteListResponse, error)
+	RevokeInvite(ctx context.Context, inviteID, actorEmail string) error
+	ListTenants(ctx context.Context) ([]*tenant.Response, error)
+	CreateTenant(ctx context.Context, actorEmail string, req tenant.CreateRequest) (*tenant.Response, error)
+	UpdateTenant(ctx context.Context, tenantID, actorEmail string, req tenant.UpdateRequest) (*tenant.Response, error)
+	PurgeCache(ctx context.Context, actorEmail string) (*PurgeCacheResponse, error)
+}
+
+type service struct {
+	userRepo         user.Repository
+	loginAttemptRepo loginattempt.Repository
+	authService      auth.Service
+	registration     registration.Service
+	tenants          tenant.Service
+	invgateClient    invgate.Service
+	cache            cache.Cache
+	ticketMeta       ticketmeta.Service
+	logger           *logrus.Logger
+}
+
+// NewService instantiates admin service.
+func NewService(userRepo user.Repository, loginAttemptRepo loginattempt.Repository, authService auth.Service, registrationService registration.Service, tenantService tenant.Service, invgateClient invgate.Service, referenceCache cache.Cache, ticketMeta ticketmeta.Service, logger *logrus.Logger) Service {
+	return &service{
+		userRepo:         userRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		authService:      authService,
+		registration:     registrationService,
+		tenants:          tenantService,
+		invgateClient:    invgateClient,
+		cache:            referenceCache,
+		ticketMeta:       ticketMeta,
+		logger:           logger,
+	}
+}
+
+func (s *service) UpdateUserRole(ctx context.Context, userID, role, actorEmail string) (*UserResponse, error) {
+	if !user.IsValidRole(role) {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"role must be one of: requester, agent, admin",
+			nil,
+		)
+	}
+
+	target, err := s.getTarget(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prevent admins from accidentally locking themselves out.
+	if strings.EqualFold(target.Email, actorEmail) {
+		return nil, errors.NewAppError(
+			errors.ErrCodeForbidden,
+			"you cannot change your own role",
+			nil,
+		)
+	}
+
+	if err := s.userRepo.UpdateRole(ctx, target.ID, role, actorEmail); err != nil {
+		s.logger.WithError(err).WithField("userID", userID).Error("failed to update user role")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to update user role",
+			err,
+		)
+	}
+
+	// Access tokens carry the role, so tokens issued before the change must
+	// stop working; the user logs in again and gets the new role.
+	if target.Role != role {
+		if err := s.authService.RevokeUserSessions(ctx, target.ID, session.RevokedReasonRoleChanged); err != nil {
+			return nil, err
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"userID":     target.ID,
+		"oldRole":    target.Role,
+		"newRole":    role,
+		"actorEmail": actorEmail,
+	}).Info("user role updated")
+
+	target.Role = role
+	target.UpdatedBy = actorEmail
+	return toUserResponse(target), nil
+}
+
+// UnlockUser clears the failed login counter of a user, lifting a temporary lock.
+func (s *service) UnlockUser(ctx context.Context, userID, actorEmail string) error {
+	target, err := s.getTarget(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	key := loginattempt.EmailKey(target.TenantID, target.Email)
+	if err := s.loginAttemptRepo.Delete(ctx, loginattempt.ScopeEmail, key); err != nil {
+		s.logger.WithError(err).WithField("userID", userID).Error("failed to clear login attempts")
+		return errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to unlock user",
+			err,
+		)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"userID":     target.ID,
+		"actorEmail": actorEmail,
+	}).Info("user unlocked")
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package admin
+
+import (
+	"context"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/invgate"
+)
+
+// PurgeCache drops the cached InvGate reference data of the tenant, so the
+// next requests load categories, articles, types, priorities and statuses
+// from InvGate again.
+func (s *service) PurgeCache(ctx context.Context, actorEmail string) (*PurgeCacheResponse, error) {
+	prefix := invgate.CacheKeyPrefix(ctx)
+	removed, err := s.cache.Purge(ctx, prefix)
+	if err != nil {
+		s.logger.WithError(err).WithField("prefix", prefix).Error("failed to purge cache")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to purge cache",
+			err,
+		)
+	}
+	s.ticketMeta.Invalidate(ctx)
+
+	s.logger.WithField("removed", removed).WithField("actorEmail", actorEmail).Info("reference data cache purged")
+	return &PurgeCacheResponse{Removed: removed}, nil
+}
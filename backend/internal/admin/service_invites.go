@@ -0,0 +1,22 @@
+package admin
+
+import (
+	"context"
+
+	"werk-ticketing/internal/registration"
+)
+
+// CreateInvite issues a registration invite code. The code is only part of this response.
+func (s *service) CreateInvite(ctx context.Context, actorEmail string, req registration.CreateInviteRequest) (*registration.CreateInviteResponse, error) {
+	return s.registration.CreateInvite(ctx, actorEmail, req)
+}
+
+// ListInvites returns one page of invite codes, newest first.
+func (s *service) ListInvites(ctx context.Context, page, limit int) (*registration.InviteListResponse, error) {
+	return s.registration.ListInvites(ctx, page, limit)
+}
+
+// RevokeInvite revokes an invite code that has not been used yet.
+func (s *service) RevokeInvite(ctx context.Context, inviteID, actorEmail string) error {
+	return s.registration.RevokeInvite(ctx, inviteID, actorEmail)
+}
@@ -0,0 +1,65 @@
+package admin
+
+import (
+	"context"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/registration"
+	"werk-ticketing/internal/tenant"
+)
+
+// ListTenants returns all tenants.
+func (s *service) ListTenants(ctx context.Context) ([]*tenant.Response, error) {
+	if err := requireDefaultTenant(ctx); err != nil {
+		return nil, err
+	}
+	return s.tenants.List(ctx)
+}
+
+// CreateTenant adds a tenant with its own InvGate connection.
+func (s *service) CreateTenant(ctx context.Context, actorEmail string, req tenant.CreateRequest) (*tenant.Response, error) {
+	if err := requireDefaultTenant(ctx); err != nil {
+		return nil, err
+	}
+	if err := validateRegistrationDomains(req.RegistrationDomains); err != nil {
+		return nil, err
+	}
+	return s.tenants.Create(ctx, actorEmail, req)
+}
+
+// UpdateTenant changes the settings of a tenant other than the default one.
+func (s *service) UpdateTenant(ctx context.Context, tenantID, actorEmail string, req tenant.UpdateRequest) (*tenant.Response, error) {
+	if err := requireDefaultTenant(ctx); err != nil {
+		return nil, err
+	}
+	if req.RegistrationDomains != nil {
+		if err := validateRegistrationDomains(*req.RegistrationDomains); err != nil {
+			return nil, err
+		}
+	}
+	return s.tenants.Update(ctx, tenantID, actorEmail, req)
+}
+
+// requireDefaultTenant limits tenant management to admins of the default
+// tenant, admins of other tenants only manage their own users.
+func requireDefaultTenant(ctx context.Context) error {
+	if t := tenant.FromContext(ctx); t == nil || !t.IsDefault {
+		return errors.NewAppError(
+			errors.ErrCodeForbidden,
+			"tenants can only be managed by admins of the default tenant",
+			nil,
+		)
+	}
+	return nil
+}
+
+func validateRegistrationDomains(raw string) error {
+	if _, err := registration.ParseDomainRules(raw, registration.Scope{}); err != nil {
+		return errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			err.Error(),
+			err,
+		)
+	}
+	return nil
+}
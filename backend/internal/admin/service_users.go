@@ -0,0 +1,346 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/session"
+	"werk-ticketing/internal/tenant"
+	"werk-ticketing/internal/user"
+)
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
+// ListUsers returns one page of local users, optionally filtered by a search
+// term (email, name or last name), role and status.
+func (s *service) ListUsers(ctx context.Context, query ListUsersQuery) (*UserListResponse, error) {
+	if query.Role != "" && !user.IsValidRole(query.Role) {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"role must be one of: requester, agent, admin",
+			nil,
+		)
+	}
+	if query.Status != "" && query.Status != user.StatusActive && query.Status != user.StatusDeactivated {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"status must be one of: active, deactivated",
+			nil,
+		)
+	}
+
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := query.Limit
+	if limit < 1 {
+		limit = defaultUserPageSize
+	}
+	if limit > maxUserPageSize {
+		limit = maxUserPageSize
+	}
+
+	filter := user.ListFilter{
+		TenantID: tenant.IDFromContext(ctx),
+		Query:    strings.TrimSpace(query.Query),
+		Role:     query.Role,
+		Status:   query.Status,
+	}
+	users, total, err := s.userRepo.List(ctx, filter, limit, (page-1)*limit)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to list users")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to list users",
+			err,
+		)
+	}
+
+	data := make([]*UserResponse, 0, len(users))
+	for i := range users {
+		data = append(data, toUserResponse(&users[i]))
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	return &UserListResponse{
+		Data: data,
+		Pagination: Pagination{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+// GetUser returns a user together with the InvGate user it is linked to.
+// InvGate failures are reported in the response instead of failing the request,
+// since a broken link is exactly what admins use this endpoint to diagnose.
+func (s *service) GetUser(ctx context.Context, userID string) (*UserDetailResponse, error) {
+	target, err := s.getTarget(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	link := InvGateLinkResponse{UserID: target.InvGateUserID}
+	if target.InvGateUserID > 0 {
+		invGateUser, err := s.invgateClient.GetUser(ctx, target.InvGateUserID)
+		if err != nil {
+			s.logger.WithError(err).WithField("invGateUserID", target.InvGateUserID).Warn("failed to get InvGate user")
+			link.Error = err.Error()
+		} else if len(invGateUser) > 0 {
+			link.Found = true
+			link.User = invGateUser
+			link.EmailMatches = strings.EqualFold(invGateEmail(invGateUser), target.Email)
+		}
+	}
+
+	return &UserDetailResponse{
+		UserResponse: toUserResponse(target),
+		InvGate:      link,
+	}, nil
+}
+
+// DeactivateUser blocks a user from logging in and ends all of its sessions.
+func (s *service) DeactivateUser(ctx context.Context, userID, actorEmail string) (*UserResponse, error) {
+	target, err := s.getTarget(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(target.Email, actorEmail) {
+		return nil, errors.NewAppError(
+			errors.ErrCodeForbidden,
+			"you cannot deactivate your own account",
+			nil,
+		)
+	}
+
+	if target.IsActive() {
+		now := time.Now().UTC()
+		if err := s.userRepo.SetDeactivatedAt(ctx, target.ID, &now, actorEmail); err != nil {
+			s.logger.WithError(err).WithField("userID", target.ID).Error("failed to deactivate user")
+			return nil, errors.NewAppError(
+				errors.ErrCodeInternal,
+				"failed to deactivate user",
+				err,
+			)
+		}
+		target.DeactivatedAt = &now
+		target.UpdatedBy = actorEmail
+	}
+
+	// Also runs for already deactivated users, in case an earlier revocation failed
+	if err := s.authService.RevokeUserSessions(ctx, target.ID, session.RevokedReasonDeactivated); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"userID":     target.ID,
+		"actorEmail": actorEmail,
+	}).Info("user deactivated")
+
+	return toUserResponse(target), nil
+}
+
+// ReactivateUser allows a deactivated user to log in again.
+func (s *service) ReactivateUser(ctx context.Context, userID, actorEmail string) (*UserResponse, error) {
+	target, err := s.getTarget(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !target.IsActive() {
+		if err := s.userRepo.SetDeactivatedAt(ctx, target.ID, nil, actorEmail); err != nil {
+			s.logger.WithError(err).WithField("userID", target.ID).Error("failed to reactivate user")
+			return nil, errors.NewAppError(
+				errors.ErrCodeInternal,
+				"failed to reactivate user",
+				err,
+			)
+		}
+		target.DeactivatedAt = nil
+		target.UpdatedBy = actorEmail
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"userID":     target.ID,
+		"actorEmail": actorEmail,
+	}).Info("user reactivated")
+
+	return toUserResponse(target), nil
+}
+
+// RelinkInvGateUser points a local user to another InvGate user. The InvGate
+// user must exist, carry the same email and not be linked to another local user.
+func (s *service) RelinkInvGateUser(ctx context.Context, userID string, req RelinkInvGateRequest, actorEmail string) (*UserResponse, error) {
+	target, err := s.getTarget(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.InvGateUserID < 0 {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"invgate_user_id must be a positive number",
+			nil,
+		)
+	}
+
+	var invGateUser map[string]interface{}
+	if req.InvGateUserID > 0 {
+		invGateUser, err = s.invgateClient.GetUser(ctx, req.InvGateUserID)
+	} else {
+		invGateUser, err = s.invgateClient.GetUserByEmail(ctx, target.Email)
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("userID", target.ID).Error("failed to look up InvGate user")
+		return nil, errors.NewAppError(
+			errors.ErrCodeExternalService,
+			"failed to look up user in InvGate",
+			err,
+		)
+	}
+
+	invGateUserID, err := invGateID(invGateUser)
+	if err != nil || invGateUserID <= 0 {
+		return nil, errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"InvGate user not found",
+			err,
+		)
+	}
+	if !strings.EqualFold(invGateEmail(invGateUser), target.Email) {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"InvGate user has a different email than the local user",
+			nil,
+		)
+	}
+
+	owner, err := s.userRepo.GetByInvGateUserID(ctx, target.TenantID, invGateUserID)
+	if err != nil {
+		s.logger.WithError(err).WithField("invGateUserID", invGateUserID).Error("failed to get user by InvGate user id")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to retrieve user information",
+			err,
+		)
+	}
+	if owner != nil && owner.ID != target.ID {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"InvGate user is already linked to another local user",
+			nil,
+		)
+	}
+
+	if invGateUserID != target.InvGateUserID {
+		if err := s.userRepo.UpdateInvGateUserID(ctx, target.ID, invGateUserID, actorEmail); err != nil {
+			s.logger.WithError(err).WithField("userID", target.ID).Error("failed to update InvGate user id")
+			return nil, errors.NewAppError(
+				errors.ErrCodeInternal,
+				"failed to link InvGate user",
+				err,
+			)
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"userID":           target.ID,
+		"oldInvGateUserID": target.InvGateUserID,
+		"newInvGateUserID": invGateUserID,
+		"actorEmail":       actorEmail,
+	}).Info("InvGate user relinked")
+
+	target.InvGateUserID = invGateUserID
+	target.UpdatedBy = actorEmail
+	return toUserResponse(target), nil
+}
+
+// AssignDefaultScopes re-runs the assignment of the linked InvGate user to the
+// company, group and location the user registered with.
+func (s *service) AssignDefaultScopes(ctx context.Context, userID, actorEmail string) error {
+	target, err := s.getTarget(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if target.InvGateUserID <= 0 {
+		return errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"user is not linked to an InvGate user",
+			nil,
+		)
+	}
+
+	if err := s.authService.AssignDefaultScopes(ctx, target); err != nil {
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"userID":        target.ID,
+		"invGateUserID": target.InvGateUserID,
+		"actorEmail":    actorEmail,
+	}).Info("InvGate default scopes reassigned")
+
+	return nil
+}
+
+func (s *service) getTarget(ctx context.Context, userID string) (*user.User, error) {
+	target, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		s.logger.WithError(err).WithField("userID", userID).Error("failed to get user by id")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to retrieve user information",
+			err,
+		)
+	}
+	// Users of other tenants are reported as missing
+	if target == nil || target.TenantID != tenant.IDFromContext(ctx) {
+		return nil, errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"user not found",
+			nil,
+		)
+	}
+	return target, nil
+}
+
+// invGateEmail reads the email of an InvGate user payload.
+func invGateEmail(u map[string]interface{}) string {
+	email, _ := u["email"].(string)
+	return email
+}
+
+// invGateID reads the ID of an InvGate user payload.
+func invGateID(u map[string]interface{}) (int, error) {
+	switch v := u["id"].(type) {
+	case float64:
+		return int(v), nil
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case nil:
+		return 0, fmt.Errorf("user ID not found in InvGate response")
+	default:
+		return 0, fmt.Errorf("unexpected type for InvGate user ID: %T", v)
+	}
+}
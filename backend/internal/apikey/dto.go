@@ -0,0 +1,46 @@
+package apikey
+
+import "time"
+
+// CreateRequest incoming body for creating an API key.
+// ExpiresAt is optional and defaults to constants.APIKeyDefaultExpiration from now.
+type CreateRequest struct {
+	Name      string     `json:"name" binding:"required,min=1,max=100" validate:"required,min=1,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1" validate:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// UpdateRequest incoming body for updating an API key. Omitted fields are kept.
+type UpdateRequest struct {
+	Name   *string  `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// KeyResponse describes an API key without its secret.
+type KeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateResponse is returned once on creation. Key is never shown again.
+type CreateResponse struct {
+	KeyResponse
+	Key string `json:"key"`
+}
+
+func toKeyResponse(k *APIKey) KeyResponse {
+	return KeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     KeyPrefix + k.Prefix,
+		Scopes:     k.ScopeList(),
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
@@ -0,0 +1,84 @@
+package apikey
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/middleware"
+	"werk-ticketing/internal/response"
+)
+
+// Handler exposes HTTP handlers for API key routes.
+type Handler struct {
+	service Service
+}
+
+// NewHandler wires API key service into http handler.
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Create handles POST /api/v1/api-keys
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+		return
+	}
+
+	resp, err := h.service.Create(c.Request.Context(), middleware.GetUserEmail(c), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	response.Write(c, http.StatusCreated, resp)
+}
+
+// List handles GET /api/v1/api-keys
+func (h *Handler) List(c *gin.Context) {
+	resp, err := h.service.List(c.Request.Context(), middleware.GetUserEmail(c))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	response.Write(c, http.StatusOK, gin.H{"data": resp})
+}
+
+// Update handles PATCH /api/v1/api-keys/:id
+func (h *Handler) Update(c *gin.Context) {
+	var req UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+		return
+	}
+
+	resp, err := h.service.Update(c.Request.Context(), middleware.GetUserEmail(c), c.Param("id"), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
+
+// Delete handles DELETE /api/v1/api-keys/:id
+func (h *Handler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), middleware.GetUserEmail(c), c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	response.Write(c, http.StatusOK, gin.H{"message": "api key deleted successfully"})
+}
+
+func writeError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		response.AppError(c, appErr)
+	} else {
+		response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+	}
+}
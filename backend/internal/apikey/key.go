@@ -0,0 +1,44 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+
+	"werk-ticketing/internal/keyauth"
+)
+
+// KeyPrefix marks API keys so they can be told apart from JWTs in a Bearer header.
+const KeyPrefix = keyauth.Prefix
+
+var keyEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateKey returns a new key of the form wtk_<prefix>_<secret> and its lookup prefix.
+func generateKey() (key, prefix string, err error) {
+	b := make([]byte, 5+20)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	prefix = strings.ToLower(keyEncoding.EncodeToString(b[:5]))
+	secret := strings.ToLower(keyEncoding.EncodeToString(b[5:]))
+	return KeyPrefix + prefix + "_" + secret, prefix, nil
+}
+
+// parseKey extracts the lookup prefix from a key.
+func parseKey(key string) (string, bool) {
+	if !strings.HasPrefix(key, KeyPrefix) {
+		return "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(key, KeyPrefix), "_", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+	return parts[0], true
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
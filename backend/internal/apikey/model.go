@@ -0,0 +1,67 @@
+package apikey
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"werk-ticketing/internal/database"
+)
+
+// Supported API key scopes.
+const (
+	ScopeTicketsRead  = "tickets:read"  // List and read tickets, comments and attachments
+	ScopeTicketsWrite = "tickets:write" // Create and update tickets, add comments, accept/reject solutions
+)
+
+// IsValidScope reports whether scope is one of the supported scopes.
+func IsValidScope(scope string) bool {
+	switch scope {
+	case ScopeTicketsRead, ScopeTicketsWrite:
+		return true
+	default:
+		return false
+	}
+}
+
+// APIKey is a personal API key. Only the SHA-256 hash of the key is stored;
+// the prefix is stored in clear to find the row without scanning hashes.
+type APIKey struct {
+	ID         string     `gorm:"type:char(36);primaryKey"`
+	UserID     string     `gorm:"type:char(36);not null;index"`
+	Name       string     `gorm:"size:100;not null"`
+	Prefix     string     `gorm:"size:16;not null;uniqueIndex"` // Random lookup part of the key, shown in listings
+	KeyHash    string     `gorm:"size:64;not null"`             // hex encoded SHA-256 of the full key
+	Scopes     string     `gorm:"size:255;not null"`            // Comma separated scopes
+	ExpiresAt  time.Time  `gorm:"not null;index"`
+	LastUsedAt *time.Time // Updated at most once per minute
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// BeforeCreate assigns the UUID in Go so the ID is known to the caller after insert.
+func (k *APIKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == "" {
+		k.ID = database.NewUUID()
+	}
+	return nil
+}
+
+// ScopeList returns the scopes of the key.
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return nil
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// IsExpired reports whether the key can no longer be used.
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
@@ -0,0 +1,93 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Repository abstracts data persistence for API keys.
+type Repository interface {
+	Create(ctx context.Context, key *APIKey) error
+	GetByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	GetByIDForUser(ctx context.Context, id, userID string) (*APIKey, error)
+	ListByUser(ctx context.Context, userID string) ([]APIKey, error)
+	Update(ctx context.Context, key *APIKey) error
+	Delete(ctx context.Context, id, userID string) (bool, error)
+	DeleteAllForUser(ctx context.Context, userID string) error
+	TouchLastUsed(ctx context.Context, id string, usedAt time.Time) error
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository builds a Gorm-backed API key repository.
+func NewRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+func (r *gormRepository) Create(ctx context.Context, key *APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+func (r *gormRepository) GetByPrefix(ctx context.Context, prefix string) (*APIKey, error) {
+	var k APIKey
+	err := r.db.WithContext(ctx).Where("prefix = ?", prefix).First(&k).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &k, nil
+}
+
+// GetByIDForUser only returns the key when it belongs to userID.
+func (r *gormRepository) GetByIDForUser(ctx context.Context, id, userID string) (*APIKey, error) {
+	var k APIKey
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&k).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *gormRepository) ListByUser(ctx context.Context, userID string) ([]APIKey, error) {
+	var keys []APIKey
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// Update saves the editable fields of a key.
+func (r *gormRepository) Update(ctx context.Context, key *APIKey) error {
+	return r.db.WithContext(ctx).Model(&APIKey{}).Where("id = ?", key.ID).Updates(map[string]interface{}{
+		"name":       key.Name,
+		"scopes":     key.Scopes,
+		"expires_at": key.ExpiresAt,
+	}).Error
+}
+
+// Delete removes a key of userID. It returns false when no such key exists.
+func (r *gormRepository) Delete(ctx context.Context, id, userID string) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&APIKey{}, "id = ? AND user_id = ?", id, userID)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteAllForUser removes every key of a user.
+func (r *gormRepository) DeleteAllForUser(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Delete(&APIKey{}, "user_id = ?", userID).Error
+}
+
+func (r *gormRepository) TouchLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&APIKey{}).Where("id = ?", id).
+		UpdateColumn("last_used_at", usedAt).Error
+}
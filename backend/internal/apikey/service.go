@@ -0,0 +1,368 @@
+package apikey
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/constants"
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/keyauth"
+	"werk-ticketing/internal/tenant"
+	"werk-ticketing/internal/user"
+)
+
+// lastUsedResolution limits last-used writes to one per key and minute.
+const lastUsedResolution = time.Minute
+
+// Service exposes API key use cases.
+type Service interface {
+	Create(ctx context.Context, ownerEmail string, req CreateRequest) (*CreateResponse, error)
+	List(ctx context.Context, ownerEmail string) ([]KeyResponse, error)
+	Update(ctx context.Context, ownerEmail, id string, req UpdateRequest) (*KeyResponse, error)
+	Delete(ctx context.Context, ownerEmail, id string) error
+	AuthenticateAPIKey(ctx context.Context, key string) (*keyauth.Identity, error)
+}
+
+type service struct {
+	repository Repository
+	userRepo   user.Repository
+	logger     *logrus.Logger
+}
+
+// NewService instantiates API key service.
+func NewService(repository Repository, userRepo user.Repository, logger *logrus.Logger) Service {
+	return &service{
+		repository: repository,
+		userRepo:   userRepo,
+		logger:     logger,
+	}
+}
+
+func (s *service) Create(ctx context.Context, ownerEmail string, req CreateRequest) (*CreateResponse, error) {
+	owner, err := s.getOwner(ctx, ownerEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"name must be between 1 and 100 characters",
+			nil,
+		)
+	}
+
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(constants.APIKeyDefaultExpiration)
+	if req.ExpiresAt != nil {
+		expiresAt = req.ExpiresAt.UTC()
+	}
+	if err := validateExpiry(expiresAt, now); err != nil {
+		return nil, err
+	}
+
+	plain, prefix, err := generateKey()
+	if err != nil {
+		s.logger.WithError(err).Error("failed to generate api key")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to create api key",
+			err,
+		)
+	}
+
+	key := &APIKey{
+		UserID:    owner.ID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   hashKey(plain),
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.repository.Create(ctx, key); err != nil {
+		s.logger.WithError(err).WithField("userID", owner.ID).Error("failed to store api key")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to create api key",
+			err,
+		)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"userID": owner.ID,
+		"keyID":  key.ID,
+		"scopes": key.Scopes,
+	}).Info("api key created")
+
+	return &CreateResponse{
+		KeyResponse: toKeyResponse(key),
+		Key:         plain,
+	}, nil
+}
+
+func (s *service) List(ctx context.Context, ownerEmail string) ([]KeyResponse, error) {
+	owner, err := s.getOwner(ctx, ownerEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.repository.ListByUser(ctx, owner.ID)
+	if err != nil {
+		s.logger.WithError(err).WithField("userID", owner.ID).Error("failed to list api keys")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to list api keys",
+			err,
+		)
+	}
+
+	resp := make([]KeyResponse, 0, len(keys))
+	for i := range keys {
+		resp = append(resp, toKeyResponse(&keys[i]))
+	}
+	return resp, nil
+}
+
+func (s *service) Update(ctx context.Context, ownerEmail, id string, req UpdateRequest) (*KeyResponse, error) {
+	owner, err := s.getOwner(ctx, ownerEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.repository.GetByIDForUser(ctx, id, owner.ID)
+	if err != nil {
+		s.logger.WithError(err).WithField("keyID", id).Error("failed to get api key")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to update api key",
+			err,
+		)
+	}
+	if key == nil {
+		return nil, errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"api key not found",
+			nil,
+		)
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || len(name) > 100 {
+			return nil, errors.NewAppError(
+				errors.ErrCodeInvalidInput,
+				"name must be between 1 and 100 characters",
+				nil,
+			)
+		}
+		key.Name = name
+	}
+	if req.Scopes != nil {
+		scopes, err := normalizeScopes(req.Scopes)
+		if err != nil {
+			return nil, err
+		}
+		key.Scopes = strings.Join(scopes, ",")
+	}
+
+	if err := s.repository.Update(ctx, key); err != nil {
+		s.logger.WithError(err).WithField("keyID", id).Error("failed to update api key")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to update api key",
+			err,
+		)
+	}
+
+	s.logger.WithField("keyID", key.ID).WithField("scopes", key.Scopes).Info("api key updated")
+
+	resp := toKeyResponse(key)
+	return &resp, nil
+}
+
+func (s *service) Delete(ctx context.Context, ownerEmail, id string) error {
+	owner, err := s.getOwner(ctx, ownerEmail)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.repository.Delete(ctx, id, owner.ID)
+	if err != nil {
+		s.logger.WithError(err).WithField("keyID", id).Error("failed to delete api key")
+		return errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to delete api key",
+			err,
+		)
+	}
+	if !deleted {
+		return errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"api key not found",
+			nil,
+		)
+	}
+
+	s.logger.WithField("keyID", id).WithField("userID", owner.ID).Info("api key deleted")
+	return nil
+}
+
+// AuthenticateAPIKey resolves the owner of a key. Unknown, mismatching and
+// expired keys are all reported as the same unauthorized error.
+func (s *service) AuthenticateAPIKey(ctx context.Context, plain string) (*keyauth.Identity, error) {
+	invalid := errors.NewAppError(
+		errors.ErrCodeUnauthorized,
+		"invalid api key",
+		nil,
+	)
+
+	prefix, ok := parseKey(plain)
+	if !ok {
+		return nil, invalid
+	}
+
+	key, err := s.repository.GetByPrefix(ctx, prefix)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get api key")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to verify api key",
+			err,
+		)
+	}
+	if key == nil || subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashKey(plain))) != 1 {
+		return nil, invalid
+	}
+
+	now := time.Now().UTC()
+	if key.IsExpired(now) {
+		return nil, errors.NewAppError(
+			errors.ErrCodeUnauthorized,
+			"api key has expired",
+			nil,
+		)
+	}
+
+	owner, err := s.userRepo.GetByID(ctx, key.UserID)
+	if err != nil {
+		s.logger.WithError(err).WithField("keyID", key.ID).Error("failed to get api key owner")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to verify api key",
+			err,
+		)
+	}
+	if owner == nil {
+		return nil, invalid
+	}
+	if !owner.IsActive() {
+		return nil, errors.NewAppError(
+			errors.ErrCodeAccountDisabled,
+			"this account has been deactivated",
+			nil,
+		)
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := s.repository.TouchLastUsed(ctx, key.ID, now); err != nil {
+			s.logger.WithError(err).WithField("keyID", key.ID).Warn("failed to update api key last used time")
+		}
+	}
+
+	role := owner.Role
+	if role == "" {
+		role = user.RoleRequester
+	}
+
+	return &keyauth.Identity{
+		KeyID:    key.ID,
+		Email:    owner.Email,
+		Role:     role,
+		TenantID: owner.TenantID,
+		Scopes:   key.ScopeList(),
+	}, nil
+}
+
+func (s *service) getOwner(ctx context.Context, email string) (*user.User, error) {
+	if email == "" {
+		return nil, errors.NewAppError(
+			errors.ErrCodeUnauthorized,
+			"user email not found",
+			nil,
+		)
+	}
+
+	owner, err := s.userRepo.GetByEmail(ctx, tenant.IDFromContext(ctx), email)
+	if err != nil {
+		s.logger.WithError(err).WithField("email", email).Error("failed to get user by email")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to retrieve user information",
+			err,
+		)
+	}
+	if owner == nil {
+		return nil, errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"user not found",
+			nil,
+		)
+	}
+	return owner, nil
+}
+
+// normalizeScopes validates and de-duplicates scopes.
+func normalizeScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var result []string
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !IsValidScope(scope) {
+			return nil, errors.NewAppError(
+				errors.ErrCodeInvalidInput,
+				"invalid scope "+scope+", must be one of: "+ScopeTicketsRead+", "+ScopeTicketsWrite,
+				nil,
+			)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	if len(result) == 0 {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"at least one scope is required",
+			nil,
+		)
+	}
+	return result, nil
+}
+
+func validateExpiry(expiresAt, now time.Time) error {
+	if !expiresAt.After(now) {
+		return errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"expires_at must be in the future",
+			nil,
+		)
+	}
+	if expiresAt.After(now.Add(constants.APIKeyMaxExpiration)) {
+		return errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"expires_at must be within 365 days",
+			nil,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package auth
+
+import "github.com/golang-jwt/jwt/v5"
+
+// Token types carried in the "typ" claim.
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+	// TokenTypeMFAChallenge is issued after a correct password when the
+	// user has two-factor authentication enabled.
+	TokenTypeMFAChallenge = "mfa_challenge"
+)
+
+// Claims are the JWT claims issued by this service.
+// Type separates access from refresh tokens so one cannot be used as the other,
+// SessionID links both to the server-side session, and Role and TenantID let
+// middleware authorize and select the tenant without a DB lookup.
+type Claims struct {
+	Type      string `json:"typ"`
+	SessionID string `json:"sid,omitempty"`
+	Role      string `json:"role,omitempty"`
+	TenantID  string `json:"tid,omitempty"`
+	jwt.RegisteredClaims
+}
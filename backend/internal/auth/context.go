@@ -0,0 +1,22 @@
+package auth
+
+import "github.com/gin-gonic/gin"
+
+// claimsKey holds the claims of the access token on the gin context.
+const claimsKey = "authClaims"
+
+// SetClaims stores the claims of the request's access token. middleware.WithAuth
+// calls it so handlers of this package can read the session of the token.
+func SetClaims(c *gin.Context, claims *Claims) {
+	c.Set(claimsKey, claims)
+}
+
+// ClaimsFromContext returns the claims stored by SetClaims.
+func ClaimsFromContext(c *gin.Context) (*Claims, bool) {
+	value, ok := c.Get(claimsKey)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := value.(*Claims)
+	return claims, ok && claims != nil
+}
@@ -1,29 +1,135 @@
 package auth
 
+import "time"
+
+// ClientInfo describes the device a request came from.
+// It is filled by the handler from request headers, never from the JSON body.
+type ClientInfo struct {
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
+}
+
 // RegisterRequest incoming body.
 type RegisterRequest struct {
 	Name     string `json:"name" binding:"required,min=1,max=100" validate:"required,min=1,max=100"`
 	LastName string `json:"lastname" binding:"required,min=1,max=100" validate:"required,min=1,max=100"`
 	Email    string `json:"email" binding:"required,email" validate:"required,email"`
 	Password string `json:"password" binding:"required,min=6" validate:"required,min=6"`
+
+	// Required unless the email domain is on the registration allowlist
+	InviteCode string `json:"invite_code"`
+
+	Client ClientInfo `json:"-"`
 }
 
 // LoginRequest incoming body.
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email" validate:"required,email"`
 	Password string `json:"password" binding:"required,min=6" validate:"required,min=6"`
+
+	Client ClientInfo `json:"-"`
 }
 
 // AuthResponse standard auth payload.
 type AuthResponse struct {
-	Token        string `json:"token"`
-	RefreshToken string `json:"refresh_token,omitempty"`
-	Name         string `json:"name"`
-	LastName     string `json:"lastname"`
-	Email        string `json:"email"`
+	Token         string `json:"token"`
+	RefreshToken  string `json:"refresh_token,omitempty"`
+	Name          string `json:"name"`
+	LastName      string `json:"lastname"`
+	Email         string `json:"email"`
+	Role          string `json:"role"`
+	EmailVerified bool   `json:"email_verified"`
+
+	// Set instead of the tokens when the password was correct but the user
+	// still has to complete two-factor authentication via /auth/mfa/verify.
+	MFARequired bool   `json:"mfa_required,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty"`
 }
 
 // RefreshTokenRequest request for token refresh
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required" validate:"required"`
+
+	Client ClientInfo `json:"-"`
+}
+
+// ForgotPasswordRequest starts the password reset flow.
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email" validate:"required,email"`
+}
+
+// ResetPasswordRequest completes the password reset flow.
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required" validate:"required"`
+	Password string `json:"password" binding:"required,min=6" validate:"required,min=6"`
+}
+
+// ChangePasswordRequest changes the password of the logged-in user.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required" validate:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6" validate:"required,min=6"`
+}
+
+// VerifyEmailRequest confirms an email address with the emailed token.
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required" validate:"required"`
+}
+
+// ResendVerificationRequest asks for a new verification email.
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email" validate:"required,email"`
+}
+
+// MFAEnrollResponse contains the TOTP secret to add to an authenticator app.
+type MFAEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// MFAConfirmRequest confirms enrollment with a code from the authenticator app.
+type MFAConfirmRequest struct {
+	Code string `json:"code" binding:"required" validate:"required"`
+}
+
+// MFARecoveryCodesResponse lists the recovery codes. They are only shown once.
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFAVerifyRequest completes a login with a TOTP or recovery code.
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required" validate:"required"`
+	Code     string `json:"code" binding:"required" validate:"required"`
+
+	Client ClientInfo `json:"-"`
+}
+
+// OIDCLoginResponse starts a single sign-on login. The client opens
+// AuthorizationURL; the provider redirects back to the callback endpoint.
+// The state is also set as an HttpOnly cookie, which the callback requires.
+type OIDCLoginResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+}
+
+// OIDCCallbackRequest carries the authorization response of the provider.
+type OIDCCallbackRequest struct {
+	Code             string `form:"code"`
+	State            string `form:"state"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+
+	BrowserState string     `form:"-"` // State bound to the browser by the login cookie
+	Client       ClientInfo `form:"-"`
+}
+
+// SessionResponse describes one logged-in device of the current user.
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // Session of the token used for this request
 }
@@ -28,6 +28,7 @@ func (h *Handler) Register(c *gin.Context) {
 		return
 	}
 
+	req.Client = clientInfo(c)
 	resp, err := h.service.Register(c.Request.Context(), req)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
@@ -49,6 +50,7 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
+	req.Client = clientInfo(c)
 	resp, err := h.service.Login(c.Request.Context(), req)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
@@ -70,7 +72,8 @@ func (h *Handler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.service.RefreshToken(c.Request.Context(), req.RefreshToken)
+	req.Client = clientInfo(c)
+	resp, err := h.service.RefreshToken(c.Request.Context(), req)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			response.AppError(c, appErr)
@@ -110,3 +113,322 @@ func (h *Handler) RevokeToken(c *gin.Context) {
 
 	response.Write(c, http.StatusOK, gin.H{"message": "token revoked successfully"})
 }
+
+// ListSessions handles GET /auth/sessions
+func (h *Handler) ListSessions(c *gin.Context) {
+	claims, ok := h.authenticatedClaims(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.ListSessions(c.Request.Context(), claims.Subject, claims.SessionID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, gin.H{"data": resp})
+}
+
+// RevokeSession handles DELETE /auth/sessions/:id
+func (h *Handler) RevokeSession(c *gin.Context) {
+	email, ok := h.authenticatedEmail(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RevokeSession(c.Request.Context(), email, c.Param("id")); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, gin.H{"message": "session revoked successfully"})
+}
+
+// LogoutAll handles POST /auth/logout-all
+func (h *Handler) LogoutAll(c *gin.Context) {
+	email, ok := h.authenticatedEmail(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.LogoutAll(c.Request.Context(), email); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, gin.H{"message": "all sessions revoked successfully"})
+}
+
+// ForgotPassword handles POST /auth/password/forgot
+func (h *Handler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+		return
+	}
+
+	if err := h.service.ForgotPassword(c.Request.Context(), req); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, gin.H{"message": "if the email is registered, a password reset link has been sent"})
+}
+
+// ResetPassword handles POST /auth/password/reset
+func (h *Handler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+		return
+	}
+
+	if err := h.service.ResetPassword(c.Request.Context(), req); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, gin.H{"message": "password has been reset"})
+}
+
+// ChangePassword handles PUT /api/v1/me/password
+func (h *Handler) ChangePassword(c *gin.Context) {
+	claims, ok := h.authenticatedClaims(c)
+	if !ok {
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+		return
+	}
+
+	if err := h.service.ChangePassword(c.Request.Context(), claims.Subject, claims.SessionID, req); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, gin.H{"message": "password changed successfully"})
+}
+
+// VerifyEmail handles POST /auth/verify
+func (h *Handler) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+		return
+	}
+
+	if err := h.service.VerifyEmail(c.Request.Context(), req); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, gin.H{"message": "email verified successfully"})
+}
+
+// ResendVerification handles POST /auth/verify/resend
+func (h *Handler) ResendVerification(c *gin.Context) {
+	var req ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+		return
+	}
+
+	if err := h.service.ResendVerification(c.Request.Context(), req); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, gin.H{"message": "if the email is registered and not yet verified, a verification link has been sent"})
+}
+
+// EnrollMFA handles POST /auth/mfa/enroll
+func (h *Handler) EnrollMFA(c *gin.Context) {
+	email, ok := h.authenticatedEmail(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.EnrollMFA(c.Request.Context(), email)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
+
+// ConfirmMFA handles POST /auth/mfa/confirm
+func (h *Handler) ConfirmMFA(c *gin.Context) {
+	email, ok := h.authenticatedEmail(c)
+	if !ok {
+		return
+	}
+
+	var req MFAConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+		return
+	}
+
+	resp, err := h.service.ConfirmMFA(c.Request.Context(), email, req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
+
+// VerifyMFA handles POST /auth/mfa/verify
+func (h *Handler) VerifyMFA(c *gin.Context) {
+	var req MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+		return
+	}
+
+	req.Client = clientInfo(c)
+	resp, err := h.service.VerifyMFA(c.Request.Context(), req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusUnauthorized, errors.ErrCodeUnauthorized, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
+
+// StartOIDCLogin handles GET /auth/oidc/login
+func (h *Handler) StartOIDCLogin(c *gin.Context) {
+	resp, err := h.service.StartOIDCLogin(c.Request.Context())
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	setOIDCStateCookie(c, resp.State, int(oidcStateExpiration.Seconds()))
+	response.Write(c, http.StatusOK, resp)
+}
+
+// OIDCCallback handles GET /auth/oidc/callback
+func (h *Handler) OIDCCallback(c *gin.Context) {
+	var req OIDCCallbackRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid query parameters")
+		return
+	}
+
+	req.BrowserState, _ = c.Cookie(oidcStateCookie)
+	req.Client = clientInfo(c)
+	setOIDCStateCookie(c, "", -1)
+	resp, err := h.service.CompleteOIDCLogin(c.Request.Context(), req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusUnauthorized, errors.ErrCodeUnauthorized, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
+
+// JWKS handles GET /.well-known/jwks.json
+func (h *Handler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	response.Write(c, http.StatusOK, h.service.JWKS())
+}
+
+// authenticatedEmail returns the subject of the access token checked by
+// middleware.WithAuth. On failure the error response is already written.
+func (h *Handler) authenticatedEmail(c *gin.Context) (string, bool) {
+	claims, ok := h.authenticatedClaims(c)
+	if !ok {
+		return "", false
+	}
+	return claims.Subject, true
+}
+
+// authenticatedClaims is authenticatedEmail for handlers that also need the
+// session of the token. Routes using it must be registered behind middleware.WithAuth.
+func (h *Handler) authenticatedClaims(c *gin.Context) (*Claims, bool) {
+	claims, ok := ClaimsFromContext(c)
+	if !ok {
+		response.ErrorWithCode(c, http.StatusUnauthorized, errors.ErrCodeUnauthorized, "authentication required")
+		return nil, false
+	}
+	return claims, true
+}
+
+// setOIDCStateCookie binds a single sign-on login to the browser that started
+// it; maxAge -1 deletes the cookie. Over HTTPS the cookie is SameSite=None so
+// it is also stored when the login is started from the app's own origin.
+func setOIDCStateCookie(c *gin.Context, state string, maxAge int) {
+	secure := c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https")
+	sameSite := http.SameSiteLaxMode
+	if secure {
+		sameSite = http.SameSiteNoneMode
+	}
+	c.SetSameSite(sameSite)
+	c.SetCookie(oidcStateCookie, state, maxAge, "/", "", secure, true)
+}
+
+// clientInfo collects device information used to describe the session.
+func clientInfo(c *gin.Context) ClientInfo {
+	return ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
@@ -1,6 +1,10 @@
 package auth
 
-import "fmt"
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
 
 func extractInvGateUserID(resp map[string]interface{}) (int, error) {
 	if resp == nil {
@@ -24,3 +28,11 @@ func extractInvGateUserID(resp map[string]interface{}) (int, error) {
 	}
 }
 
+// newTokenID returns a random identifier used as the JWT "jti" claim.
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
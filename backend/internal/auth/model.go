@@ -0,0 +1,15 @@
+package auth
+
+import "time"
+
+// RevokedToken is a revoked JWT persisted by the MySQL blacklist backend.
+type RevokedToken struct {
+	TokenID   string    `gorm:"size:64;primaryKey"` // JWT "jti" claim
+	ExpiresAt time.Time `gorm:"not null;index"`     // Row can be deleted after this time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
@@ -3,44 +3,79 @@ package auth
 import (
 	"context"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/sirupsen/logrus"
 
 	"werk-ticketing/internal/invgate"
+	"werk-ticketing/internal/jwtkeys"
+	"werk-ticketing/internal/loginattempt"
+	"werk-ticketing/internal/mailer"
+	"werk-ticketing/internal/oidc"
+	"werk-ticketing/internal/registration"
+	"werk-ticketing/internal/session"
 	"werk-ticketing/internal/user"
+	"werk-ticketing/internal/usertoken"
 )
 
 // Service exposes authentication related use cases.
 type Service interface {
 	Register(ctx context.Context, req RegisterRequest) (*AuthResponse, error)
 	Login(ctx context.Context, req LoginRequest) (*AuthResponse, error)
-	RefreshToken(ctx context.Context, refreshToken string) (*AuthResponse, error)
+	RefreshToken(ctx context.Context, req RefreshTokenRequest) (*AuthResponse, error)
 	RevokeToken(ctx context.Context, token string) error
-	ParseToken(token string) (*jwt.RegisteredClaims, error)
-	IsTokenBlacklisted(token string) bool
+	ListSessions(ctx context.Context, email, currentSessionID string) ([]SessionResponse, error)
+	RevokeSession(ctx context.Context, email, sessionID string) error
+	LogoutAll(ctx context.Context, email string) error
+	RevokeUserSessions(ctx context.Context, userID, reason string) error
+	AssignDefaultScopes(ctx context.Context, u *user.User) error
+	ParseToken(ctx context.Context, token string) (*Claims, error)
+	ForgotPassword(ctx context.Context, req ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req ResetPasswordRequest) error
+	ChangePassword(ctx context.Context, email, currentSessionID string, req ChangePasswordRequest) error
+	VerifyEmail(ctx context.Context, req VerifyEmailRequest) error
+	ResendVerification(ctx context.Context, req ResendVerificationRequest) error
+	EnrollMFA(ctx context.Context, email string) (*MFAEnrollResponse, error)
+	ConfirmMFA(ctx context.Context, email string, req MFAConfirmRequest) (*MFARecoveryCodesResponse, error)
+	VerifyMFA(ctx context.Context, req MFAVerifyRequest) (*AuthResponse, error)
+	StartOIDCLogin(ctx context.Context) (*OIDCLoginResponse, error)
+	CompleteOIDCLogin(ctx context.Context, req OIDCCallbackRequest) (*AuthResponse, error)
+	JWKS() jwtkeys.JWKS
 }
 
 type service struct {
-	userRepo      user.Repository
-	invgateClient invgate.Service
-	jwtSecret     []byte
-	blacklist     *TokenBlacklist
-	logger        *logrus.Logger
-	companyID     int
-	groupID       int
-	locationID    int
+	userRepo         user.Repository
+	sessionRepo      session.Repository
+	tokenRepo        usertoken.Repository
+	loginAttemptRepo loginattempt.Repository
+	invgateClient    invgate.Service
+	mailer           mailer.Mailer
+	keys             *jwtkeys.KeySet
+	appBaseURL       string
+	mfaIssuer        string
+	blacklist        TokenBlacklistService
+	logger           *logrus.Logger
+	registration     registration.Service
+	deferScopes      bool           // assign default InvGate scopes on email verification instead of registration
+	oidcProvider     *oidc.Provider // nil when single sign-on is disabled
+	oidcStateRepo    oidc.StateRepository
 }
 
 // NewService instantiates auth service.
-func NewService(repo user.Repository, invgateClient invgate.Service, jwtSecret string, logger *logrus.Logger, companyID, groupID, locationID int) Service {
+func NewService(repo user.Repository, sessionRepo session.Repository, tokenRepo usertoken.Repository, loginAttemptRepo loginattempt.Repository, blacklist TokenBlacklistService, invgateClient invgate.Service, mail mailer.Mailer, keys *jwtkeys.KeySet, appBaseURL, mfaIssuer string, logger *logrus.Logger, registrationService registration.Service, deferScopes bool, oidcProvider *oidc.Provider, oidcStateRepo oidc.StateRepository) Service {
 	return &service{
-		userRepo:      repo,
-		invgateClient: invgateClient,
-		jwtSecret:     []byte(jwtSecret),
-		blacklist:     NewTokenBlacklist(),
-		logger:        logger,
-		companyID:     companyID,
-		groupID:       groupID,
-		locationID:    locationID,
+		userRepo:         repo,
+		sessionRepo:      sessionRepo,
+		tokenRepo:        tokenRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		invgateClient:    invgateClient,
+		mailer:           mail,
+		keys:             keys,
+		appBaseURL:       appBaseURL,
+		mfaIssuer:        mfaIssuer,
+		blacklist:        blacklist,
+		logger:           logger,
+		registration:     registrationService,
+		deferScopes:      deferScopes,
+		oidcProvider:     oidcProvider,
+		oidcStateRepo:    oidcStateRepo,
 	}
 }
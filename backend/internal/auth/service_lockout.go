@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/constants"
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/loginattempt"
+)
+
+// checkLoginAllowed rejects a login while the email is locked or while the
+// email or client IP is still in its backoff period. Storage failures are
+// logged and do not block the login.
+func (s *service) checkLoginAllowed(ctx context.Context, tenantID, email, ipAddress string) error {
+	now := time.Now().UTC()
+
+	for _, scope := range loginAttemptScopes(tenantID, email, ipAddress) {
+		attempt, err := s.loginAttemptRepo.Get(ctx, scope.scope, scope.key)
+		if err != nil {
+			s.logger.WithError(err).WithField("scope", scope.scope).Error("failed to get login attempts")
+			continue
+		}
+		if attempt == nil || isStaleAttempt(attempt, now) {
+			continue
+		}
+
+		if attempt.IsLocked(now) {
+			return errors.NewAppError(
+				errors.ErrCodeAccountLocked,
+				fmt.Sprintf("account is temporarily locked due to too many failed login attempts, try again in %s", formatRetryAfter(attempt.RetryAfter(now))),
+				nil,
+			)
+		}
+		if wait := attempt.RetryAfter(now); wait > 0 {
+			return errors.NewAppError(
+				errors.ErrCodeTooManyAttempts,
+				fmt.Sprintf("too many failed login attempts, try again in %s", formatRetryAfter(wait)),
+				nil,
+			)
+		}
+	}
+
+	return nil
+}
+
+// recordLoginFailure counts a failed login for the email and the client IP,
+// applying backoff and locking the email once the threshold is reached.
+// The counter is incremented in the database, so concurrent failures are all
+// counted, and backoff and lock are derived from the count it returns.
+func (s *service) recordLoginFailure(ctx context.Context, tenantID, email, ipAddress string) {
+	now := time.Now().UTC()
+
+	for _, scope := range loginAttemptScopes(tenantID, email, ipAddress) {
+		failures, err := s.loginAttemptRepo.RecordFailure(ctx, scope.scope, scope.key, now, now.Add(-constants.LoginAttemptWindow))
+		if err != nil {
+			s.logger.WithError(err).WithField("scope", scope.scope).Error("failed to record failed login attempt")
+			continue
+		}
+
+		var nextAttemptAt, lockedUntil *time.Time
+		threshold := constants.LoginBackoffThreshold
+		if scope.scope == loginattempt.ScopeIP {
+			threshold = constants.LoginIPBackoffThreshold
+		}
+		if failures >= threshold {
+			next := now.Add(backoffDelay(failures - threshold))
+			nextAttemptAt = &next
+		}
+		if scope.scope == loginattempt.ScopeEmail && failures >= constants.LoginLockThreshold {
+			until := now.Add(constants.LoginLockDuration)
+			lockedUntil = &until
+			s.logger.WithFields(logrus.Fields{
+				"tenantID":  tenantID,
+				"email":     email,
+				"ipAddress": ipAddress,
+				"failures":  failures,
+			}).Warn("account locked after too many failed login attempts")
+		}
+		if nextAttemptAt == nil && lockedUntil == nil {
+			continue
+		}
+
+		if err := s.loginAttemptRepo.SetBackoff(ctx, scope.scope, scope.key, failures, nextAttemptAt, lockedUntil); err != nil {
+			s.logger.WithError(err).WithField("scope", scope.scope).Error("failed to store login backoff")
+		}
+	}
+}
+
+// clearLoginFailures resets the failure counter of an email after a successful login.
+// The IP counter is kept so a single valid account cannot reset it.
+func (s *service) clearLoginFailures(ctx context.Context, tenantID, email string) {
+	if err := s.loginAttemptRepo.Delete(ctx, loginattempt.ScopeEmail, loginattempt.EmailKey(tenantID, email)); err != nil {
+		s.logger.WithError(err).Error("failed to clear login attempts")
+	}
+}
+
+type loginAttemptScope struct {
+	scope string
+	key   string
+}
+
+func loginAttemptScopes(tenantID, email, ipAddress string) []loginAttemptScope {
+	scopes := []loginAttemptScope{{scope: loginattempt.ScopeEmail, key: loginattempt.EmailKey(tenantID, email)}}
+	if ipAddress != "" {
+		scopes = append(scopes, loginAttemptScope{scope: loginattempt.ScopeIP, key: ipAddress})
+	}
+	return scopes
+}
+
+// isStaleAttempt reports whether a record should start over: either the last
+// failure is outside the tracking window or an earlier lock has expired.
+func isStaleAttempt(attempt *loginattempt.LoginAttempt, now time.Time) bool {
+	if now.Sub(attempt.LastFailureAt) > constants.LoginAttemptWindow {
+		return true
+	}
+	return attempt.LockedUntil != nil && !now.Before(*attempt.LockedUntil)
+}
+
+// backoffDelay returns the delay for the n-th failure past the backoff threshold.
+func backoffDelay(n int) time.Duration {
+	delay := float64(constants.LoginBackoffBase) * math.Pow(2, float64(n))
+	if delay > float64(constants.LoginBackoffMax) {
+		return constants.LoginBackoffMax
+	}
+	return time.Duration(delay)
+}
+
+// formatRetryAfter renders a wait time rounded up to whole seconds.
+func formatRetryAfter(d time.Duration) string {
+	return (d + time.Second - 1).Truncate(time.Second).String()
+}
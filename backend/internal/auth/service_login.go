@@ -6,6 +6,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/tenant"
 	"werk-ticketing/internal/validator"
 )
 
@@ -26,7 +27,11 @@ func (s *service) Login(ctx context.Context, req LoginRequest) (*AuthResponse, e
 		)
 	}
 
-	existing, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err := s.checkLoginAllowed(ctx, tenant.IDFromContext(ctx), req.Email, req.Client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.userRepo.GetByEmail(ctx, tenant.IDFromContext(ctx), req.Email)
 	if err != nil {
 		s.logger.WithError(err).Error("failed to get user")
 		return nil, errors.NewAppError(
@@ -37,6 +42,7 @@ func (s *service) Login(ctx context.Context, req LoginRequest) (*AuthResponse, e
 	}
 	if existing == nil {
 		s.logger.Warn("login attempt with non-existent email")
+		s.recordLoginFailure(ctx, tenant.IDFromContext(ctx), req.Email, req.Client.IPAddress)
 		return nil, errors.NewAppError(
 			errors.ErrCodeInvalidCredentials,
 			"invalid credentials",
@@ -46,6 +52,7 @@ func (s *service) Login(ctx context.Context, req LoginRequest) (*AuthResponse, e
 
 	if err := bcrypt.CompareHashAndPassword([]byte(existing.Password), []byte(req.Password)); err != nil {
 		s.logger.Warn("login attempt with invalid password")
+		s.recordLoginFailure(ctx, tenant.IDFromContext(ctx), req.Email, req.Client.IPAddress)
 		return nil, errors.NewAppError(
 			errors.ErrCodeInvalidCredentials,
 			"invalid credentials",
@@ -53,33 +60,24 @@ func (s *service) Login(ctx context.Context, req LoginRequest) (*AuthResponse, e
 		)
 	}
 
-	token, err := s.buildToken(existing)
-	if err != nil {
-		s.logger.WithError(err).Error("failed to generate token")
-		return nil, errors.NewAppError(
-			errors.ErrCodeInternal,
-			"failed to generate token",
-			err,
-		)
+	if err := ensureActive(existing); err != nil {
+		s.logger.WithField("userID", existing.ID).Warn("login attempt on deactivated account")
+		return nil, err
 	}
 
-	refreshToken, err := s.buildRefreshToken(existing)
+	if existing.IsMFAEnabled() {
+		// Failures are only cleared once the second factor was verified
+		return s.issueMFAChallenge(existing)
+	}
+
+	s.clearLoginFailures(ctx, tenant.IDFromContext(ctx), req.Email)
+
+	resp, err := s.issueTokens(ctx, existing, req.Client)
 	if err != nil {
-		s.logger.WithError(err).Error("failed to generate refresh token")
-		return nil, errors.NewAppError(
-			errors.ErrCodeInternal,
-			"failed to generate refresh token",
-			err,
-		)
+		return nil, err
 	}
 
 	s.logger.Info("user logged in successfully")
 
-	return &AuthResponse{
-		Token:        token,
-		RefreshToken: refreshToken,
-		Name:         existing.Name,
-		LastName:     existing.LastName,
-		Email:        existing.Email,
-	}, nil
+	return resp, nil
 }
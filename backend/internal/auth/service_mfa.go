@@ -0,0 +1,332 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"werk-ticketing/internal/constants"
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/tenant"
+	"werk-ticketing/internal/totp"
+	"werk-ticketing/internal/user"
+)
+
+// EnrollMFA generates a new TOTP secret for the user. Two-factor authentication
+// is only enabled after the secret was confirmed with ConfirmMFA.
+func (s *service) EnrollMFA(ctx context.Context, email string) (*MFAEnrollResponse, error) {
+	u, err := s.getUserForMFA(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if u.IsMFAEnabled() {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"two-factor authentication is already enabled",
+			nil,
+		)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		s.logger.WithError(err).Error("failed to generate totp secret")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to start two-factor enrollment",
+			err,
+		)
+	}
+
+	if err := s.userRepo.SetMFASecret(ctx, u.ID, secret); err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to store totp secret")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to start two-factor enrollment",
+			err,
+		)
+	}
+
+	s.logger.WithField("userID", u.ID).Info("two-factor enrollment started")
+
+	return &MFAEnrollResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(s.mfaIssuer, u.Email, secret),
+	}, nil
+}
+
+// ConfirmMFA enables two-factor authentication once the user proved the
+// authenticator app works, and returns freshly generated recovery codes.
+func (s *service) ConfirmMFA(ctx context.Context, email string, req MFAConfirmRequest) (*MFARecoveryCodesResponse, error) {
+	u, err := s.getUserForMFA(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if u.IsMFAEnabled() {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"two-factor authentication is already enabled",
+			nil,
+		)
+	}
+	if u.MFASecret == "" {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"two-factor enrollment has not been started",
+			nil,
+		)
+	}
+
+	now := time.Now().UTC()
+	step, ok := totp.Validate(u.MFASecret, req.Code, now, constants.MFAClockSkewSteps)
+	if !ok {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"invalid authentication code",
+			nil,
+		)
+	}
+
+	codes, hashes, err := generateRecoveryCodes(constants.MFARecoveryCodeCount)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to generate recovery codes")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to enable two-factor authentication",
+			err,
+		)
+	}
+
+	if err := s.userRepo.EnableMFA(ctx, u.ID, strings.Join(hashes, ","), step, now); err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to enable two-factor authentication")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to enable two-factor authentication",
+			err,
+		)
+	}
+
+	s.logger.WithField("userID", u.ID).Info("two-factor authentication enabled")
+
+	return &MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// VerifyMFA completes a login started with a password by checking a TOTP or
+// recovery code against the challenge token. The challenge token is single-use.
+func (s *service) VerifyMFA(ctx context.Context, req MFAVerifyRequest) (*AuthResponse, error) {
+	claims, err := s.parseToken(ctx, req.MFAToken, TokenTypeMFAChallenge)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkLoginAllowed(ctx, claims.TenantID, claims.Subject, req.Client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	u, err := s.userRepo.GetByEmail(ctx, claims.TenantID, claims.Subject)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get user")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to authenticate",
+			err,
+		)
+	}
+	if u == nil || !u.IsMFAEnabled() {
+		return nil, errors.NewAppError(
+			errors.ErrCodeUnauthorized,
+			"invalid token",
+			nil,
+		)
+	}
+
+	if err := ensureActive(u); err != nil {
+		return nil, err
+	}
+
+	valid, err := s.verifyMFACode(ctx, u, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		s.logger.Warn("login attempt with invalid two-factor code")
+		s.recordLoginFailure(ctx, u.TenantID, u.Email, req.Client.IPAddress)
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidCredentials,
+			"invalid authentication code",
+			nil,
+		)
+	}
+
+	expiresAt := time.Now().Add(constants.MFAChallengeExpiration)
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+	if err := s.blacklist.Add(ctx, claims.ID, expiresAt); err != nil {
+		s.logger.WithError(err).Error("failed to revoke mfa challenge token")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to authenticate",
+			err,
+		)
+	}
+
+	s.clearLoginFailures(ctx, u.TenantID, u.Email)
+
+	resp, err := s.issueTokens(ctx, u, req.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("user logged in successfully with two-factor authentication")
+
+	return resp, nil
+}
+
+// issueMFAChallenge returns the response for a correct password when a second factor is required.
+func (s *service) issueMFAChallenge(u *user.User) (*AuthResponse, error) {
+	tokenID, err := newTokenID()
+	if err != nil {
+		s.logger.WithError(err).Error("failed to generate mfa challenge token id")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to generate token",
+			err,
+		)
+	}
+
+	claims := Claims{
+		Type:     TokenTypeMFAChallenge,
+		TenantID: u.TenantID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Subject:   u.Email,
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(constants.MFAChallengeExpiration)),
+		},
+	}
+
+	token, err := s.keys.Sign(claims)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to sign mfa challenge token")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to generate token",
+			err,
+		)
+	}
+
+	s.logger.Info("password accepted, two-factor authentication required")
+
+	return &AuthResponse{
+		Name:          u.Name,
+		LastName:      u.LastName,
+		Email:         u.Email,
+		Role:          u.Role,
+		EmailVerified: u.IsEmailVerified(),
+		MFARequired:   true,
+		MFAToken:      token,
+	}, nil
+}
+
+// verifyMFACode accepts either a current TOTP code or an unused recovery code.
+func (s *service) verifyMFACode(ctx context.Context, u *user.User, code string) (bool, error) {
+	if step, ok := totp.Validate(u.MFASecret, code, time.Now().UTC(), constants.MFAClockSkewSteps); ok {
+		consumed, err := s.userRepo.ConsumeMFAStep(ctx, u.ID, step)
+		if err != nil {
+			s.logger.WithError(err).WithField("userID", u.ID).Error("failed to record totp usage")
+			return false, errors.NewAppError(
+				errors.ErrCodeInternal,
+				"failed to authenticate",
+				err,
+			)
+		}
+		return consumed, nil
+	}
+
+	remaining, found := removeRecoveryCode(u.MFARecoveryCodes, code)
+	if !found {
+		return false, nil
+	}
+
+	consumed, err := s.userRepo.ConsumeMFARecoveryCode(ctx, u.ID, u.MFARecoveryCodes, remaining)
+	if err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to consume recovery code")
+		return false, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to authenticate",
+			err,
+		)
+	}
+	if consumed {
+		s.logger.WithField("userID", u.ID).Warn("recovery code used for two-factor authentication")
+	}
+	return consumed, nil
+}
+
+func (s *service) getUserForMFA(ctx context.Context, email string) (*user.User, error) {
+	u, err := s.userRepo.GetByEmail(ctx, tenant.IDFromContext(ctx), email)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get user")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to retrieve user information",
+			err,
+		)
+	}
+	if u == nil {
+		return nil, errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"user not found",
+			nil,
+		)
+	}
+	return u, nil
+}
+
+// generateRecoveryCodes returns codes formatted as xxxxx-xxxxx together with their hashes.
+func generateRecoveryCodes(n int) ([]string, []string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, 0, n)
+	hashes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		b := make([]byte, 6)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(encoding.EncodeToString(b))[:10]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashRecoveryCode(raw))
+	}
+	return codes, hashes, nil
+}
+
+// removeRecoveryCode looks up code in the stored hash list and returns the
+// list without it.
+func removeRecoveryCode(stored, code string) (string, bool) {
+	if stored == "" {
+		return stored, false
+	}
+
+	target := hashRecoveryCode(code)
+	hashes := strings.Split(stored, ",")
+	for i, h := range hashes {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(target)) == 1 {
+			remaining := append(hashes[:i:i], hashes[i+1:]...)
+			return strings.Join(remaining, ","), true
+		}
+	}
+	return stored, false
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
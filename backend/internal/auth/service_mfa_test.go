@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/totp"
+	"werk-ticketing/internal/user"
+)
+
+// mfaUserRepo mirrors the conditional update of the last used TOTP step.
+type mfaUserRepo struct {
+	user.Repository
+	lastStep int64
+}
+
+func (r *mfaUserRepo) ConsumeMFAStep(_ context.Context, _ string, step int64) (bool, error) {
+	if step <= r.lastStep {
+		return false, nil
+	}
+	r.lastStep = step
+	return true, nil
+}
+
+func TestVerifyMFACodeRejectsReplay(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	code, err := totp.Code(secret, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := &service{userRepo: &mfaUserRepo{}, logger: logger}
+	u := &user.User{ID: "user-1", MFASecret: secret}
+
+	ok, err := s.verifyMFACode(context.Background(), u, code)
+	if err != nil || !ok {
+		t.Fatalf("first use = %v, %v; want accepted", ok, err)
+	}
+	ok, err = s.verifyMFACode(context.Background(), u, code)
+	if err != nil || ok {
+		t.Fatalf("replay = %v, %v; want rejected", ok, err)
+	}
+}
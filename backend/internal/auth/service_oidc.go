@@ -0,0 +1,408 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/invgate"
+	"werk-ticketing/internal/oidc"
+	"werk-ticketing/internal/tenant"
+	"werk-ticketing/internal/user"
+)
+
+const (
+	oidcStateExpiration = 10 * time.Minute
+
+	// oidcStateCookie holds the state of the login started by a browser
+	oidcStateCookie = "oidc_state"
+)
+
+// StartOIDCLogin creates the state, nonce and PKCE verifier for a new
+// authorization request and returns the provider URL to open.
+func (s *service) StartOIDCLogin(ctx context.Context) (*OIDCLoginResponse, error) {
+	if s.oidcProvider == nil {
+		return nil, errOIDCDisabled()
+	}
+
+	if _, err := s.oidcStateRepo.DeleteExpired(ctx, time.Now().UTC()); err != nil {
+		s.logger.WithError(err).Warn("failed to delete expired oidc login states")
+	}
+
+	state, errState := oidc.RandomString()
+	nonce, errNonce := oidc.RandomString()
+	verifier, errVerifier := oidc.RandomString()
+	if errState != nil || errNonce != nil || errVerifier != nil {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to start single sign-on",
+			nil,
+		)
+	}
+
+	authURL, err := s.oidcProvider.AuthCodeURL(ctx, state, nonce, oidc.CodeChallengeS256(verifier))
+	if err != nil {
+		s.logger.WithError(err).Error("failed to build oidc authorization url")
+		return nil, errors.NewAppError(
+			errors.ErrCodeExternalService,
+			"failed to reach identity provider",
+			err,
+		)
+	}
+
+	if err := s.oidcStateRepo.Create(ctx, &oidc.LoginState{
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().UTC().Add(oidcStateExpiration),
+	}); err != nil {
+		s.logger.WithError(err).Error("failed to store oidc login state")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to start single sign-on",
+			err,
+		)
+	}
+
+	return &OIDCLoginResponse{
+		AuthorizationURL: authURL,
+		State:            state,
+	}, nil
+}
+
+// CompleteOIDCLogin exchanges the authorization code, validates the ID token
+// and signs the matching local user in, provisioning it on first login.
+// Two-factor authentication is left to the identity provider.
+func (s *service) CompleteOIDCLogin(ctx context.Context, req OIDCCallbackRequest) (*AuthResponse, error) {
+	if s.oidcProvider == nil {
+		return nil, errOIDCDisabled()
+	}
+
+	if req.Error != "" {
+		s.logger.WithField("error", req.Error).WithField("description", req.ErrorDescription).Warn("identity provider returned an error")
+		return nil, errors.NewAppError(
+			errors.ErrCodeUnauthorized,
+			"single sign-on failed: "+req.Error,
+			nil,
+		)
+	}
+	if req.Code == "" || req.State == "" {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"code and state are required",
+			nil,
+		)
+	}
+	// The state must come back to the browser that started the login, else
+	// an attacker could get a victim signed in to the attacker's account
+	if subtle.ConstantTimeCompare([]byte(req.State), []byte(req.BrowserState)) != 1 {
+		s.logger.Warn("oidc callback state does not match the browser's login")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"invalid or expired login state",
+			nil,
+		)
+	}
+
+	loginState, err := s.oidcStateRepo.Consume(ctx, req.State)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to load oidc login state")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to complete single sign-on",
+			err,
+		)
+	}
+	if loginState == nil || time.Now().UTC().After(loginState.ExpiresAt) {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"invalid or expired login state",
+			nil,
+		)
+	}
+
+	tokens, err := s.oidcProvider.Exchange(ctx, req.Code, loginState.CodeVerifier)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to exchange oidc authorization code")
+		return nil, errors.NewAppError(
+			errors.ErrCodeUnauthorized,
+			"failed to exchange authorization code",
+			err,
+		)
+	}
+
+	claims, err := s.oidcProvider.VerifyIDToken(ctx, tokens.IDToken, loginState.Nonce)
+	if err != nil {
+		s.logger.WithError(err).Warn("invalid oidc id token")
+		return nil, errors.NewAppError(
+			errors.ErrCodeUnauthorized,
+			"invalid identity token",
+			err,
+		)
+	}
+
+	if claims.Email == "" {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"identity provider did not return an email address",
+			nil,
+		)
+	}
+	if claims.EmailVerified != nil && !*claims.EmailVerified {
+		return nil, errors.NewAppError(
+			errors.ErrCodeForbidden,
+			"email address is not verified by the identity provider",
+			nil,
+		)
+	}
+
+	u, err := s.resolveOIDCUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if !u.IsEmailVerified() && oidcEmailVerified(claims) {
+		now := time.Now().UTC()
+		if err := s.userRepo.MarkEmailVerified(ctx, u.ID, now); err != nil {
+			s.logger.WithError(err).WithField("userID", u.ID).Warn("failed to mark email as verified after sso login")
+		} else {
+			u.EmailVerifiedAt = &now
+		}
+	}
+
+	resp, err := s.issueTokens(ctx, u, req.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithField("userID", u.ID).Info("user logged in with single sign-on")
+	return resp, nil
+}
+
+// resolveOIDCUser finds the local user for the SSO account: first by subject,
+// then by email (linking the account), otherwise a new user is provisioned.
+func (s *service) resolveOIDCUser(ctx context.Context, claims *oidc.Claims) (*user.User, error) {
+	u, err := s.userRepo.GetByOIDCSubject(ctx, claims.Subject)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get user by oidc subject")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to complete single sign-on",
+			err,
+		)
+	}
+	if u != nil {
+		return u, nil
+	}
+
+	// Matching by email hands over an existing account, so the provider has
+	// to vouch for the address. A missing claim is not enough.
+	if !oidcEmailVerified(claims) {
+		return nil, errors.NewAppError(
+			errors.ErrCodeForbidden,
+			"email address is not verified by the identity provider",
+			nil,
+		)
+	}
+
+	u, err = s.userRepo.GetByEmail(ctx, tenant.IDFromContext(ctx), claims.Email)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get user")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to complete single sign-on",
+			err,
+		)
+	}
+	if u == nil {
+		return s.provisionOIDCUser(ctx, claims)
+	}
+
+	if u.OIDCSubject != nil && *u.OIDCSubject != claims.Subject {
+		s.logger.WithField("userID", u.ID).Warn("email is already linked to a different sso account")
+		return nil, errors.NewAppError(
+			errors.ErrCodeForbidden,
+			"this email is linked to a different single sign-on account",
+			nil,
+		)
+	}
+
+	if err := s.userRepo.LinkOIDCSubject(ctx, u.ID, claims.Subject); err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to link sso account")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to link single sign-on account",
+			err,
+		)
+	}
+	subject := claims.Subject
+	u.OIDCSubject = &subject
+
+	s.logger.WithField("userID", u.ID).Info("linked existing user to sso account")
+	return u, nil
+}
+
+// provisionOIDCUser creates the InvGate user (or reuses an existing one with
+// the same email), assigns the scope of its email domain and stores the local
+// user. Like registration, emails of unknown domains are rejected.
+// A random password is set; the user can pick a real one via password reset.
+func (s *service) provisionOIDCUser(ctx context.Context, claims *oidc.Claims) (*user.User, error) {
+	scope, ok := s.registration.ScopeForEmail(ctx, claims.Email)
+	if !ok {
+		s.logger.WithField("email", claims.Email).Warn("sso login rejected for email domain not open for registration")
+		return nil, errors.NewAppError(
+			errors.ErrCodeForbidden,
+			"registration is not open for this email domain, ask an administrator for an invite",
+			nil,
+		)
+	}
+
+	name, lastName := oidcNames(claims)
+
+	password, err := oidc.RandomString()
+	if err != nil {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to provision user",
+			err,
+		)
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to hash password")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to provision user",
+			err,
+		)
+	}
+
+	createdInInvGate := false
+	var invGateUserID int
+	if existing, err := s.invgateClient.GetUserByEmail(ctx, claims.Email); err == nil && existing != nil {
+		if id, err := extractInvGateUserID(existing); err == nil && id > 0 {
+			invGateUserID = id
+			s.logger.WithField("invGateUserID", id).Info("reusing existing InvGate user for sso login")
+		}
+	}
+
+	if invGateUserID == 0 {
+		resp, err := s.invgateClient.CreateUser(ctx, invgate.CreateUserPayload{
+			Name:     name,
+			LastName: lastName,
+			Email:    claims.Email,
+			Pass:     password,
+		})
+		if err != nil {
+			s.logger.WithError(err).Error("failed to create user in InvGate")
+			return nil, errors.NewAppError(
+				errors.ErrCodeExternalService,
+				"failed to create user in external service",
+				err,
+			)
+		}
+		invGateUserID, err = extractInvGateUserID(resp)
+		if err != nil {
+			s.logger.WithError(err).WithField("response", resp).Error("failed to extract InvGate user ID")
+			return nil, errors.NewAppError(
+				errors.ErrCodeExternalService,
+				"failed to process external user response",
+				err,
+			)
+		}
+		createdInInvGate = true
+	}
+
+	compensate := func() {
+		if !createdInInvGate {
+			return
+		}
+		if compErr := s.invgateClient.DeleteUser(ctx, invGateUserID); compErr != nil {
+			s.logger.WithError(compErr).
+				WithField("invGateUserID", invGateUserID).
+				Error("compensation failed: could not delete user from InvGate")
+		}
+	}
+
+	if err := s.assignUserToScopes(ctx, invGateUserID, scope); err != nil {
+		s.logger.WithError(err).WithField("invGateUserID", invGateUserID).Error("failed to assign sso user to default InvGate scopes")
+		compensate()
+		return nil, errors.NewAppError(
+			errors.ErrCodeExternalService,
+			"failed to assign user to default configuration",
+			err,
+		)
+	}
+
+	now := time.Now().UTC()
+	subject := claims.Subject
+	newUser := &user.User{
+		Name:            name,
+		LastName:        lastName,
+		Email:           claims.Email,
+		Password:        string(hashed),
+		InvGateUserID:   invGateUserID,
+		Role:            user.RoleRequester,
+		EmailVerifiedAt: &now,
+		OIDCSubject:     &subject,
+		CreatedBy:       claims.Email,
+		UpdatedBy:       claims.Email,
+		TenantID:        tenant.IDFromContext(ctx),
+	}
+	setUserScope(newUser, scope)
+	if err := s.userRepo.Create(ctx, newUser); err != nil {
+		s.logger.WithError(err).WithField("invGateUserID", invGateUserID).Error("failed to create sso user in database")
+		compensate()
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to create user in local database",
+			err,
+		)
+	}
+
+	s.logger.WithField("userID", newUser.ID).Info("provisioned user from sso login")
+	return newUser, nil
+}
+
+// oidcEmailVerified reports whether the provider explicitly confirmed the email.
+func oidcEmailVerified(claims *oidc.Claims) bool {
+	return claims.EmailVerified != nil && *claims.EmailVerified
+}
+
+// oidcNames maps the name claims to first and last name. InvGate requires both.
+func oidcNames(claims *oidc.Claims) (string, string) {
+	name, lastName := claims.GivenName, claims.FamilyName
+	if name == "" && claims.Name != "" {
+		parts := strings.SplitN(strings.TrimSpace(claims.Name), " ", 2)
+		name = parts[0]
+		if len(parts) == 2 && lastName == "" {
+			lastName = parts[1]
+		}
+	}
+	if name == "" {
+		name = strings.SplitN(claims.Email, "@", 2)[0]
+	}
+	if lastName == "" {
+		lastName = name
+	}
+	return truncate(name, 100), truncate(lastName, 100)
+}
+
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max]
+	}
+	return s
+}
+
+func errOIDCDisabled() error {
+	return errors.NewAppError(
+		errors.ErrCodeNotFound,
+		"single sign-on is not configured",
+		nil,
+	)
+}
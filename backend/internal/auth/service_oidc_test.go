@@ -0,0 +1,255 @@
+package auth
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/jwtkeys"
+	"werk-ticketing/internal/oidc"
+	"werk-ticketing/internal/oidc/oidctest"
+	"werk-ticketing/internal/registration"
+	"werk-ticketing/internal/session"
+	"werk-ticketing/internal/user"
+)
+
+// fakeUserRepo keeps users in memory. Methods the SSO flow does not use
+// panic through the nil embedded interface.
+type fakeUserRepo struct {
+	user.Repository
+	users  []*user.User
+	linked map[string]string
+}
+
+func (r *fakeUserRepo) GetByOIDCSubject(_ context.Context, subject string) (*user.User, error) {
+	for _, u := range r.users {
+		if u.OIDCSubject != nil && *u.OIDCSubject == subject {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepo) GetByEmail(_ context.Context, tenantID, email string) (*user.User, error) {
+	for _, u := range r.users {
+		if u.TenantID == tenantID && u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepo) LinkOIDCSubject(_ context.Context, id, subject string) error {
+	r.linked[id] = subject
+	return nil
+}
+
+func (r *fakeUserRepo) MarkEmailVerified(context.Context, string, time.Time) error {
+	return nil
+}
+
+type fakeSessionRepo struct {
+	session.Repository
+}
+
+func (fakeSessionRepo) Create(_ context.Context, sess *session.Session) error {
+	sess.ID = "session-1"
+	return nil
+}
+
+// closedRegistration accepts no email domain.
+type closedRegistration struct {
+	registration.Service
+}
+
+func (closedRegistration) ScopeForEmail(context.Context, string) (registration.Scope, bool) {
+	return registration.Scope{}, false
+}
+
+type memoryStateRepo struct {
+	mu     sync.Mutex
+	states map[string]*oidc.LoginState
+}
+
+func (r *memoryStateRepo) Create(_ context.Context, state *oidc.LoginState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states[state.State] = state
+	return nil
+}
+
+func (r *memoryStateRepo) Consume(_ context.Context, state string) (*oidc.LoginState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.states[state]
+	delete(r.states, state)
+	return s, nil
+}
+
+func (r *memoryStateRepo) DeleteExpired(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+type oidcFixture struct {
+	service  *service
+	provider *oidctest.Provider
+	users    *fakeUserRepo
+}
+
+func newOIDCFixture(t *testing.T) *oidcFixture {
+	t.Helper()
+	provider, err := oidctest.NewProvider("werk")
+	if err != nil {
+		t.Fatalf("start fake provider: %v", err)
+	}
+	t.Cleanup(provider.Close)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	users := &fakeUserRepo{linked: make(map[string]string)}
+	return &oidcFixture{
+		provider: provider,
+		users:    users,
+		service: &service{
+			userRepo:     users,
+			sessionRepo:  fakeSessionRepo{},
+			keys:         jwtkeys.NewHMACKeySet([]byte("test-secret")),
+			logger:       logger,
+			registration: closedRegistration{},
+			oidcProvider: oidc.NewProvider(oidc.Config{
+				IssuerURL:   provider.Issuer,
+				ClientID:    "werk",
+				RedirectURL: "https://app.example.com/api/v1/auth/oidc/callback",
+			}, nil),
+			oidcStateRepo: &memoryStateRepo{states: make(map[string]*oidc.LoginState)},
+		},
+	}
+}
+
+// login starts a login and lets the fake provider authorize it. It returns
+// the callback request as the browser that started the login would send it.
+func (f *oidcFixture) login(t *testing.T, identity oidctest.Identity) OIDCCallbackRequest {
+	t.Helper()
+	f.provider.SetIdentity(identity)
+
+	start, err := f.service.StartOIDCLogin(context.Background())
+	if err != nil {
+		t.Fatalf("StartOIDCLogin: %v", err)
+	}
+	code, state, err := f.provider.Authorize(start.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	if state != start.State {
+		t.Fatalf("provider returned state %q, want %q", state, start.State)
+	}
+	return OIDCCallbackRequest{Code: code, State: state, BrowserState: start.State}
+}
+
+func verified(v bool) *bool {
+	return &v
+}
+
+func wantAppError(t *testing.T, err error, code string) {
+	t.Helper()
+	appErr, ok := err.(*errors.AppError)
+	if !ok {
+		t.Fatalf("error = %v, want app error %s", err, code)
+	}
+	if appErr.Code != code {
+		t.Fatalf("error code = %s (%s), want %s", appErr.Code, appErr.Message, code)
+	}
+}
+
+func TestCompleteOIDCLoginLinksVerifiedEmail(t *testing.T) {
+	f := newOIDCFixture(t)
+	f.users.users = []*user.User{{ID: "user-1", Email: "jane@example.com", Role: user.RoleRequester}}
+
+	req := f.login(t, oidctest.Identity{Subject: "sub-1", Email: "jane@example.com", EmailVerified: verified(true)})
+	resp, err := f.service.CompleteOIDCLogin(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CompleteOIDCLogin: %v", err)
+	}
+	if resp.Token == "" {
+		t.Fatal("no access token issued")
+	}
+	if f.users.linked["user-1"] != "sub-1" {
+		t.Fatalf("linked = %v, want user-1 linked to sub-1", f.users.linked)
+	}
+}
+
+func TestCompleteOIDCLoginRejectsForeignState(t *testing.T) {
+	f := newOIDCFixture(t)
+
+	req := f.login(t, oidctest.Identity{Subject: "sub-1", Email: "jane@example.com", EmailVerified: verified(true)})
+	// An attacker's callback URL opened in a browser that did not start the login
+	req.BrowserState = ""
+	_, err := f.service.CompleteOIDCLogin(context.Background(), req)
+	wantAppError(t, err, errors.ErrCodeInvalidInput)
+
+	req.BrowserState = "another-login"
+	_, err = f.service.CompleteOIDCLogin(context.Background(), req)
+	wantAppError(t, err, errors.ErrCodeInvalidInput)
+}
+
+func TestCompleteOIDCLoginRejectsUnknownOrReusedState(t *testing.T) {
+	f := newOIDCFixture(t)
+	f.users.users = []*user.User{{ID: "user-1", Email: "jane@example.com"}}
+
+	_, err := f.service.CompleteOIDCLogin(context.Background(), OIDCCallbackRequest{
+		Code: "code", State: "unknown", BrowserState: "unknown",
+	})
+	wantAppError(t, err, errors.ErrCodeInvalidInput)
+
+	req := f.login(t, oidctest.Identity{Subject: "sub-1", Email: "jane@example.com", EmailVerified: verified(true)})
+	if _, err := f.service.CompleteOIDCLogin(context.Background(), req); err != nil {
+		t.Fatalf("first CompleteOIDCLogin: %v", err)
+	}
+	_, err = f.service.CompleteOIDCLogin(context.Background(), req)
+	wantAppError(t, err, errors.ErrCodeInvalidInput)
+}
+
+func TestCompleteOIDCLoginRejectsNonceMismatch(t *testing.T) {
+	f := newOIDCFixture(t)
+	f.provider.OverrideNonce("replayed")
+
+	req := f.login(t, oidctest.Identity{Subject: "sub-1", Email: "jane@example.com", EmailVerified: verified(true)})
+	_, err := f.service.CompleteOIDCLogin(context.Background(), req)
+	wantAppError(t, err, errors.ErrCodeUnauthorized)
+}
+
+func TestCompleteOIDCLoginDoesNotLinkUnverifiedEmail(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		verified *bool
+	}{
+		{name: "claim missing"},
+		{name: "claim false", verified: verified(false)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOIDCFixture(t)
+			f.users.users = []*user.User{{ID: "user-1", Email: "jane@example.com"}}
+
+			req := f.login(t, oidctest.Identity{Subject: "sub-1", Email: "jane@example.com", EmailVerified: tt.verified})
+			_, err := f.service.CompleteOIDCLogin(context.Background(), req)
+			wantAppError(t, err, errors.ErrCodeForbidden)
+			if len(f.users.linked) != 0 {
+				t.Fatalf("account linked with unverified email: %v", f.users.linked)
+			}
+		})
+	}
+}
+
+func TestCompleteOIDCLoginRejectsUnknownDomain(t *testing.T) {
+	f := newOIDCFixture(t)
+
+	req := f.login(t, oidctest.Identity{Subject: "sub-1", Email: "eve@elsewhere.example", EmailVerified: verified(true)})
+	_, err := f.service.CompleteOIDCLogin(context.Background(), req)
+	wantAppError(t, err, errors.ErrCodeForbidden)
+}
@@ -0,0 +1,305 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"werk-ticketing/internal/constants"
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/mailer"
+	"werk-ticketing/internal/requestinfo"
+	"werk-ticketing/internal/session"
+	"werk-ticketing/internal/tenant"
+	"werk-ticketing/internal/user"
+	"werk-ticketing/internal/usertoken"
+	"werk-ticketing/internal/validator"
+)
+
+const mailSendTimeout = 30 * time.Second
+
+// ForgotPassword emails a password reset link to the user.
+// It reports success for unknown emails as well, so callers cannot probe which accounts exist.
+func (s *service) ForgotPassword(ctx context.Context, req ForgotPasswordRequest) error {
+	if !validator.ValidateEmail(req.Email) {
+		return errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"invalid email format",
+			nil,
+		)
+	}
+
+	u, err := s.userRepo.GetByEmail(ctx, tenant.IDFromContext(ctx), req.Email)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get user")
+		return errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to process password reset",
+			err,
+		)
+	}
+	if u == nil {
+		s.logger.Warn("password reset requested for non-existent email")
+		return nil
+	}
+
+	plain, err := s.createUserToken(ctx, u, usertoken.PurposePasswordReset, constants.PasswordResetTokenExpiration)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", strings.TrimRight(s.appBaseURL, "/"), url.QueryEscape(plain))
+	s.sendMail(u.Email, mailer.Message{
+		To:      u.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWe received a request to reset your password. Use the link below to choose a new one:\n\n%s\n\nThe link expires in %d minutes and can only be used once. If you did not request a password reset, you can ignore this email.\n",
+			u.Name,
+			link,
+			int(constants.PasswordResetTokenExpiration.Minutes()),
+		),
+	})
+
+	s.logger.WithField("userID", u.ID).Info("password reset token issued")
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token. The password is changed
+// locally and in InvGate, and all existing sessions of the user are revoked.
+func (s *service) ResetPassword(ctx context.Context, req ResetPasswordRequest) error {
+	if !validator.ValidateRequired(req.Token) {
+		return errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"token is required",
+			nil,
+		)
+	}
+	if !validator.ValidatePassword(req.Password) {
+		return errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"password must be at least 6 characters",
+			nil,
+		)
+	}
+
+	token, u, err := s.consumeUserToken(ctx, usertoken.PurposePasswordReset, req.Token)
+	if err != nil {
+		return err
+	}
+
+	if err := s.setPassword(ctx, u, req.Password, u.Email); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Code == errors.ErrCodeExternalService {
+			// The reset token is already used up at this point
+			return errors.NewAppError(appErr.Code, appErr.Message+", please request a new reset link", appErr.Err)
+		}
+		return err
+	}
+
+	if err := s.revokeAllSessions(ctx, u.ID, session.RevokedReasonPasswordReset, ""); err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to revoke sessions after password reset")
+	}
+
+	s.logger.WithField("userID", u.ID).WithField("tokenID", token.ID).Info("password reset completed")
+	return nil
+}
+
+// setPassword changes the password of a user locally and in InvGate. When
+// InvGate rejects the change the previous local hash is restored, so both
+// systems keep accepting the same password.
+func (s *service) setPassword(ctx context.Context, u *user.User, password, updatedBy string) error {
+	ctx, err := s.userContext(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to hash password")
+		return errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to process password",
+			err,
+		)
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, u.ID, string(hashed), updatedBy); err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to update password")
+		return errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to update password",
+			err,
+		)
+	}
+
+	if u.InvGateUserID > 0 {
+		if err := s.invgateClient.UpdateUserPassword(ctx, u.InvGateUserID, password); err != nil {
+			s.logger.WithError(err).WithField("invgateUserID", u.InvGateUserID).Error("failed to update password in InvGate")
+
+			// Keep both systems consistent: restore the previous local hash
+			if rbErr := s.userRepo.UpdatePassword(ctx, u.ID, u.Password, updatedBy); rbErr != nil {
+				s.logger.WithError(rbErr).WithField("userID", u.ID).Error("failed to restore previous password after InvGate failure")
+			}
+
+			return errors.NewAppError(
+				errors.ErrCodeExternalService,
+				"failed to update password in InvGate",
+				err,
+			)
+		}
+	}
+	return nil
+}
+
+// ChangePassword sets a new password after checking the current one. Wrong
+// current passwords count as failed logins for the email and client IP. Every
+// other session of the user is revoked; the session making the request stays logged in.
+func (s *service) ChangePassword(ctx context.Context, email, currentSessionID string, req ChangePasswordRequest) error {
+	if !validator.ValidatePassword(req.NewPassword) {
+		return errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"password must be at least 6 characters",
+			nil,
+		)
+	}
+
+	u, err := s.getSessionOwner(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	// Guessing the current password counts against the same limits as a login
+	ipAddress := requestinfo.FromContext(ctx).IPAddress
+	if err := s.checkLoginAllowed(ctx, u.TenantID, u.Email, ipAddress); err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(req.CurrentPassword)); err != nil {
+		s.logger.WithField("userID", u.ID).Warn("password change with invalid current password")
+		s.recordLoginFailure(ctx, u.TenantID, u.Email, ipAddress)
+		return errors.NewAppError(
+			errors.ErrCodeInvalidCredentials,
+			"current password is incorrect",
+			nil,
+		)
+	}
+	s.clearLoginFailures(ctx, u.TenantID, u.Email)
+
+	if err := s.setPassword(ctx, u, req.NewPassword, u.Email); err != nil {
+		return err
+	}
+
+	if err := s.revokeAllSessions(ctx, u.ID, session.RevokedReasonPasswordChange, currentSessionID); err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to revoke sessions after password change")
+	}
+
+	s.logger.WithField("userID", u.ID).Info("password changed")
+	return nil
+}
+
+// createUserToken issues a new single-use token for the user, invalidating
+// any earlier unused token with the same purpose. It returns the plain token.
+func (s *service) createUserToken(ctx context.Context, u *user.User, purpose string, ttl time.Duration) (string, error) {
+	if err := s.tokenRepo.InvalidateForUser(ctx, u.ID, purpose); err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to invalidate previous user tokens")
+		return "", errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to create token",
+			err,
+		)
+	}
+
+	plain, hash, err := usertoken.Generate()
+	if err != nil {
+		s.logger.WithError(err).Error("failed to generate user token")
+		return "", errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to create token",
+			err,
+		)
+	}
+
+	token := &usertoken.UserToken{
+		UserID:    u.ID,
+		Purpose:   purpose,
+		TokenHash: hash,
+		ExpiresAt: time.Now().UTC().Add(ttl),
+	}
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to store user token")
+		return "", errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to create token",
+			err,
+		)
+	}
+
+	return plain, nil
+}
+
+// consumeUserToken validates a plain token and marks it as used.
+// Unknown, expired and already used tokens are all reported the same way.
+func (s *service) consumeUserToken(ctx context.Context, purpose, plain string) (*usertoken.UserToken, *user.User, error) {
+	invalid := errors.NewAppError(
+		errors.ErrCodeInvalidInput,
+		"invalid or expired token",
+		nil,
+	)
+
+	token, err := s.tokenRepo.GetByHash(ctx, purpose, usertoken.Hash(plain))
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get user token")
+		return nil, nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to verify token",
+			err,
+		)
+	}
+	if token == nil || !token.IsUsable(time.Now().UTC()) {
+		return nil, nil, invalid
+	}
+
+	u, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		s.logger.WithError(err).WithField("userID", token.UserID).Error("failed to get user")
+		return nil, nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to verify token",
+			err,
+		)
+	}
+	if u == nil {
+		return nil, nil, invalid
+	}
+
+	consumed, err := s.tokenRepo.MarkUsed(ctx, token.ID)
+	if err != nil {
+		s.logger.WithError(err).WithField("tokenID", token.ID).Error("failed to mark user token as used")
+		return nil, nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to verify token",
+			err,
+		)
+	}
+	if !consumed {
+		return nil, nil, invalid
+	}
+
+	return token, u, nil
+}
+
+// sendMail delivers an email in the background so the response time does not
+// depend on the mail server, nor reveal whether an email was sent at all.
+func (s *service) sendMail(recipient string, msg mailer.Message) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailSendTimeout)
+		defer cancel()
+
+		if err := s.mailer.Send(ctx, msg); err != nil {
+			s.logger.WithError(err).WithField("to", recipient).Error("failed to send email")
+		}
+	}()
+}
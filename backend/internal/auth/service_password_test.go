@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+
+	"werk-ticketing/internal/constants"
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/loginattempt"
+	"werk-ticketing/internal/requestinfo"
+	"werk-ticketing/internal/user"
+)
+
+// memoryLoginAttempts keeps failure counters in memory, without the window
+// handling of the database repository.
+type memoryLoginAttempts struct {
+	attempts map[string]*loginattempt.LoginAttempt
+}
+
+func (r *memoryLoginAttempts) Get(_ context.Context, scope, key string) (*loginattempt.LoginAttempt, error) {
+	return r.attempts[scope+"|"+key], nil
+}
+
+func (r *memoryLoginAttempts) RecordFailure(_ context.Context, scope, key string, now, _ time.Time) (int, error) {
+	a, ok := r.attempts[scope+"|"+key]
+	if !ok {
+		a = &loginattempt.LoginAttempt{Scope: scope, Key: key}
+		r.attempts[scope+"|"+key] = a
+	}
+	a.Failures++
+	a.LastFailureAt = now
+	return a.Failures, nil
+}
+
+func (r *memoryLoginAttempts) SetBackoff(_ context.Context, scope, key string, _ int, nextAttemptAt, lockedUntil *time.Time) error {
+	a := r.attempts[scope+"|"+key]
+	a.NextAttemptAt = nextAttemptAt
+	a.LockedUntil = lockedUntil
+	return nil
+}
+
+func (r *memoryLoginAttempts) Delete(_ context.Context, scope, key string) error {
+	delete(r.attempts, scope+"|"+key)
+	return nil
+}
+
+func TestChangePasswordCountsWrongCurrentPassword(t *testing.T) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte("secret1"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	attempts := &memoryLoginAttempts{attempts: make(map[string]*loginattempt.LoginAttempt)}
+	s := &service{
+		userRepo:         &fakeUserRepo{users: []*user.User{{ID: "user-1", Email: "jane@example.com", Password: string(hashed)}}},
+		loginAttemptRepo: attempts,
+		logger:           logger,
+	}
+	ctx := requestinfo.WithInfo(context.Background(), requestinfo.Info{IPAddress: "198.51.100.7"})
+	req := ChangePasswordRequest{CurrentPassword: "guess", NewPassword: "new-secret"}
+
+	for i := 0; i < constants.LoginBackoffThreshold; i++ {
+		err := s.ChangePassword(ctx, "jane@example.com", "session-1", req)
+		wantAppError(t, err, errors.ErrCodeInvalidCredentials)
+	}
+	if a := attempts.attempts[loginattempt.ScopeIP+"|198.51.100.7"]; a == nil || a.Failures != constants.LoginBackoffThreshold {
+		t.Fatalf("ip attempts = %+v, want %d failures", a, constants.LoginBackoffThreshold)
+	}
+	emailKey := loginattempt.EmailKey("", "jane@example.com")
+	if a := attempts.attempts[loginattempt.ScopeEmail+"|"+emailKey]; a == nil || a.Failures != constants.LoginBackoffThreshold {
+		t.Fatalf("email attempts = %+v, want %d failures under the tenant key", a, constants.LoginBackoffThreshold)
+	}
+
+	// Past the threshold the next guess is refused before the password is checked,
+	// even with the correct current password
+	req.CurrentPassword = "secret1"
+	err = s.ChangePassword(ctx, "jane@example.com", "session-1", req)
+	wantAppError(t, err, errors.ErrCodeTooManyAttempts)
+}
@@ -10,6 +10,8 @@ import (
 
 	"werk-ticketing/internal/errors"
 	"werk-ticketing/internal/invgate"
+	"werk-ticketing/internal/registration"
+	"werk-ticketing/internal/tenant"
 	"werk-ticketing/internal/user"
 	"werk-ticketing/internal/validator"
 )
@@ -45,7 +47,7 @@ func (s *service) Register(ctx context.Context, req RegisterRequest) (*AuthRespo
 		)
 	}
 
-	existing, err := s.userRepo.GetByEmail(ctx, req.Email)
+	existing, err := s.userRepo.GetByEmail(ctx, tenant.IDFromContext(ctx), req.Email)
 	if err != nil {
 		s.logger.WithError(err).Error("failed to check existing user")
 		return nil, errors.NewAppError(
@@ -72,6 +74,19 @@ func (s *service) Register(ctx context.Context, req RegisterRequest) (*AuthRespo
 		)
 	}
 
+	// Only invited users and allowed email domains may register. A claimed
+	// invite is released again when any later step fails.
+	grant, err := s.registration.Resolve(ctx, req.Email, req.InviteCode)
+	if err != nil {
+		return nil, err
+	}
+	registered := false
+	defer func() {
+		if !registered {
+			s.registration.ReleaseInvite(ctx, grant.InviteID)
+		}
+	}()
+
 	hashed, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
 		s.logger.WithError(err).Error("failed to hash password")
@@ -113,9 +128,12 @@ func (s *service) Register(ctx context.Context, req RegisterRequest) (*AuthRespo
 		Email:         req.Email,
 		Password:      string(hashed),
 		InvGateUserID: invGateUserID,
+		Role:          user.RoleRequester,
 		CreatedBy:     req.Email,
 		UpdatedBy:     req.Email,
+		TenantID:      tenant.IDFromContext(ctx),
 	}
+	setUserScope(newUser, grant.Scope)
 
 	if err := s.userRepo.Create(ctx, newUser); err != nil {
 		var dupKeyErr *user.DuplicateKeyError
@@ -164,7 +182,9 @@ func (s *service) Register(ctx context.Context, req RegisterRequest) (*AuthRespo
 		)
 	}
 
-	if err := s.assignUserToDefaultScopes(ctx, invGateUserID); err != nil {
+	if s.deferScopes {
+		s.logger.WithField("userID", newUser.ID).Info("default InvGate scope assignment deferred until email verification")
+	} else if err := s.assignUserToScopes(ctx, invGateUserID, grant.Scope); err != nil {
 		s.logger.WithError(err).
 			WithField("invGateUserID", invGateUserID).
 			WithField("email", req.Email).
@@ -189,57 +209,98 @@ func (s *service) Register(ctx context.Context, req RegisterRequest) (*AuthRespo
 		)
 	}
 
-	token, err := s.buildToken(newUser)
+	if err := s.sendVerificationEmail(ctx, newUser); err != nil {
+		// The user can ask for a new email via /auth/verify/resend
+		s.logger.WithError(err).WithField("userID", newUser.ID).Error("failed to send verification email")
+	}
+
+	// The account exists from here on, so the invite stays used even if issuing tokens fails
+	registered = true
+
+	resp, err := s.issueTokens(ctx, newUser, req.Client)
 	if err != nil {
-		s.logger.WithError(err).Error("failed to generate token")
-		return nil, errors.NewAppError(
-			errors.ErrCodeInternal,
-			"failed to generate token",
-			err,
-		)
+		return nil, err
 	}
 
-	refreshToken, err := s.buildRefreshToken(newUser)
+	s.logger.WithField("inviteID", grant.InviteID).Info("user registered successfully")
+
+	return resp, nil
+}
+
+// AssignDefaultScopes adds the InvGate user of u to the company, group and
+// location it registered with again, e.g. after a failed or deferred assignment.
+func (s *service) AssignDefaultScopes(ctx context.Context, u *user.User) error {
+	ctx, err := s.userContext(ctx, u)
 	if err != nil {
-		s.logger.WithError(err).Error("failed to generate refresh token")
-		return nil, errors.NewAppError(
-			errors.ErrCodeInternal,
-			"failed to generate refresh token",
+		return err
+	}
+
+	if err := s.assignUserToScopes(ctx, u.InvGateUserID, s.userScope(ctx, u)); err != nil {
+		s.logger.WithError(err).WithField("invGateUserID", u.InvGateUserID).Error("failed to assign user to default scopes")
+		return errors.NewAppError(
+			errors.ErrCodeExternalService,
+			"failed to assign user to default scopes in InvGate",
 			err,
 		)
 	}
-
-	s.logger.Info("user registered successfully")
-
-	return &AuthResponse{
-		Token:        token,
-		RefreshToken: refreshToken,
-		Name:         newUser.Name,
-		LastName:     newUser.LastName,
-		Email:        newUser.Email,
-	}, nil
+	return nil
 }
 
-func (s *service) assignUserToDefaultScopes(ctx context.Context, invGateUserID int) error {
+func (s *service) assignUserToScopes(ctx context.Context, invGateUserID int, scope registration.Scope) error {
 	userIDs := []int{invGateUserID}
 
-	if s.companyID > 0 {
-		if err := s.invgateClient.AssignUserToCompany(ctx, s.companyID, userIDs); err != nil {
+	if scope.CompanyID > 0 {
+		if err := s.invgateClient.AssignUserToCompany(ctx, scope.CompanyID, userIDs); err != nil {
 			return fmt.Errorf("assign user to company: %w", err)
 		}
 	}
 
-	if s.groupID > 0 {
-		if err := s.invgateClient.AssignUserToGroup(ctx, s.groupID, userIDs); err != nil {
+	if scope.GroupID > 0 {
+		if err := s.invgateClient.AssignUserToGroup(ctx, scope.GroupID, userIDs); err != nil {
 			return fmt.Errorf("assign user to group: %w", err)
 		}
 	}
 
-	if s.locationID > 0 {
-		if err := s.invgateClient.AssignUserToLocation(ctx, s.locationID, userIDs); err != nil {
+	if scope.LocationID > 0 {
+		if err := s.invgateClient.AssignUserToLocation(ctx, scope.LocationID, userIDs); err != nil {
 			return fmt.Errorf("assign user to location: %w", err)
 		}
 	}
 
 	return nil
 }
+
+// userScope returns the scope u registered with. Users created before scopes
+// were stored get the defaults of their tenant; ctx must be bound to it.
+func (s *service) userScope(ctx context.Context, u *user.User) registration.Scope {
+	scope := registration.Scope{
+		CompanyID:  u.InvGateCompanyID,
+		GroupID:    u.InvGateGroupID,
+		LocationID: u.InvGateLocationID,
+	}
+	if scope.IsZero() {
+		return s.registration.DefaultScope(ctx)
+	}
+	return scope
+}
+
+func setUserScope(u *user.User, scope registration.Scope) {
+	u.InvGateCompanyID = scope.CompanyID
+	u.InvGateGroupID = scope.GroupID
+	u.InvGateLocationID = scope.LocationID
+}
+
+// userContext binds ctx to the tenant of u, so InvGate calls for the user go
+// to its InvGate instance even when the request came in through another host.
+func (s *service) userContext(ctx context.Context, u *user.User) (context.Context, error) {
+	userCtx, err := tenant.Switch(ctx, u.TenantID)
+	if err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to resolve tenant of user")
+		return ctx, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to resolve tenant of user",
+			err,
+		)
+	}
+	return userCtx, nil
+}
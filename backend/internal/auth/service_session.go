@@ -0,0 +1,279 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"werk-ticketing/internal/constants"
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/session"
+	"werk-ticketing/internal/tenant"
+	"werk-ticketing/internal/user"
+)
+
+const maxDeviceLength = 255
+
+// issueTokens starts a new server-side session for the user and returns
+// an access token together with the first refresh token of that session.
+func (s *service) issueTokens(ctx context.Context, u *user.User, client ClientInfo) (*AuthResponse, error) {
+	if err := ensureActive(u); err != nil {
+		return nil, err
+	}
+
+	refreshTokenID, err := newTokenID()
+	if err != nil {
+		s.logger.WithError(err).Error("failed to generate refresh token id")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to generate refresh token",
+			err,
+		)
+	}
+
+	device := client.UserAgent
+	if len(device) > maxDeviceLength {
+		device = device[:maxDeviceLength]
+	}
+
+	sess := &session.Session{
+		UserID:         u.ID,
+		UserEmail:      u.Email,
+		RefreshTokenID: refreshTokenID,
+		Device:         device,
+		IPAddress:      client.IPAddress,
+		ExpiresAt:      time.Now().UTC().Add(constants.JWTRefreshExpiration),
+		LastSeenAt:     time.Now().UTC(),
+	}
+	if err := s.sessionRepo.Create(ctx, sess); err != nil {
+		s.logger.WithError(err).WithField("email", u.Email).Error("failed to create session")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to create session",
+			err,
+		)
+	}
+
+	return s.buildAuthResponse(u, sess.ID, refreshTokenID, sess.ExpiresAt)
+}
+
+// buildAuthResponse signs the access and refresh token pair for a session.
+func (s *service) buildAuthResponse(u *user.User, sessionID, refreshTokenID string, refreshExpiresAt time.Time) (*AuthResponse, error) {
+	token, err := s.buildToken(u, sessionID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to generate token")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to generate token",
+			err,
+		)
+	}
+
+	refreshToken, err := s.buildRefreshToken(u, sessionID, refreshTokenID, refreshExpiresAt)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to generate refresh token")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to generate refresh token",
+			err,
+		)
+	}
+
+	return &AuthResponse{
+		Token:         token,
+		RefreshToken:  refreshToken,
+		Name:          u.Name,
+		LastName:      u.LastName,
+		Email:         u.Email,
+		Role:          u.Role,
+		EmailVerified: u.IsEmailVerified(),
+	}, nil
+}
+
+// revokeSessionForReuse revokes a session after an outdated refresh token was presented.
+func (s *service) revokeSessionForReuse(ctx context.Context, sess *session.Session) error {
+	if err := s.revokeSession(ctx, sess.ID, session.RevokedReasonReuseDetected); err != nil {
+		s.logger.WithError(err).WithField("sessionID", sess.ID).Error("failed to revoke session after refresh token reuse")
+	}
+
+	s.logger.WithField("sessionID", sess.ID).
+		WithField("email", sess.UserEmail).
+		Warn("refresh token reuse detected, session revoked")
+
+	return errors.NewAppError(
+		errors.ErrCodeUnauthorized,
+		"refresh token has already been used, session revoked",
+		nil,
+	)
+}
+
+// revokeSession ends a session and blacklists its session ID, so access tokens
+// already issued for it stop working before they expire. The refresh token
+// is invalidated by the revoked session row.
+func (s *service) revokeSession(ctx context.Context, sessionID, reason string) error {
+	if err := s.sessionRepo.Revoke(ctx, sessionID, reason); err != nil {
+		return err
+	}
+	return s.blacklist.Add(ctx, sessionRevocationKey(sessionID), time.Now().Add(constants.JWTExpiration))
+}
+
+// revokeAllSessions ends every active session of a user except keepSessionID
+// (empty to end all), see revokeSession.
+func (s *service) revokeAllSessions(ctx context.Context, userID, reason, keepSessionID string) error {
+	sessions, err := s.sessionRepo.ListActiveByUser(ctx, userID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	for _, sess := range sessions {
+		if sess.ID == keepSessionID {
+			continue
+		}
+		if err := s.revokeSession(ctx, sess.ID, reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RevokeUserSessions ends every active session of a user, for example when an
+// admin deactivates the account.
+func (s *service) RevokeUserSessions(ctx context.Context, userID, reason string) error {
+	if err := s.revokeAllSessions(ctx, userID, reason, ""); err != nil {
+		s.logger.WithError(err).WithField("userID", userID).Error("failed to revoke user sessions")
+		return errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to revoke sessions",
+			err,
+		)
+	}
+	return nil
+}
+
+// ensureActive rejects users an admin has deactivated.
+func ensureActive(u *user.User) error {
+	if !u.IsActive() {
+		return errors.NewAppError(
+			errors.ErrCodeAccountDisabled,
+			"this account has been deactivated",
+			nil,
+		)
+	}
+	return nil
+}
+
+// sessionRevocationKey is the blacklist entry that revokes all access tokens of a session.
+func sessionRevocationKey(sessionID string) string {
+	return "sid:" + sessionID
+}
+
+// ListSessions returns the active sessions of a user. currentSessionID marks
+// the session the request was made from.
+func (s *service) ListSessions(ctx context.Context, email, currentSessionID string) ([]SessionResponse, error) {
+	u, err := s.getSessionOwner(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.sessionRepo.ListActiveByUser(ctx, u.ID, time.Now().UTC())
+	if err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to list sessions")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to list sessions",
+			err,
+		)
+	}
+
+	resp := make([]SessionResponse, 0, len(sessions))
+	for _, sess := range sessions {
+		resp = append(resp, SessionResponse{
+			ID:         sess.ID,
+			UserAgent:  sess.Device,
+			IPAddress:  sess.IPAddress,
+			CreatedAt:  sess.CreatedAt,
+			LastSeenAt: sess.LastSeenAt,
+			ExpiresAt:  sess.ExpiresAt,
+			Current:    sess.ID == currentSessionID,
+		})
+	}
+	return resp, nil
+}
+
+// RevokeSession logs out one session of the user, invalidating both its
+// access and refresh tokens.
+func (s *service) RevokeSession(ctx context.Context, email, sessionID string) error {
+	u, err := s.getSessionOwner(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	sess, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		s.logger.WithError(err).WithField("sessionID", sessionID).Error("failed to get session")
+		return errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to revoke session",
+			err,
+		)
+	}
+	// Sessions of other users are reported as missing so their IDs cannot be probed
+	if sess == nil || sess.UserID != u.ID || !sess.IsActive(time.Now().UTC()) {
+		return errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"session not found",
+			nil,
+		)
+	}
+
+	if err := s.revokeSession(ctx, sess.ID, session.RevokedReasonRemoteLogout); err != nil {
+		s.logger.WithError(err).WithField("sessionID", sess.ID).Error("failed to revoke session")
+		return errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to revoke session",
+			err,
+		)
+	}
+
+	s.logger.WithField("sessionID", sess.ID).WithField("userID", u.ID).Info("session revoked")
+	return nil
+}
+
+// LogoutAll ends every session of the user, including the current one.
+func (s *service) LogoutAll(ctx context.Context, email string) error {
+	u, err := s.getSessionOwner(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	if err := s.revokeAllSessions(ctx, u.ID, session.RevokedReasonLogoutAll, ""); err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to revoke all sessions")
+		return errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to revoke sessions",
+			err,
+		)
+	}
+
+	s.logger.WithField("userID", u.ID).Info("all sessions revoked")
+	return nil
+}
+
+func (s *service) getSessionOwner(ctx context.Context, email string) (*user.User, error) {
+	u, err := s.userRepo.GetByEmail(ctx, tenant.IDFromContext(ctx), email)
+	if err != nil {
+		s.logger.WithError(err).WithField("email", email).Error("failed to get user by email")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to retrieve user information",
+			err,
+		)
+	}
+	if u == nil {
+		return nil, errors.NewAppError(
+			errors.ErrCodeUnauthorized,
+			"user not found",
+			nil,
+		)
+	}
+	return u, nil
+}
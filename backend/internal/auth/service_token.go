@@ -8,21 +8,37 @@ import (
 
 	"werk-ticketing/internal/constants"
 	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/jwtkeys"
+	"werk-ticketing/internal/requestinfo"
+	"werk-ticketing/internal/session"
 	"werk-ticketing/internal/user"
 )
 
-func (s *service) ParseToken(token string) (*jwt.RegisteredClaims, error) {
-	if s.IsTokenBlacklisted(token) {
-		return nil, errors.NewAppError(
-			errors.ErrCodeUnauthorized,
-			"token has been revoked",
-			nil,
-		)
+// ParseToken validates an access token and records the use of its session.
+// Refresh tokens are rejected.
+func (s *service) ParseToken(ctx context.Context, token string) (*Claims, error) {
+	claims, err := s.parseToken(ctx, token, TokenTypeAccess)
+	if err != nil {
+		return nil, err
+	}
+	if claims.SessionID != "" {
+		s.touchSession(ctx, claims.SessionID)
 	}
+	return claims, nil
+}
 
-	parsed, err := jwt.ParseWithClaims(token, &jwt.RegisteredClaims{}, func(t *jwt.Token) (interface{}, error) {
-		return s.jwtSecret, nil
-	})
+// touchSession updates when the session was last used, at most once per
+// SessionTouchInterval. Failures are logged and do not fail the request.
+func (s *service) touchSession(ctx context.Context, sessionID string) {
+	now := time.Now().UTC()
+	ipAddress := requestinfo.FromContext(ctx).IPAddress
+	if err := s.sessionRepo.Touch(ctx, sessionID, ipAddress, now, now.Add(-constants.SessionTouchInterval)); err != nil {
+		s.logger.WithError(err).WithField("sessionID", sessionID).Warn("failed to update session activity")
+	}
+}
+
+func (s *service) parseToken(ctx context.Context, token, expectedType string) (*Claims, error) {
+	parsed, err := jwt.ParseWithClaims(token, &Claims{}, s.keys.Keyfunc, jwt.WithValidMethods(s.keys.ValidMethods()))
 	if err != nil {
 		return nil, errors.NewAppError(
 			errors.ErrCodeUnauthorized,
@@ -31,7 +47,7 @@ func (s *service) ParseToken(token string) (*jwt.RegisteredClaims, error) {
 		)
 	}
 
-	claims, ok := parsed.Claims.(*jwt.RegisteredClaims)
+	claims, ok := parsed.Claims.(*Claims)
 	if !ok || !parsed.Valid {
 		return nil, errors.NewAppError(
 			errors.ErrCodeUnauthorized,
@@ -39,16 +55,80 @@ func (s *service) ParseToken(token string) (*jwt.RegisteredClaims, error) {
 			nil,
 		)
 	}
+
+	if claims.Type != expectedType || claims.ID == "" {
+		return nil, errors.NewAppError(
+			errors.ErrCodeUnauthorized,
+			"invalid token type",
+			nil,
+		)
+	}
+
+	revoked, err := s.blacklist.IsRevoked(ctx, claims.ID)
+	if err == nil && !revoked && claims.SessionID != "" {
+		revoked, err = s.blacklist.IsRevoked(ctx, sessionRevocationKey(claims.SessionID))
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("failed to check token revocation")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to verify token",
+			err,
+		)
+	}
+	if revoked {
+		return nil, errors.NewAppError(
+			errors.ErrCodeUnauthorized,
+			"token has been revoked",
+			nil,
+		)
+	}
 	return claims, nil
 }
 
-func (s *service) RefreshToken(ctx context.Context, refreshToken string) (*AuthResponse, error) {
-	claims, err := s.ParseToken(refreshToken)
+// RefreshToken rotates the refresh token of a session.
+// Presenting a refresh token that was already rotated revokes the whole session.
+func (s *service) RefreshToken(ctx context.Context, req RefreshTokenRequest) (*AuthResponse, error) {
+	claims, err := s.parseToken(ctx, req.RefreshToken, TokenTypeRefresh)
 	if err != nil {
 		return nil, err
 	}
+	if claims.SessionID == "" {
+		return nil, errors.NewAppError(
+			errors.ErrCodeUnauthorized,
+			"invalid token",
+			nil,
+		)
+	}
+
+	sess, err := s.sessionRepo.GetByID(ctx, claims.SessionID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get session for token refresh")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to refresh token",
+			err,
+		)
+	}
+	if sess == nil || sess.RevokedAt != nil {
+		return nil, errors.NewAppError(
+			errors.ErrCodeUnauthorized,
+			"session has been revoked",
+			nil,
+		)
+	}
+	if sess.RefreshTokenID != claims.ID {
+		return nil, s.revokeSessionForReuse(ctx, sess)
+	}
+	if !sess.IsActive(time.Now().UTC()) {
+		return nil, errors.NewAppError(
+			errors.ErrCodeUnauthorized,
+			"session has expired",
+			nil,
+		)
+	}
 
-	user, err := s.userRepo.GetByEmail(ctx, claims.Subject)
+	user, err := s.userRepo.GetByID(ctx, sess.UserID)
 	if err != nil {
 		s.logger.WithError(err).Error("failed to get user for token refresh")
 		return nil, errors.NewAppError(
@@ -64,77 +144,124 @@ func (s *service) RefreshToken(ctx context.Context, refreshToken string) (*AuthR
 			nil,
 		)
 	}
+	if err := ensureActive(user); err != nil {
+		return nil, err
+	}
 
-	token, err := s.buildToken(user)
+	newRefreshTokenID, err := newTokenID()
 	if err != nil {
-		s.logger.WithError(err).Error("failed to generate refresh token")
+		s.logger.WithError(err).Error("failed to generate refresh token id")
 		return nil, errors.NewAppError(
 			errors.ErrCodeInternal,
-			"failed to generate token",
+			"failed to generate refresh token",
 			err,
 		)
 	}
+	expiresAt := time.Now().UTC().Add(constants.JWTRefreshExpiration)
 
-	refreshTokenNew, err := s.buildRefreshToken(user)
+	rotated, err := s.sessionRepo.Rotate(ctx, sess.ID, claims.ID, newRefreshTokenID, req.Client.IPAddress, expiresAt)
 	if err != nil {
-		s.logger.WithError(err).Error("failed to generate refresh token")
+		s.logger.WithError(err).WithField("sessionID", sess.ID).Error("failed to rotate refresh token")
 		return nil, errors.NewAppError(
 			errors.ErrCodeInternal,
-			"failed to generate refresh token",
+			"failed to refresh token",
 			err,
 		)
 	}
+	if !rotated {
+		// Another request rotated this token first, so it is being replayed.
+		return nil, s.revokeSessionForReuse(ctx, sess)
+	}
 
-	s.logger.Info("token refreshed successfully")
+	resp, err := s.buildAuthResponse(user, sess.ID, newRefreshTokenID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
 
-	return &AuthResponse{
-		Token:        token,
-		RefreshToken: refreshTokenNew,
-		Name:         user.Name,
-		LastName:     user.LastName,
-		Email:        user.Email,
-	}, nil
+	s.logger.Info("token refreshed successfully")
+	return resp, nil
 }
 
+// RevokeToken blacklists the presented access token by its ID and ends its
+// session, which also invalidates the session's refresh token.
 func (s *service) RevokeToken(ctx context.Context, token string) error {
-	claims, err := s.ParseToken(token)
+	claims, err := s.ParseToken(ctx, token)
 	if err != nil {
-		s.blacklist.Add(token, time.Now().Add(constants.JWTExpiration))
+		// Invalid, expired or already revoked tokens cannot be used anyway
 		return nil
 	}
 
+	expiresAt := time.Now().Add(constants.JWTExpiration)
 	if claims.ExpiresAt != nil {
-		s.blacklist.Add(token, claims.ExpiresAt.Time)
-	} else {
-		s.blacklist.Add(token, time.Now().Add(constants.JWTExpiration))
+		expiresAt = claims.ExpiresAt.Time
+	}
+	if err := s.blacklist.Add(ctx, claims.ID, expiresAt); err != nil {
+		s.logger.WithError(err).Error("failed to blacklist token")
+		return errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to revoke token",
+			err,
+		)
+	}
+
+	if claims.SessionID != "" {
+		if err := s.revokeSession(ctx, claims.SessionID, session.RevokedReasonLogout); err != nil {
+			s.logger.WithError(err).WithField("sessionID", claims.SessionID).Error("failed to revoke session")
+			return errors.NewAppError(
+				errors.ErrCodeInternal,
+				"failed to revoke session",
+				err,
+			)
+		}
 	}
 
 	s.logger.Info("token revoked")
 	return nil
 }
 
-func (s *service) IsTokenBlacklisted(token string) bool {
-	return s.blacklist.IsBlacklisted(token)
-}
+func (s *service) buildToken(u *user.User, sessionID string) (string, error) {
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	role := u.Role
+	if role == "" {
+		role = user.RoleRequester
+	}
 
-func (s *service) buildToken(u *user.User) (string, error) {
-	claims := jwt.RegisteredClaims{
-		Subject:   u.Email,
-		IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
-		ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(constants.JWTExpiration)),
+	claims := Claims{
+		Type:      TokenTypeAccess,
+		SessionID: sessionID,
+		Role:      role,
+		TenantID:  u.TenantID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Subject:   u.Email,
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(constants.JWTExpiration)),
+		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.jwtSecret)
+	return s.keys.Sign(claims)
 }
 
-func (s *service) buildRefreshToken(u *user.User) (string, error) {
-	claims := jwt.RegisteredClaims{
-		Subject:   u.Email,
-		IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
-		ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(constants.JWTRefreshExpiration)),
+func (s *service) buildRefreshToken(u *user.User, sessionID, tokenID string, expiresAt time.Time) (string, error) {
+	claims := Claims{
+		Type:      TokenTypeRefresh,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Subject:   u.Email,
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.jwtSecret)
+	return s.keys.Sign(claims)
+}
+
+// JWKS returns the public keys other services can use to verify tokens issued here.
+func (s *service) JWKS() jwtkeys.JWKS {
+	return s.keys.JWKS()
 }
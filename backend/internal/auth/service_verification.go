@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"werk-ticketing/internal/constants"
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/mailer"
+	"werk-ticketing/internal/tenant"
+	"werk-ticketing/internal/user"
+	"werk-ticketing/internal/usertoken"
+	"werk-ticketing/internal/validator"
+)
+
+// VerifyEmail confirms the email address of the user the token was issued to.
+// When scope assignment is deferred, the user is assigned to the default InvGate scopes here.
+func (s *service) VerifyEmail(ctx context.Context, req VerifyEmailRequest) error {
+	if !validator.ValidateRequired(req.Token) {
+		return errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"token is required",
+			nil,
+		)
+	}
+
+	_, u, err := s.consumeUserToken(ctx, usertoken.PurposeEmailVerification, req.Token)
+	if err != nil {
+		return err
+	}
+	if u.IsEmailVerified() {
+		return nil
+	}
+
+	if s.deferScopes && u.InvGateUserID > 0 {
+		userCtx, err := s.userContext(ctx, u)
+		if err != nil {
+			return err
+		}
+		if err := s.assignUserToScopes(userCtx, u.InvGateUserID, s.userScope(userCtx, u)); err != nil {
+			s.logger.WithError(err).
+				WithField("invGateUserID", u.InvGateUserID).
+				WithField("userID", u.ID).
+				Error("failed to assign verified user to default InvGate scopes")
+			return errors.NewAppError(
+				errors.ErrCodeExternalService,
+				"failed to assign user to default configuration, please request a new verification email",
+				err,
+			)
+		}
+	}
+
+	if err := s.userRepo.MarkEmailVerified(ctx, u.ID, time.Now().UTC()); err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to mark email as verified")
+		return errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to verify email",
+			err,
+		)
+	}
+
+	s.logger.WithField("userID", u.ID).Info("email verified")
+	return nil
+}
+
+// ResendVerification emails a new verification link. Like ForgotPassword it does
+// not reveal whether the email is registered or already verified.
+func (s *service) ResendVerification(ctx context.Context, req ResendVerificationRequest) error {
+	if !validator.ValidateEmail(req.Email) {
+		return errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"invalid email format",
+			nil,
+		)
+	}
+
+	u, err := s.userRepo.GetByEmail(ctx, tenant.IDFromContext(ctx), req.Email)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get user")
+		return errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to resend verification email",
+			err,
+		)
+	}
+	if u == nil || u.IsEmailVerified() {
+		return nil
+	}
+
+	return s.sendVerificationEmail(ctx, u)
+}
+
+// sendVerificationEmail issues a verification token and emails the link to the user.
+func (s *service) sendVerificationEmail(ctx context.Context, u *user.User) error {
+	plain, err := s.createUserToken(ctx, u, usertoken.PurposeEmailVerification, constants.EmailVerificationTokenExpiration)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", strings.TrimRight(s.appBaseURL, "/"), url.QueryEscape(plain))
+	s.sendMail(u.Email, mailer.Message{
+		To:      u.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link expires in %d hours. You cannot create tickets until your email is verified.\n",
+			u.Name,
+			link,
+			int(constants.EmailVerificationTokenExpiration.Hours()),
+		),
+	})
+
+	s.logger.WithField("userID", u.ID).Info("email verification token issued")
+	return nil
+}
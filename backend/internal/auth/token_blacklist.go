@@ -6,76 +6,66 @@ import (
 	"time"
 )
 
-// TokenBlacklist manages blacklisted tokens in memory
-// In production, consider using Redis or database for distributed systems
+// TokenBlacklistService stores revoked token IDs (the JWT "jti" claim) until
+// the token would have expired anyway. Implementations must be safe for
+// concurrent use.
+type TokenBlacklistService interface {
+	// Add revokes the token with the given ID until expiresAt.
+	Add(ctx context.Context, tokenID string, expiresAt time.Time) error
+	// IsRevoked reports whether the token ID has been revoked and is not yet expired.
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+	// DeleteExpired removes entries that expired before now and returns how many were removed.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// TokenBlacklist manages revoked token IDs in memory.
+// Revocations are lost on restart and not shared between replicas; use the
+// MySQL or Redis backend in production.
 type TokenBlacklist struct {
 	tokens map[string]time.Time
 	mu     sync.RWMutex
 }
 
-// NewTokenBlacklist creates a new token blacklist
+// NewTokenBlacklist creates a new in-memory token blacklist
 func NewTokenBlacklist() *TokenBlacklist {
-	tb := &TokenBlacklist{
+	return &TokenBlacklist{
 		tokens: make(map[string]time.Time),
 	}
-
-	// Cleanup expired tokens periodically
-	go tb.cleanup()
-
-	return tb
 }
 
-// Add adds a token to the blacklist with expiration time
-func (tb *TokenBlacklist) Add(token string, expiresAt time.Time) {
+// Add adds a token ID to the blacklist with expiration time
+func (tb *TokenBlacklist) Add(ctx context.Context, tokenID string, expiresAt time.Time) error {
 	tb.mu.Lock()
 	defer tb.mu.Unlock()
-	tb.tokens[token] = expiresAt
+	tb.tokens[tokenID] = expiresAt
+	return nil
 }
 
-// IsBlacklisted checks if a token is blacklisted
-func (tb *TokenBlacklist) IsBlacklisted(token string) bool {
+// IsRevoked checks if a token ID is blacklisted
+func (tb *TokenBlacklist) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
 	tb.mu.RLock()
 	defer tb.mu.RUnlock()
-	
-	expiresAt, exists := tb.tokens[token]
+
+	expiresAt, exists := tb.tokens[tokenID]
 	if !exists {
-		return false
-	}
-	
-	// If token has expired, remove it and return false
-	if time.Now().After(expiresAt) {
-		tb.mu.RUnlock()
-		tb.mu.Lock()
-		delete(tb.tokens, token)
-		tb.mu.Unlock()
-		tb.mu.RLock()
-		return false
+		return false, nil
 	}
-	
-	return true
+
+	// Expired entries are left for the sweeper to remove
+	return time.Now().Before(expiresAt), nil
 }
 
-// cleanup removes expired tokens periodically
-func (tb *TokenBlacklist) cleanup() {
-	ticker := time.NewTicker(1 * time.Hour)
-	defer ticker.Stop()
+// DeleteExpired removes expired token IDs
+func (tb *TokenBlacklist) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
 
-	for range ticker.C {
-		tb.mu.Lock()
-		now := time.Now()
-		for token, expiresAt := range tb.tokens {
-			if now.After(expiresAt) {
-				delete(tb.tokens, token)
-			}
+	var removed int64
+	for tokenID, expiresAt := range tb.tokens {
+		if now.After(expiresAt) {
+			delete(tb.tokens, tokenID)
+			removed++
 		}
-		tb.mu.Unlock()
 	}
+	return removed, nil
 }
-
-// TokenBlacklistService interface for token blacklist operations
-type TokenBlacklistService interface {
-	AddToken(ctx context.Context, token string, expiresAt time.Time) error
-	IsTokenBlacklisted(ctx context.Context, token string) (bool, error)
-	RevokeToken(ctx context.Context, token string) error
-}
-
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"werk-ticketing/internal/config"
+	"werk-ticketing/internal/constants"
+	"werk-ticketing/internal/redis"
+)
+
+// Supported token blacklist backends (TOKEN_BLACKLIST_BACKEND).
+const (
+	BlacklistBackendMemory = "memory"
+	BlacklistBackendMySQL  = "mysql"
+	BlacklistBackendRedis  = "redis"
+)
+
+// NewTokenBlacklistFromConfig builds the blacklist backend selected in config
+// and starts a background sweeper that removes expired entries.
+func NewTokenBlacklistFromConfig(cfg *config.Config, db *gorm.DB, logger *logrus.Logger) (TokenBlacklistService, error) {
+	var store TokenBlacklistService
+
+	switch cfg.TokenBlacklistBackend {
+	case BlacklistBackendMemory, "":
+		store = NewTokenBlacklist()
+	case BlacklistBackendMySQL:
+		store = NewMySQLTokenBlacklist(db)
+	case BlacklistBackendRedis:
+		client := redis.NewClient(redis.Options{
+			Addr:     cfg.RedisAddr,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := client.Ping(ctx); err != nil {
+			return nil, fmt.Errorf("token blacklist redis ping: %w", err)
+		}
+		store = NewRedisTokenBlacklist(client)
+	default:
+		return nil, fmt.Errorf("unknown token blacklist backend %q", cfg.TokenBlacklistBackend)
+	}
+
+	go sweepExpiredTokens(store, constants.TokenBlacklistSweepInterval, logger)
+
+	logger.WithField("backend", cfg.TokenBlacklistBackend).Info("token blacklist initialized")
+	return store, nil
+}
+
+// sweepExpiredTokens periodically removes expired blacklist entries
+func sweepExpiredTokens(store TokenBlacklistService, interval time.Duration, logger *logrus.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		removed, err := store.DeleteExpired(ctx, time.Now().UTC())
+		cancel()
+
+		if err != nil {
+			logger.WithError(err).Warn("failed to sweep expired revoked tokens")
+			continue
+		}
+		if removed > 0 {
+			logger.WithField("removed", removed).Debug("swept expired revoked tokens")
+		}
+	}
+}
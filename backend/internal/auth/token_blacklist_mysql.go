@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mysqlTokenBlacklist persists revoked token IDs in the revoked_tokens table,
+// so revocations survive restarts and are shared by every replica using the same database.
+type mysqlTokenBlacklist struct {
+	db *gorm.DB
+}
+
+// NewMySQLTokenBlacklist builds a MySQL-backed token blacklist.
+func NewMySQLTokenBlacklist(db *gorm.DB) TokenBlacklistService {
+	return &mysqlTokenBlacklist{db: db}
+}
+
+func (b *mysqlTokenBlacklist) Add(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	return b.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"expires_at"})}).
+		Create(&RevokedToken{TokenID: tokenID, ExpiresAt: expiresAt.UTC()}).Error
+}
+
+func (b *mysqlTokenBlacklist) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	var count int64
+	err := b.db.WithContext(ctx).Model(&RevokedToken{}).
+		Where("token_id = ? AND expires_at > ?", tokenID, time.Now().UTC()).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (b *mysqlTokenBlacklist) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := b.db.WithContext(ctx).Where("expires_at <= ?", now.UTC()).Delete(&RevokedToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"werk-ticketing/internal/redis"
+)
+
+const redisBlacklistKeyPrefix = "werk:revoked:"
+
+// redisTokenBlacklist stores revoked token IDs in a Redis-protocol server.
+// Keys carry a TTL matching the token expiry, so the server expires them itself.
+type redisTokenBlacklist struct {
+	client *redis.Client
+}
+
+// NewRedisTokenBlacklist builds a token blacklist on top of a Redis-protocol client.
+func NewRedisTokenBlacklist(client *redis.Client) TokenBlacklistService {
+	return &redisTokenBlacklist{client: client}
+}
+
+func (b *redisTokenBlacklist) Add(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return b.client.Set(ctx, redisBlacklistKeyPrefix+tokenID, []byte("1"), ttl)
+}
+
+func (b *redisTokenBlacklist) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return b.client.Exists(ctx, redisBlacklistKeyPrefix+tokenID)
+}
+
+// DeleteExpired is a no-op because Redis expires keys on its own.
+func (b *redisTokenBlacklist) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
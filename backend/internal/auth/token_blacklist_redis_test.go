@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"werk-ticketing/internal/redis"
+	"werk-ticketing/internal/redis/redistest"
+)
+
+func TestRedisTokenBlacklist(t *testing.T) {
+	srv, err := redistest.NewServer()
+	if err != nil {
+		t.Fatalf("start fake server: %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	client := redis.NewClient(redis.Options{Addr: srv.Addr})
+	t.Cleanup(func() { _ = client.Close() })
+	blacklist := NewRedisTokenBlacklist(client)
+	ctx := context.Background()
+
+	if revoked, err := blacklist.IsRevoked(ctx, "token-1"); err != nil || revoked {
+		t.Fatalf("IsRevoked before Add = %v, %v", revoked, err)
+	}
+
+	if err := blacklist.Add(ctx, "token-1", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if revoked, err := blacklist.IsRevoked(ctx, "token-1"); err != nil || !revoked {
+		t.Fatalf("IsRevoked after Add = %v, %v", revoked, err)
+	}
+	if ttl := srv.TTL(redisBlacklistKeyPrefix + "token-1"); ttl <= 0 || ttl > time.Hour {
+		t.Fatalf("ttl = %s, want the remaining token lifetime", ttl)
+	}
+
+	// Already expired tokens are not stored at all
+	if err := blacklist.Add(ctx, "token-2", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("Add expired: %v", err)
+	}
+	if revoked, err := blacklist.IsRevoked(ctx, "token-2"); err != nil || revoked {
+		t.Fatalf("IsRevoked of expired token = %v, %v", revoked, err)
+	}
+
+	// A short lived entry expires on the server
+	if err := blacklist.Add(ctx, "token-3", time.Now().Add(50*time.Millisecond)); err != nil {
+		t.Fatalf("Add short lived: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if revoked, err := blacklist.IsRevoked(ctx, "token-3"); err != nil || revoked {
+		t.Fatalf("IsRevoked after expiry = %v, %v", revoked, err)
+	}
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Cache serves values from a Store and loads them on a miss. Entries younger
+// than ttl are fresh. Entries up to staleTTL older than that are still served,
+// while a reload runs in the background (stale-while-revalidate).
+type Cache interface {
+	Fetch(ctx context.Context, key string, load func(ctx context.Context) ([]byte, error)) ([]byte, error)
+	Purge(ctx context.Context, prefix string) (int64, error)
+}
+
+type cache struct {
+	store    Store
+	ttl      time.Duration
+	staleTTL time.Duration
+	logger   *logrus.Logger
+
+	mu         sync.Mutex
+	refreshing map[string]bool
+}
+
+// New creates a cache on top of store.
+func New(store Store, ttl, staleTTL time.Duration, logger *logrus.Logger) Cache {
+	return &cache{
+		store:      store,
+		ttl:        ttl,
+		staleTTL:   staleTTL,
+		logger:     logger,
+		refreshing: make(map[string]bool),
+	}
+}
+
+// Fetch returns the value cached under key, calling load when it is missing or
+// too old. Store failures are logged and fall back to load, so a cache outage
+// only costs latency.
+func (c *cache) Fetch(ctx context.Context, key string, load func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	raw, err := c.store.Get(ctx, key)
+	if err != nil && !errors.Is(err, ErrMiss) {
+		c.logger.WithError(err).WithField("key", key).Warn("failed to read from cache")
+	}
+	if err == nil {
+		if storedAt, value, ok := decode(raw); ok {
+			age := time.Since(storedAt)
+			if age < c.ttl {
+				return value, nil
+			}
+			if age < c.ttl+c.staleTTL {
+				c.revalidate(ctx, key, load)
+				return value, nil
+			}
+		}
+	}
+
+	value, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.put(ctx, key, value)
+	return value, nil
+}
+
+// Purge removes all entries whose key starts with prefix.
+func (c *cache) Purge(ctx context.Context, prefix string) (int64, error) {
+	return c.store.DeletePrefix(ctx, prefix)
+}
+
+// revalidate reloads a stale entry in the background, at most once per key at a time.
+func (c *cache) revalidate(ctx context.Context, key string, load func(ctx context.Context) ([]byte, error)) {
+	c.mu.Lock()
+	if c.refreshing[key] {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing[key] = true
+	c.mu.Unlock()
+
+	// Keep the values of the request context (such as the InvGate connection)
+	// but not its cancellation, the request is answered before the reload ends
+	bg := context.WithoutCancel(ctx)
+
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.refreshing, key)
+			c.mu.Unlock()
+		}()
+
+		value, err := load(bg)
+		if err != nil {
+			c.logger.WithError(err).WithField("key", key).Warn("failed to revalidate cache entry")
+			return
+		}
+		c.put(bg, key, value)
+	}()
+}
+
+func (c *cache) put(ctx context.Context, key string, value []byte) {
+	if err := c.store.Set(ctx, key, encode(time.Now(), value), c.ttl+c.staleTTL); err != nil {
+		c.logger.WithError(err).WithField("key", key).Warn("failed to write to cache")
+	}
+}
+
+// encode prefixes value with the time it was stored.
+func encode(storedAt time.Time, value []byte) []byte {
+	buf := make([]byte, 8, 8+len(value))
+	binary.BigEndian.PutUint64(buf, uint64(storedAt.UnixNano()))
+	return append(buf, value...)
+}
+
+func decode(raw []byte) (time.Time, []byte, bool) {
+	if len(raw) < 8 {
+		return time.Time{}, nil, false
+	}
+	storedAt := time.Unix(0, int64(binary.BigEndian.Uint64(raw[:8])))
+	return storedAt, raw[8:], true
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/config"
+	"werk-ticketing/internal/constants"
+	"werk-ticketing/internal/redis"
+)
+
+// Supported cache backends (CACHE_BACKEND).
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+// NewFromConfig builds the cache for InvGate reference data on the backend selected in config.
+func NewFromConfig(cfg *config.Config, logger *logrus.Logger) (Cache, error) {
+	var store Store
+
+	switch cfg.CacheBackend {
+	case BackendMemory, "":
+		store = NewMemoryStore(cfg.CacheMaxEntries)
+	case BackendRedis:
+		client := redis.NewClient(redis.Options{
+			Addr:     cfg.RedisAddr,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := client.Ping(ctx); err != nil {
+			return nil, fmt.Errorf("cache redis ping: %w", err)
+		}
+		store = NewRedisStore(client)
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", cfg.CacheBackend)
+	}
+
+	logger.WithField("backend", cfg.CacheBackend).Info("reference data cache initialized")
+	return New(store, constants.ReferenceCacheTTL, constants.ReferenceCacheStaleTTL, logger), nil
+}
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryStore is a size-bounded LRU store local to this process.
+type memoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	items      map[string]*list.Element
+	order      *list.List // Front is most recently used
+}
+
+type memoryItem struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryStore creates an in-process LRU store holding at most maxEntries keys.
+func NewMemoryStore(maxEntries int) Store {
+	if maxEntries <= 0 {
+		maxEntries = 1000
+	}
+	return &memoryStore{
+		maxEntries: maxEntries,
+		items:      make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[key]
+	if !ok {
+		return nil, ErrMiss
+	}
+	item := el.Value.(*memoryItem)
+	if !item.expiresAt.IsZero() && time.Now().After(item.expiresAt) {
+		s.remove(el)
+		return nil, ErrMiss
+	}
+	s.order.MoveToFront(el)
+	return item.value, nil
+}
+
+func (s *memoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if el, ok := s.items[key]; ok {
+		item := el.Value.(*memoryItem)
+		item.value = value
+		item.expiresAt = expiresAt
+		s.order.MoveToFront(el)
+		return nil
+	}
+
+	s.items[key] = s.order.PushFront(&memoryItem{key: key, value: value, expiresAt: expiresAt})
+	for s.order.Len() > s.maxEntries {
+		s.remove(s.order.Back())
+	}
+	return nil
+}
+
+func (s *memoryStore) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed int64
+	for key, el := range s.items {
+		if strings.HasPrefix(key, prefix) {
+			s.remove(el)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (s *memoryStore) remove(el *list.Element) {
+	s.order.Remove(el)
+	delete(s.items, el.Value.(*memoryItem).key)
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"werk-ticketing/internal/redis"
+)
+
+const redisKeyPrefix = "cache:"
+
+// redisStore keeps entries in Redis or a Redis-protocol compatible server, so
+// they are shared between instances of the service.
+type redisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a store backed by client.
+func NewRedisStore(client *redis.Client) Store {
+	return &redisStore{client: client}
+}
+
+func (s *redisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := s.client.Get(ctx, redisKeyPrefix+key)
+	if errors.Is(err, redis.ErrNil) {
+		return nil, ErrMiss
+	}
+	return value, err
+}
+
+func (s *redisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, redisKeyPrefix+key, value, ttl)
+}
+
+// DeletePrefix walks the matching keys with SCAN, which unlike KEYS does not
+// block the server.
+func (s *redisStore) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	var removed int64
+	cursor := "0"
+	for {
+		reply, err := s.client.Do(ctx, "SCAN", cursor, "MATCH", redisKeyPrefix+escapeGlob(prefix)+"*", "COUNT", "200")
+		if err != nil {
+			return removed, err
+		}
+		parts, ok := reply.([]interface{})
+		if !ok || len(parts) != 2 {
+			return removed, fmt.Errorf("cache: unexpected SCAN reply %T", reply)
+		}
+		next, _ := parts[0].([]byte)
+		items, _ := parts[1].([]interface{})
+
+		keys := make([]string, 0, len(items))
+		for _, item := range items {
+			if key, ok := item.([]byte); ok {
+				keys = append(keys, string(key))
+			}
+		}
+		n, err := s.client.Del(ctx, keys...)
+		if err != nil {
+			return removed, err
+		}
+		removed += n
+
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			return removed, nil
+		}
+	}
+}
+
+// escapeGlob escapes the characters SCAN MATCH treats as patterns.
+func escapeGlob(s string) string {
+	escaped := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '*', '?', '[', ']', '\\':
+			escaped = append(escaped, '\\')
+		}
+		escaped = append(escaped, s[i])
+	}
+	return string(escaped)
+}
@@ -0,0 +1,18 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMiss is returned by Store.Get for keys that are not stored or expired.
+var ErrMiss = errors.New("cache: miss")
+
+// Store is a key/value backend for the cache.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// DeletePrefix removes all keys starting with prefix and returns how many were removed.
+	DeletePrefix(ctx context.Context, prefix string) (int64, error)
+}
@@ -0,0 +1,24 @@
+package category
+
+// Node is a category in the category tree.
+type Node struct {
+	ID               int     `json:"id"`
+	Name             string  `json:"name"`
+	ParentCategoryID int     `json:"parent_category_id,omitempty"` // 0 for top-level categories in InvGate
+	Icon             string  `json:"icon,omitempty"`
+	Description      string  `json:"description,omitempty"`
+	Allowed          *bool   `json:"allowed,omitempty"` // Only set in the admin view
+	Children         []*Node `json:"children"`
+}
+
+// UpdateAllowedRequest incoming body for replacing the category allowlist.
+type UpdateAllowedRequest struct {
+	CategoryIDs []int `json:"category_ids" binding:"required"`
+}
+
+// UpdateOverrideRequest incoming body for the local icon and description of a
+// category. Empty values fall back to InvGate.
+type UpdateOverrideRequest struct {
+	Icon        string `json:"icon" binding:"max=100" validate:"max=100"`
+	Description string `json:"description" binding:"max=500" validate:"max=500"`
+}
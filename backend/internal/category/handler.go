@@ -0,0 +1,101 @@
+package category
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"werk-ticketing/internal/constants"
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/middleware"
+	"werk-ticketing/internal/response"
+)
+
+// Handler exposes HTTP handlers for category routes.
+type Handler struct {
+	service Service
+}
+
+// NewHandler wires category service into http handler.
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetCategories handles GET /api/v1/categories
+func (h *Handler) GetCategories(c *gin.Context) {
+	tree, err := h.service.GetTree(c.Request.Context())
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusBadGateway, errors.ErrCodeExternalService, err.Error())
+		}
+		return
+	}
+
+	response.WriteCacheable(c, http.StatusOK, gin.H{"data": tree}, constants.ReferenceCacheMaxAge)
+}
+
+// ListAdminCategories handles GET /api/v1/admin/categories
+func (h *Handler) ListAdminCategories(c *gin.Context) {
+	tree, err := h.service.GetAdminTree(c.Request.Context())
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusBadGateway, errors.ErrCodeExternalService, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, gin.H{"data": tree})
+}
+
+// UpdateAllowed handles PUT /api/v1/admin/categories/allowed
+func (h *Handler) UpdateAllowed(c *gin.Context) {
+	var req UpdateAllowedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+		return
+	}
+
+	tree, err := h.service.UpdateAllowed(c.Request.Context(), middleware.GetUserEmail(c), req.CategoryIDs)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, gin.H{"data": tree})
+}
+
+// UpdateOverride handles PUT /api/v1/admin/categories/:id
+func (h *Handler) UpdateOverride(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || categoryID <= 0 {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "category id must be a positive integer")
+		return
+	}
+
+	var req UpdateOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+		return
+	}
+
+	node, err := h.service.UpdateOverride(c.Request.Context(), categoryID, middleware.GetUserEmail(c), req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, node)
+}
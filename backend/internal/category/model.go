@@ -0,0 +1,35 @@
+package category
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"werk-ticketing/internal/database"
+)
+
+// Override holds the local presentation of an InvGate category, shown instead
+// of or in addition to what InvGate returns.
+type Override struct {
+	ID          string    `gorm:"type:char(36);primaryKey"`
+	TenantID    string    `gorm:"type:char(36);not null;default:'';uniqueIndex:idx_tenant_category"`
+	CategoryID  int       `gorm:"not null;uniqueIndex:idx_tenant_category"` // InvGate category ID
+	Icon        string    `gorm:"size:100"`                                 // Icon name or URL understood by the client
+	Description string    `gorm:"size:500"`
+	UpdatedBy   string    `gorm:"size:190;column:updated_by"` // Email of the admin who last changed the override
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (Override) TableName() string {
+	return "category_overrides"
+}
+
+// BeforeCreate assigns the UUID in Go so the ID is known to the caller after insert.
+func (o *Override) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == "" {
+		o.ID = database.NewUUID()
+	}
+	return nil
+}
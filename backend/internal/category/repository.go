@@ -0,0 +1,55 @@
+package category
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// Repository abstracts data persistence for category overrides.
+type Repository interface {
+	ListByTenant(ctx context.Context, tenantID string) ([]Override, error)
+	Get(ctx context.Context, tenantID string, categoryID int) (*Override, error)
+	Save(ctx context.Context, override *Override) error
+	Delete(ctx context.Context, tenantID string, categoryID int) error
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository builds a Gorm-backed category override repository.
+func NewRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+func (r *gormRepository) ListByTenant(ctx context.Context, tenantID string) ([]Override, error) {
+	var overrides []Override
+	err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).Find(&overrides).Error
+	return overrides, err
+}
+
+func (r *gormRepository) Get(ctx context.Context, tenantID string, categoryID int) (*Override, error) {
+	var o Override
+	err := r.db.WithContext(ctx).Where("tenant_id = ? AND category_id = ?", tenantID, categoryID).First(&o).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &o, nil
+}
+
+// Save inserts the override or updates it when it already has an ID.
+func (r *gormRepository) Save(ctx context.Context, override *Override) error {
+	if override.ID == "" {
+		return r.db.WithContext(ctx).Create(override).Error
+	}
+	return r.db.WithContext(ctx).Save(override).Error
+}
+
+func (r *gormRepository) Delete(ctx context.Context, tenantID string, categoryID int) error {
+	return r.db.WithContext(ctx).Delete(&Override{}, "tenant_id = ? AND category_id = ?", tenantID, categoryID).Error
+}
@@ -0,0 +1,225 @@
+package category
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/invgate"
+	"werk-ticketing/internal/tenant"
+)
+
+// Service builds the category tree of the tenant and manages which categories
+// are offered and how they are presented.
+type Service interface {
+	GetTree(ctx context.Context) ([]*Node, error)
+	GetAdminTree(ctx context.Context) ([]*Node, error)
+	UpdateAllowed(ctx context.Context, actorEmail string, categoryIDs []int) ([]*Node, error)
+	UpdateOverride(ctx context.Context, categoryID int, actorEmail string, req UpdateOverrideRequest) (*Node, error)
+}
+
+type service struct {
+	client     invgate.Service
+	repository Repository
+	tenants    tenant.Service
+	logger     *logrus.Logger
+}
+
+// NewService instantiates category service.
+func NewService(client invgate.Service, repository Repository, tenants tenant.Service, logger *logrus.Logger) Service {
+	return &service{
+		client:     client,
+		repository: repository,
+		tenants:    tenants,
+		logger:     logger,
+	}
+}
+
+// GetTree returns the categories users of the tenant may create tickets in.
+func (s *service) GetTree(ctx context.Context) ([]*Node, error) {
+	nodes, err := s.loadNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make(map[int]bool)
+	if t := tenant.FromContext(ctx); t != nil {
+		for _, id := range t.AllowedCategoryIDs() {
+			allowed[id] = true
+		}
+	}
+
+	filtered := make([]*Node, 0, len(nodes))
+	for _, n := range nodes {
+		if allowed[n.ID] {
+			filtered = append(filtered, n)
+		}
+	}
+	return buildTree(filtered), nil
+}
+
+// GetAdminTree returns all InvGate categories, each marked with whether it is allowed.
+func (s *service) GetAdminTree(ctx context.Context) ([]*Node, error) {
+	nodes, err := s.loadNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	t := tenant.FromContext(ctx)
+	for _, n := range nodes {
+		allowed := t != nil && t.IsCategoryAllowed(n.ID)
+		n.Allowed = &allowed
+	}
+	return buildTree(nodes), nil
+}
+
+// UpdateAllowed replaces the categories users of the tenant may create tickets in.
+func (s *service) UpdateAllowed(ctx context.Context, actorEmail string, categoryIDs []int) ([]*Node, error) {
+	t := tenant.FromContext(ctx)
+	if t == nil {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"tenant not resolved",
+			nil,
+		)
+	}
+
+	nodes, err := s.loadNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[int]bool, len(nodes))
+	for _, n := range nodes {
+		known[n.ID] = true
+	}
+
+	for _, id := range categoryIDs {
+		if !known[id] {
+			return nil, errors.NewAppError(
+				errors.ErrCodeInvalidInput,
+				"unknown category_id "+strconv.Itoa(id),
+				nil,
+			)
+		}
+	}
+
+	updated, err := s.tenants.SetAllowedCategories(ctx, t.ID, actorEmail, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"tenantID":    t.ID,
+		"categoryIDs": updated.AllowedCategories,
+		"actorEmail":  actorEmail,
+	}).Info("category allowlist updated")
+
+	return s.GetAdminTree(tenant.NewContext(ctx, updated))
+}
+
+// UpdateOverride sets the local icon and description of a category. Clearing
+// both removes the override.
+func (s *service) UpdateOverride(ctx context.Context, categoryID int, actorEmail string, req UpdateOverrideRequest) (*Node, error) {
+	nodes, err := s.loadNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var node *Node
+	for _, n := range nodes {
+		if n.ID == categoryID {
+			node = n
+			break
+		}
+	}
+	if node == nil {
+		return nil, errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"category not found",
+			nil,
+		)
+	}
+
+	tenantID := tenant.IDFromContext(ctx)
+	icon := strings.TrimSpace(req.Icon)
+	description := strings.TrimSpace(req.Description)
+
+	if icon == "" && description == "" {
+		if err := s.repository.Delete(ctx, tenantID, categoryID); err != nil {
+			s.logger.WithError(err).WithField("categoryID", categoryID).Error("failed to delete category override")
+			return nil, errors.NewAppError(
+				errors.ErrCodeInternal,
+				"failed to update category",
+				err,
+			)
+		}
+	} else {
+		override, err := s.repository.Get(ctx, tenantID, categoryID)
+		if err != nil {
+			s.logger.WithError(err).WithField("categoryID", categoryID).Error("failed to get category override")
+			return nil, errors.NewAppError(
+				errors.ErrCodeInternal,
+				"failed to update category",
+				err,
+			)
+		}
+		if override == nil {
+			override = &Override{TenantID: tenantID, CategoryID: categoryID}
+		}
+		override.Icon = icon
+		override.Description = description
+		override.UpdatedBy = actorEmail
+
+		if err := s.repository.Save(ctx, override); err != nil {
+			s.logger.WithError(err).WithField("categoryID", categoryID).Error("failed to save category override")
+			return nil, errors.NewAppError(
+				errors.ErrCodeInternal,
+				"failed to update category",
+				err,
+			)
+		}
+	}
+
+	s.logger.WithField("categoryID", categoryID).WithField("actorEmail", actorEmail).Info("category override updated")
+
+	node.Icon = icon
+	node.Description = description
+	t := tenant.FromContext(ctx)
+	allowed := t != nil && t.IsCategoryAllowed(categoryID)
+	node.Allowed = &allowed
+	return node, nil
+}
+
+// loadNodes fetches the InvGate categories and applies the local overrides of the tenant.
+func (s *service) loadNodes(ctx context.Context) ([]*Node, error) {
+	resp, err := s.client.GetCategories(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get categories from InvGate")
+		return nil, errors.NewAppError(
+			errors.ErrCodeExternalService,
+			"failed to fetch categories from external service",
+			err,
+		)
+	}
+	nodes := parseCategories(resp)
+
+	overrides, err := s.repository.ListByTenant(ctx, tenant.IDFromContext(ctx))
+	if err != nil {
+		// Categories stay usable without their local icons and descriptions
+		s.logger.WithError(err).Warn("failed to load category overrides")
+		return nodes, nil
+	}
+	byID := make(map[int]*Override, len(overrides))
+	for i := range overrides {
+		byID[overrides[i].CategoryID] = &overrides[i]
+	}
+	for _, n := range nodes {
+		if o, ok := byID[n.ID]; ok {
+			n.Icon = o.Icon
+			n.Description = o.Description
+		}
+	}
+	return nodes, nil
+}
@@ -0,0 +1,111 @@
+package category
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// parseCategories reads the categories of an InvGate categories response,
+// which is either a plain list or a list wrapped in "data" or "categories".
+func parseCategories(resp map[string]interface{}) []*Node {
+	var items []interface{}
+	if arr, ok := resp["data"].([]interface{}); ok {
+		items = arr
+	} else if arr, ok := resp["categories"].([]interface{}); ok {
+		items = arr
+	} else {
+		for _, v := range resp {
+			if arr, ok := v.([]interface{}); ok {
+				items = arr
+				break
+			}
+		}
+	}
+
+	nodes := make([]*Node, 0, len(items))
+	for _, item := range items {
+		raw, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, ok := toInt(raw["id"])
+		if !ok || id <= 0 {
+			continue
+		}
+		parentID, _ := toInt(raw["parent_category_id"])
+		name, _ := raw["name"].(string)
+
+		nodes = append(nodes, &Node{
+			ID:               id,
+			Name:             name,
+			ParentCategoryID: parentID,
+			Children:         []*Node{},
+		})
+	}
+	return nodes
+}
+
+// buildTree links nodes to their parents and returns the roots. A node whose
+// parent is not part of nodes, e.g. because the parent is not allowed, becomes
+// a root itself. Siblings are sorted by name.
+func buildTree(nodes []*Node) []*Node {
+	byID := make(map[int]*Node, len(nodes))
+	for _, n := range nodes {
+		byID[n.ID] = n
+	}
+
+	roots := []*Node{}
+	for _, n := range nodes {
+		parent, ok := byID[n.ParentCategoryID]
+		if !ok || parent == n || isDescendant(parent, n, byID) {
+			roots = append(roots, n)
+			continue
+		}
+		parent.Children = append(parent.Children, n)
+	}
+
+	sortNodes(roots)
+	return roots
+}
+
+// isDescendant reports whether node is below ancestor, following the parent
+// IDs. It guards against cycles in the InvGate data.
+func isDescendant(node, ancestor *Node, byID map[int]*Node) bool {
+	seen := make(map[int]bool)
+	for cur := node; cur != nil && !seen[cur.ID]; cur = byID[cur.ParentCategoryID] {
+		if cur == ancestor {
+			return true
+		}
+		seen[cur.ID] = true
+	}
+	return false
+}
+
+func sortNodes(nodes []*Node) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return strings.ToLower(nodes[i].Name) < strings.ToLower(nodes[j].Name)
+	})
+	for _, n := range nodes {
+		sortNodes(n.Children)
+	}
+}
+
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	case string:
+		parsed, err := strconv.Atoi(n)
+		if err != nil {
+			return 0, false
+		}
+		return parsed, true
+	default:
+		return 0, false
+	}
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"strconv"
@@ -20,6 +23,27 @@ type Config struct {
 
 	JWTSecret string
 
+	JWTKeyDir     string // Directory with PEM keys for RS256/EdDSA signing, HS256 with JWTSecret when empty
+	JWTSigningKID string // kid of the signing key, defaults to the last key file by name
+
+	TokenBlacklistBackend string // memory, mysql or redis
+
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+
+	CacheBackend    string // memory or redis, for InvGate reference data
+	CacheMaxEntries int    // Size of the memory backend
+
+	AppBaseURL string // Base URL of the client app, used for links in emails
+
+	MailBackend  string // log or smtp
+	MailFrom     string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+
 	ArmMadaBaseURL  string
 	ArmMadaUsername string
 	ArmMadaPassword string
@@ -28,6 +52,44 @@ type Config struct {
 	ArmMadaCompanyID  int
 	ArmMadaGroupID    int
 	ArmMadaLocationID int
+
+	// Email domains that may register without an invite, each mapped to its
+	// InvGate scope: domain=company[:group[:location]], comma separated.
+	// Omitted IDs fall back to the ARMMADA_* defaults above.
+	RegistrationDomains string
+
+	// InvGate category IDs offered to users of the default tenant, comma separated.
+	// Only used when the default tenant is created, admins edit the list afterwards.
+	AllowedCategoryIDs string
+
+	// Default tenant, backed by the ARMMADA_* connection above. Requests for
+	// hosts not claimed by any tenant are served by it as well.
+	DefaultTenantName  string
+	DefaultTenantHosts string // Comma separated
+
+	// Assign new users to the default InvGate company/group/location only after
+	// they verified their email, instead of right at registration.
+	DeferScopesUntilVerified bool
+
+	MFAIssuer string // Issuer shown in authenticator apps
+
+	DataExportDir string // Directory where personal data export ZIP files are written
+
+	// OpenID Connect single sign-on, enabled when issuer and client ID are set
+	OIDCIssuerURL    string
+	OIDCClientID     string
+	OIDCClientSecret string
+	OIDCRedirectURL  string
+	OIDCScopes       string // Space separated
+
+	CursorSecret string // Signs pagination cursors, derived from JWT_SECRET when empty
+
+	TenantSecretKey string // Base64 AES-256 key encrypting tenant InvGate passwords in the database
+}
+
+// OIDCEnabled reports whether single sign-on is configured.
+func (c *Config) OIDCEnabled() bool {
+	return c.OIDCIssuerURL != "" && c.OIDCClientID != ""
 }
 
 // Load loads configuration from environment variables (optionally via .env files).
@@ -50,10 +112,80 @@ func Load() (*Config, error) {
 		ArmMadaCompanyID:  getEnvInt("ARMMADA_COMPANY_ID", 135),
 		ArmMadaGroupID:    getEnvInt("ARMMADA_GROUP_ID", 134),
 		ArmMadaLocationID: getEnvInt("ARMMADA_LOCATION_ID", 136),
+
+		JWTKeyDir:     getEnv("JWT_KEY_DIR", ""),
+		JWTSigningKID: getEnv("JWT_SIGNING_KID", ""),
+
+		TokenBlacklistBackend: getEnv("TOKEN_BLACKLIST_BACKEND", "memory"),
+		RedisAddr:             getEnv("REDIS_ADDR", "localhost:6379"),
+		RedisPassword:         getEnv("REDIS_PASSWORD", ""),
+		RedisDB:               getEnvInt("REDIS_DB", 0),
+
+		CacheBackend:    getEnv("CACHE_BACKEND", "memory"),
+		CacheMaxEntries: getEnvInt("CACHE_MAX_ENTRIES", 1000),
+
+		AppBaseURL: getEnv("APP_BASE_URL", "http://localhost:8080"),
+
+		MailBackend:  getEnv("MAIL_BACKEND", "log"),
+		MailFrom:     getEnv("MAIL_FROM", ""),
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnvInt("SMTP_PORT", 587),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+
+		RegistrationDomains: getEnv("REGISTRATION_DOMAINS", ""),
+
+		AllowedCategoryIDs: getEnv("ALLOWED_CATEGORY_IDS", "115,116,117,118,119,120,121,122,123"),
+
+		DefaultTenantName:  getEnv("DEFAULT_TENANT_NAME", "Armmada"),
+		DefaultTenantHosts: getEnv("DEFAULT_TENANT_HOSTS", ""),
+
+		DeferScopesUntilVerified: getEnvBool("DEFER_INVGATE_SCOPES_UNTIL_VERIFIED", false),
+
+		MFAIssuer: getEnv("MFA_ISSUER", "Werk Ticketing"),
+
+		DataExportDir: getEnv("DATA_EXPORT_DIR", "./data/exports"),
+
+		OIDCIssuerURL:    getEnv("OIDC_ISSUER_URL", ""),
+		OIDCClientID:     getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:  getEnv("OIDC_REDIRECT_URL", ""),
+		OIDCScopes:       getEnv("OIDC_SCOPES", "openid email profile"),
+
+		CursorSecret: getEnv("CURSOR_SECRET", ""),
+
+		TenantSecretKey: getEnv("TENANT_SECRET_KEY", ""),
+	}
+
+	if cfg.JWTSecret == "" && cfg.JWTKeyDir == "" {
+		return nil, fmt.Errorf("JWT_SECRET or JWT_KEY_DIR must be provided")
+	}
+
+	if cfg.CursorSecret == "" && cfg.JWTSecret != "" {
+		cfg.CursorSecret = deriveSecret(cfg.JWTSecret, "pagination-cursor")
+	}
+	if cfg.CursorSecret == "" {
+		return nil, fmt.Errorf("CURSOR_SECRET must be provided when JWT_SECRET is empty")
+	}
+
+	if cfg.TenantSecretKey == "" {
+		return nil, fmt.Errorf("TENANT_SECRET_KEY must be provided")
+	}
+
+	switch cfg.TokenBlacklistBackend {
+	case "memory", "mysql", "redis":
+	default:
+		return nil, fmt.Errorf("TOKEN_BLACKLIST_BACKEND must be one of memory, mysql, redis")
+	}
+
+	switch cfg.CacheBackend {
+	case "memory", "redis":
+	default:
+		return nil, fmt.Errorf("CACHE_BACKEND must be one of memory, redis")
 	}
 
-	if cfg.JWTSecret == "" {
-		return nil, fmt.Errorf("JWT_SECRET must be provided")
+	if cfg.OIDCEnabled() && cfg.OIDCRedirectURL == "" {
+		return nil, fmt.Errorf("OIDC_REDIRECT_URL must be provided when OIDC is enabled")
 	}
 
 	if cfg.ArmMadaBaseURL == "" || cfg.ArmMadaUsername == "" || cfg.ArmMadaPassword == "" {
@@ -63,6 +195,14 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// deriveSecret derives a key for one purpose from a shared secret, so a value
+// signed with it is never also valid for the shared secret itself.
+func deriveSecret(secret, purpose string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func getEnv(key, fallback string) string {
 	val := os.Getenv(key)
 	if val == "" {
@@ -84,3 +224,17 @@ func getEnvInt(key string, fallback int) int {
 
 	return parsed
 }
+
+func getEnvBool(key string, fallback bool) bool {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseBool(val)
+	if err != nil {
+		return fallback
+	}
+
+	return parsed
+}
@@ -4,8 +4,99 @@ import "time"
 
 // JWT token expiration
 const (
-	JWTExpiration      = 15 * time.Minute // 15 minutes
-	JWTRefreshExpiration = 8760 * time.Hour // 1 year (365 days * 24 hours)
+	JWTExpiration        = 15 * time.Minute    // 15 minutes
+	JWTRefreshExpiration = 30 * 24 * time.Hour // 30 days, extended on every refresh token rotation
+)
+
+// Session activity
+const (
+	SessionTouchInterval = time.Minute // LastSeenAt is written at most this often per session
+)
+
+// Token blacklist
+const (
+	TokenBlacklistSweepInterval = 10 * time.Minute
+)
+
+// JWT signing keys
+const (
+	JWTKeyReloadInterval = 5 * time.Minute
+)
+
+// Password reset and email verification
+const (
+	PasswordResetTokenExpiration     = 30 * time.Minute
+	EmailVerificationTokenExpiration = 24 * time.Hour
+)
+
+// Failed login protection
+const (
+	LoginAttemptWindow      = time.Hour        // Failures older than this are forgotten
+	LoginBackoffThreshold   = 3                // Failures per email before exponential backoff kicks in
+	LoginIPBackoffThreshold = 15               // Failures per IP before backoff, higher since many users can share an IP
+	LoginBackoffBase        = time.Second      // Delay after the first backoff failure, doubled on each further failure
+	LoginBackoffMax         = 5 * time.Minute  // Upper bound for the backoff delay
+	LoginLockThreshold      = 10               // Failures per email before the account is locked
+	LoginLockDuration       = 15 * time.Minute // How long a locked account stays locked
+)
+
+// Two-factor authentication
+const (
+	MFAChallengeExpiration = 5 * time.Minute // Lifetime of the token issued between password and TOTP step
+	MFAClockSkewSteps      = 1               // Accepted TOTP steps before/after the current one
+	MFARecoveryCodeCount   = 10
+)
+
+// Personal API keys
+const (
+	APIKeyDefaultExpiration = 90 * 24 * time.Hour  // Used when no expiry is requested
+	APIKeyMaxExpiration     = 365 * 24 * time.Hour // Keys cannot live longer than this
+)
+
+// Registration invite codes
+const (
+	InviteDefaultExpiration = 7 * 24 * time.Hour  // Used when no expiry is requested
+	InviteMaxExpiration     = 90 * 24 * time.Hour // Invites cannot live longer than this
+)
+
+// Tenants
+const (
+	TenantCacheTTL = time.Minute // How long tenant lookups are served from memory
+)
+
+// InvGate reference data cache (categories, articles, ticket attributes)
+const (
+	ReferenceCacheTTL      = 5 * time.Minute // Served without contacting InvGate
+	ReferenceCacheStaleTTL = time.Hour       // Served while reloading in the background
+	ReferenceCacheMaxAge   = time.Minute     // Cache-Control max-age sent to clients
+)
+
+// Ticket types, priorities and statuses synced from InvGate
+const (
+	TicketMetaCacheTTL        = time.Hour        // Entries older than this are reloaded on request
+	TicketMetaRefreshInterval = 15 * time.Minute // Background refresh of all cached tenants
+	TicketMetaRetryInterval   = time.Minute      // Built-in defaults are served this long after a failed load
+)
+
+// Personal data export
+const (
+	DataExportExpiration    = 7 * 24 * time.Hour // How long a finished export can be downloaded
+	DataExportTimeout       = 10 * time.Minute   // Upper bound for building one export
+	DataExportSweepInterval = time.Hour          // Cleanup of expired files and requeue of stuck exports
+)
+
+// Ticket listing
+const (
+	TicketListBatchTimeout  = 10 * time.Second // Batch fetch of the incidents of one page
+	TicketDetailTimeout     = 8 * time.Second  // Each single incident fetch, including retries
+	TicketDetailConcurrency = 5                // Parallel single incident fetches per listing
+)
+
+// Ticket read model sync
+const (
+	TicketSyncInterval    = 2 * time.Minute    // Pause between sync rounds
+	TicketSyncBatchSize   = 200                // Tickets refreshed per round
+	TicketSyncClosedGrace = 7 * 24 * time.Hour // Closed tickets are no longer refreshed after this
 )
 
 // HTTP timeout
@@ -15,6 +15,10 @@ const (
 	ErrCodeExternalService    = "EXTERNAL_SERVICE_ERROR"
 	ErrCodeEmailAlreadyExist  = "EMAIL_ALREADY_EXIST"
 	ErrCodeInvalidCredentials = "INVALID_CREDENTIALS"
+	ErrCodeEmailNotVerified   = "EMAIL_NOT_VERIFIED"
+	ErrCodeAccountLocked      = "ACCOUNT_LOCKED"
+	ErrCodeTooManyAttempts    = "TOO_MANY_ATTEMPTS"
+	ErrCodeAccountDisabled    = "ACCOUNT_DISABLED"
 )
 
 // Predefined errors
@@ -0,0 +1,78 @@
+package invgate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+
+	"werk-ticketing/internal/cache"
+)
+
+// cachedService serves InvGate reference data, which rarely changes, from a
+// cache. All other calls go straight to the wrapped service.
+type cachedService struct {
+	Service
+	cache cache.Cache
+}
+
+// WithCache wraps client so categories, articles, priorities, types and
+// statuses are cached per InvGate connection.
+func WithCache(client Service, c cache.Cache) Service {
+	return &cachedService{Service: client, cache: c}
+}
+
+// CacheKeyPrefix returns the prefix of all cache keys of the InvGate
+// connection of ctx, e.g. to purge the cached data of one tenant.
+func CacheKeyPrefix(ctx context.Context) string {
+	conn, _ := ctx.Value(connectionKey{}).(Connection)
+	if conn.BaseURL == "" {
+		return "invgate:default:"
+	}
+	sum := sha256.Sum256([]byte(conn.BaseURL + "\x00" + conn.Username))
+	return "invgate:" + hex.EncodeToString(sum[:8]) + ":"
+}
+
+func (s *cachedService) GetCategories(ctx context.Context) (map[string]interface{}, error) {
+	return s.fetch(ctx, "categories", s.Service.GetCategories)
+}
+
+func (s *cachedService) GetPriorities(ctx context.Context) (map[string]interface{}, error) {
+	return s.fetch(ctx, "priorities", s.Service.GetPriorities)
+}
+
+func (s *cachedService) GetIncidentTypes(ctx context.Context) (map[string]interface{}, error) {
+	return s.fetch(ctx, "types", s.Service.GetIncidentTypes)
+}
+
+func (s *cachedService) GetStatuses(ctx context.Context) (map[string]interface{}, error) {
+	return s.fetch(ctx, "statuses", s.Service.GetStatuses)
+}
+
+func (s *cachedService) GetArticlesByCategory(ctx context.Context, categoryID int) (map[string]interface{}, error) {
+	return s.fetch(ctx, "articles:"+strconv.Itoa(categoryID), func(ctx context.Context) (map[string]interface{}, error) {
+		return s.Service.GetArticlesByCategory(ctx, categoryID)
+	})
+}
+
+// fetch returns a freshly decoded copy of the cached response on every call,
+// so callers may modify it.
+func (s *cachedService) fetch(ctx context.Context, name string, load func(ctx context.Context) (map[string]interface{}, error)) (map[string]interface{}, error) {
+	raw, err := s.cache.Fetch(ctx, CacheKeyPrefix(ctx)+name, func(ctx context.Context) ([]byte, error) {
+		resp, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(resp)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
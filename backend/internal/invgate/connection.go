@@ -0,0 +1,33 @@
+package invgate
+
+import "context"
+
+// Connection holds the InvGate instance and credentials requests are sent to.
+type Connection struct {
+	BaseURL  string // Must end with a slash, paths are appended as-is
+	Username string
+	Password string
+	PageKey  string
+}
+
+type connectionKey struct{}
+
+// WithConnection returns a context whose InvGate requests use conn instead of
+// the connection from the configuration. Used to serve several tenants, each
+// with its own InvGate instance, from one client.
+func WithConnection(ctx context.Context, conn Connection) context.Context {
+	return context.WithValue(ctx, connectionKey{}, conn)
+}
+
+// connection returns the connection set on ctx, or the configured default.
+func (s *service) connection(ctx context.Context) Connection {
+	if conn, ok := ctx.Value(connectionKey{}).(Connection); ok && conn.BaseURL != "" {
+		return conn
+	}
+	return Connection{
+		BaseURL:  s.cfg.ArmMadaBaseURL,
+		Username: s.cfg.ArmMadaUsername,
+		Password: s.cfg.ArmMadaPassword,
+		PageKey:  s.cfg.ArmMadaPageKey,
+	}
+}
@@ -15,8 +15,11 @@ import (
 type Service interface {
 	CreateUser(ctx context.Context, payload CreateUserPayload) (map[string]interface{}, error)
 	DeleteUser(ctx context.Context, userID int) error
+	DisableUser(ctx context.Context, userID int) error
 	GetUser(ctx context.Context, userID int) (map[string]interface{}, error)
 	GetUserByEmail(ctx context.Context, email string) (map[string]interface{}, error)
+	UpdateUser(ctx context.Context, payload UpdateUserPayload) error
+	UpdateUserPassword(ctx context.Context, userID int, password string) error
 	CreateTicket(ctx context.Context, payload CreateTicketPayload) (map[string]interface{}, error)
 	CreateTicketWithAttachments(ctx context.Context, payload CreateTicketPayload, files []*multipart.FileHeader) (map[string]interface{}, error)
 	UpdateTicket(ctx context.Context, payload UpdateTicketPayload) (map[string]interface{}, error)
@@ -24,7 +27,11 @@ type Service interface {
 	SolutionReject(ctx context.Context, payload SolutionRejectPayload) (map[string]interface{}, error)
 	GetTicketList(ctx context.Context, filters url.Values) (map[string]interface{}, error)
 	GetTicketDetail(ctx context.Context, ticketID string) (map[string]interface{}, error)
+	GetTicketsByIDs(ctx context.Context, ticketIDs []string) (map[string]map[string]interface{}, error)
 	GetCategories(ctx context.Context) (map[string]interface{}, error)
+	GetPriorities(ctx context.Context) (map[string]interface{}, error)
+	GetIncidentTypes(ctx context.Context) (map[string]interface{}, error)
+	GetStatuses(ctx context.Context) (map[string]interface{}, error)
 	AddTicketComment(ctx context.Context, requestID, authorID int, comment string, files []*multipart.FileHeader) (map[string]interface{}, error)
 	GetTicketComments(ctx context.Context, requestID int) (map[string]interface{}, error)
 	GetTicketAttachment(ctx context.Context, attachmentID string) ([]byte, string, string, error)
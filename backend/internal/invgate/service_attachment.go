@@ -25,7 +25,8 @@ func (s *service) GetTicketAttachmentInfo(ctx context.Context, attachmentID stri
 	params := url.Values{}
 	params.Set("id", attachmentID)
 
-	fullURL := s.cfg.ArmMadaBaseURL + "incident.attachment"
+	conn := s.connection(ctx)
+	fullURL := conn.BaseURL + "incident.attachment"
 	if len(params) > 0 {
 		fullURL = fmt.Sprintf("%s?%s", fullURL, params.Encode())
 	}
@@ -35,7 +36,7 @@ func (s *service) GetTicketAttachmentInfo(ctx context.Context, attachmentID stri
 		return nil, err
 	}
 
-	req.SetBasicAuth(s.cfg.ArmMadaUsername, s.cfg.ArmMadaPassword)
+	req.SetBasicAuth(conn.Username, conn.Password)
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := s.client.Do(req)
@@ -62,7 +62,8 @@ func (s *service) doRawRequestWithRetry(ctx context.Context, method, path string
 }
 
 func (s *service) doRawRequestSingle(ctx context.Context, method, path string, params url.Values, body io.Reader, contentType string) (map[string]interface{}, error, int) {
-	fullURL := s.cfg.ArmMadaBaseURL + path
+	conn := s.connection(ctx)
+	fullURL := conn.BaseURL + path
 	if len(params) > 0 {
 		fullURL = fmt.Sprintf("%s?%s", fullURL, params.Encode())
 	}
@@ -72,7 +73,7 @@ func (s *service) doRawRequestSingle(ctx context.Context, method, path string, p
 		return nil, err, 0
 	}
 
-	req.SetBasicAuth(s.cfg.ArmMadaUsername, s.cfg.ArmMadaPassword)
+	req.SetBasicAuth(conn.Username, conn.Password)
 	if contentType != "" {
 		req.Header.Set("Content-Type", contentType)
 	}
@@ -109,7 +110,8 @@ func (s *service) doRawRequestSingle(ctx context.Context, method, path string, p
 }
 
 func (s *service) doRawRequestBytes(ctx context.Context, method, path string, params url.Values) ([]byte, string, string, error) {
-	fullURL := s.cfg.ArmMadaBaseURL + path
+	conn := s.connection(ctx)
+	fullURL := conn.BaseURL + path
 	if len(params) > 0 {
 		fullURL = fmt.Sprintf("%s?%s", fullURL, params.Encode())
 	}
@@ -119,7 +121,7 @@ func (s *service) doRawRequestBytes(ctx context.Context, method, path string, pa
 		return nil, "", "", err
 	}
 
-	req.SetBasicAuth(s.cfg.ArmMadaUsername, s.cfg.ArmMadaPassword)
+	req.SetBasicAuth(conn.Username, conn.Password)
 
 	resp, err := s.client.Do(req)
 	if err != nil {
@@ -43,8 +43,8 @@ func (s *service) GetTicketList(ctx context.Context, filters url.Values) (map[st
 	if filters == nil {
 		filters = url.Values{}
 	}
-	if s.cfg.ArmMadaPageKey != "" && filters.Get("page_key") == "" {
-		filters.Set("page_key", s.cfg.ArmMadaPageKey)
+	if pageKey := s.connection(ctx).PageKey; pageKey != "" && filters.Get("page_key") == "" {
+		filters.Set("page_key", pageKey)
 	}
 	return s.doRequest(ctx, http.MethodGet, "incidents", nil, filters)
 }
@@ -55,10 +55,76 @@ func (s *service) GetTicketDetail(ctx context.Context, ticketID string) (map[str
 	return s.doRequest(ctx, http.MethodGet, "incident", nil, params)
 }
 
+// GetTicketsByIDs fetches several incidents in one request and returns them
+// keyed by InvGate ID. IDs InvGate does not return are missing from the map.
+func (s *service) GetTicketsByIDs(ctx context.Context, ticketIDs []string) (map[string]map[string]interface{}, error) {
+	result := make(map[string]map[string]interface{}, len(ticketIDs))
+	if len(ticketIDs) == 0 {
+		return result, nil
+	}
+
+	params := url.Values{}
+	for _, id := range ticketIDs {
+		params.Add("ids[]", id)
+	}
+	resp, err := s.doRequest(ctx, http.MethodGet, "incidents", nil, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// InvGate answers either with a list or with an object keyed by incident ID
+	if items, ok := resp["data"].([]interface{}); ok {
+		for _, item := range items {
+			if incident, ok := item.(map[string]interface{}); ok {
+				if id := incidentID(incident["id"]); id != "" {
+					result[id] = incident
+				}
+			}
+		}
+		return result, nil
+	}
+	for key, item := range resp {
+		if incident, ok := item.(map[string]interface{}); ok {
+			id := incidentID(incident["id"])
+			if id == "" {
+				id = key
+			}
+			result[id] = incident
+		}
+	}
+	return result, nil
+}
+
+func incidentID(v interface{}) string {
+	switch id := v.(type) {
+	case string:
+		return id
+	case float64:
+		return strconv.FormatInt(int64(id), 10)
+	default:
+		return ""
+	}
+}
+
 func (s *service) GetCategories(ctx context.Context) (map[string]interface{}, error) {
 	return s.doRequest(ctx, http.MethodGet, "categories", nil, nil)
 }
 
+// GetPriorities returns the incident priorities configured in InvGate.
+func (s *service) GetPriorities(ctx context.Context) (map[string]interface{}, error) {
+	return s.doRequest(ctx, http.MethodGet, "incident.attributes.priority", nil, nil)
+}
+
+// GetIncidentTypes returns the incident types configured in InvGate.
+func (s *service) GetIncidentTypes(ctx context.Context) (map[string]interface{}, error) {
+	return s.doRequest(ctx, http.MethodGet, "incident.attributes.type", nil, nil)
+}
+
+// GetStatuses returns the incident statuses configured in InvGate.
+func (s *service) GetStatuses(ctx context.Context) (map[string]interface{}, error) {
+	return s.doRequest(ctx, http.MethodGet, "incident.attributes.status", nil, nil)
+}
+
 func (s *service) GetArticlesByCategory(ctx context.Context, categoryID int) (map[string]interface{}, error) {
 	params := url.Values{}
 	params.Set("category_id", strconv.Itoa(categoryID))
@@ -18,12 +18,35 @@ func (s *service) DeleteUser(ctx context.Context, userID int) error {
 	return err
 }
 
+// DisableUser deactivates an InvGate user, used when it cannot be deleted.
+func (s *service) DisableUser(ctx context.Context, userID int) error {
+	payload := UserIDPayload{ID: userID}
+	_, err := s.doRequest(ctx, http.MethodPut, "user.disable", payload, nil)
+	return err
+}
+
 func (s *service) GetUser(ctx context.Context, userID int) (map[string]interface{}, error) {
 	params := url.Values{}
 	params.Set("id", strconv.Itoa(userID))
 	return s.doRequest(ctx, http.MethodGet, "user", nil, params)
 }
 
+// UpdateUser changes the profile fields of an InvGate user.
+func (s *service) UpdateUser(ctx context.Context, payload UpdateUserPayload) error {
+	_, err := s.doRequest(ctx, http.MethodPut, "user", payload, nil)
+	return err
+}
+
+// UpdateUserPassword sets a new password for an InvGate user.
+func (s *service) UpdateUserPassword(ctx context.Context, userID int, password string) error {
+	payload := UpdateUserPasswordPayload{
+		ID:       userID,
+		Password: password,
+	}
+	_, err := s.doRequest(ctx, http.MethodPut, "user.password", payload, nil)
+	return err
+}
+
 func (s *service) GetUserByEmail(ctx context.Context, email string) (map[string]interface{}, error) {
 	params := url.Values{}
 	params.Set("email", email)
@@ -25,6 +25,26 @@ type CreateUserPayload struct {
 	Pass     string `json:"pass,omitempty"`
 }
 
+// UpdateUserPayload represents the payload to change the profile of an InvGate user.
+type UpdateUserPayload struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	LastName string `json:"lastname"`
+	Phone    string `json:"phone"`
+	Language string `json:"language,omitempty"`
+}
+
+// UserIDPayload identifies an InvGate user in request bodies.
+type UserIDPayload struct {
+	ID int `json:"id"`
+}
+
+// UpdateUserPasswordPayload represents the payload to change the password of an InvGate user.
+type UpdateUserPasswordPayload struct {
+	ID       int    `json:"id"`
+	Password string `json:"password"`
+}
+
 // AssignUsersPayload represents the payload to assign users to companies/groups/locations.
 type AssignUsersPayload struct {
 	ID    int   `json:"id"`
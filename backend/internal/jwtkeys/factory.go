@@ -0,0 +1,57 @@
+package jwtkeys
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/config"
+	"werk-ticketing/internal/constants"
+)
+
+// NewFromConfig builds the key set used to sign and verify JWTs.
+// With JWT_KEY_DIR set, keys are loaded from that directory and reloaded
+// periodically so new keys can be rolled out without a restart. Otherwise
+// tokens are signed with HS256 and JWT_SECRET.
+func NewFromConfig(cfg *config.Config, logger *logrus.Logger) (*KeySet, error) {
+	if cfg.JWTKeyDir == "" {
+		logger.Warn("JWT_KEY_DIR not set, signing tokens with HS256 shared secret")
+		return NewHMACKeySet([]byte(cfg.JWTSecret)), nil
+	}
+
+	keys, activeKID, err := LoadDir(cfg.JWTKeyDir, cfg.JWTSigningKID)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &KeySet{}
+	if err := set.Replace(keys, activeKID); err != nil {
+		return nil, err
+	}
+	logger.WithField("kid", activeKID).WithField("keys", len(keys)).Info("loaded JWT signing keys")
+
+	go reloadKeys(set, cfg.JWTKeyDir, cfg.JWTSigningKID, activeKID, constants.JWTKeyReloadInterval, logger)
+	return set, nil
+}
+
+// reloadKeys re-reads the key directory on an interval. On error the
+// previously loaded keys stay in use.
+func reloadKeys(set *KeySet, dir, signingKID, lastActive string, interval time.Duration, logger *logrus.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		keys, activeKID, err := LoadDir(dir, signingKID)
+		if err == nil {
+			err = set.Replace(keys, activeKID)
+		}
+		if err != nil {
+			logger.WithError(err).Error("failed to reload JWT signing keys")
+			continue
+		}
+		if activeKID != lastActive {
+			logger.WithField("kid", activeKID).Info("JWT signing key rotated")
+		}
+		lastActive = activeKID
+	}
+}
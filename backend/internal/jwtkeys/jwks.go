@@ -0,0 +1,55 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"sort"
+)
+
+// JWKS is a JSON Web Key Set (RFC 7517).
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is a single public key in a JWKS.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+func toJWK(key *Key) (JWK, bool) {
+	switch pub := key.Public.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: key.ID,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: key.ID,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+		}, true
+	default:
+		return JWK{}, false
+	}
+}
+
+func sortJWKs(keys []JWK) {
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Kid < keys[j].Kid })
+}
@@ -0,0 +1,144 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key is a single signing or verification key identified by its kid.
+// Private is nil for verification-only (retired) keys.
+type Key struct {
+	ID       string
+	Method   jwt.SigningMethod
+	Private  crypto.PrivateKey
+	Public   crypto.PublicKey
+	NotAfter time.Time // Retired keys verify tokens until then; zero for the signing key
+}
+
+// expired reports whether a retired key no longer verifies tokens.
+func (k *Key) expired(now time.Time) bool {
+	return !k.NotAfter.IsZero() && !now.Before(k.NotAfter)
+}
+
+// KeySet signs tokens with the active key and verifies tokens with any known key.
+// It is safe for concurrent use; Replace swaps all keys at once on rotation.
+type KeySet struct {
+	mu     sync.RWMutex
+	keys   map[string]*Key
+	active *Key
+}
+
+// NewHMACKeySet builds a key set with a single HS256 secret. It is meant for
+// local development where no key directory is configured.
+func NewHMACKeySet(secret []byte) *KeySet {
+	key := &Key{Method: jwt.SigningMethodHS256, Private: secret, Public: secret}
+	return &KeySet{keys: map[string]*Key{"": key}, active: key}
+}
+
+// Replace installs a new set of keys and the key used for signing.
+func (s *KeySet) Replace(keys map[string]*Key, activeKID string) error {
+	active, ok := keys[activeKID]
+	if !ok || active.Private == nil {
+		return fmt.Errorf("signing key %q not found or has no private key", activeKID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+	s.active = active
+	return nil
+}
+
+// Sign signs claims with the active key and sets the kid header.
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	s.mu.RLock()
+	active := s.active
+	s.mu.RUnlock()
+
+	token := jwt.NewWithClaims(active.Method, claims)
+	if active.ID != "" {
+		token.Header["kid"] = active.ID
+	}
+	return token.SignedString(active.Private)
+}
+
+// Keyfunc resolves the verification key for a parsed token. The token's alg
+// must match the algorithm of the key its kid refers to, so a token cannot
+// pick a weaker or different algorithm than the key was issued for.
+func (s *KeySet) Keyfunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if key.expired(time.Now()) {
+		return nil, fmt.Errorf("signing key %q has expired", kid)
+	}
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %q", t.Method.Alg(), kid)
+	}
+	return key.Public, nil
+}
+
+// ValidMethods returns the algorithms of all known keys, for jwt.WithValidMethods.
+func (s *KeySet) ValidMethods() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	seen := make(map[string]bool)
+	var methods []string
+	for _, key := range s.keys {
+		if key.expired(now) {
+			continue
+		}
+		alg := key.Method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+// JWKS returns the public keys as a JSON Web Key Set. Symmetric keys are
+// never published, expired retired keys are left out.
+func (s *KeySet) JWKS() JWKS {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range s.keys {
+		if key.expired(now) {
+			continue
+		}
+		jwk, ok := toJWK(key)
+		if ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	sortJWKs(set.Keys)
+	return set
+}
+
+func publicKeyOf(private crypto.PrivateKey) (crypto.PublicKey, error) {
+	switch k := private.(type) {
+	case *rsa.PrivateKey:
+		return &k.PublicKey, nil
+	case ed25519.PrivateKey:
+		return k.Public(), nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", private)
+	}
+}
@@ -0,0 +1,159 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"werk-ticketing/internal/constants"
+)
+
+const minRSAKeyBits = 2048
+
+// notAfterHeader is the PEM header that sets until when a retired key
+// verifies tokens, as an RFC 3339 time.
+const notAfterHeader = "Not-After"
+
+// LoadDir reads all *.pem files in dir. The file name without extension is
+// the kid. Private keys (PKCS#8 RSA or Ed25519, or PKCS#1 RSA) can sign;
+// public keys (PKIX) are only used to verify tokens signed before a rotation.
+//
+// The signing key is activeKID, or when empty the private key whose kid sorts
+// last, so naming keys by date (e.g. 2025-01.pem) rotates automatically.
+//
+// All other keys are retired: only their public key is kept, and they verify
+// tokens until their Not-After PEM header or, without one, until the longest
+// token lifetime has passed since the signing key file was written. Expired
+// retired keys are not loaded.
+func LoadDir(dir, activeKID string) (map[string]*Key, string, error) {
+	return loadDir(dir, activeKID, time.Now())
+}
+
+func loadDir(dir, activeKID string, now time.Time) (map[string]*Key, string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, "", fmt.Errorf("read key directory: %w", err)
+	}
+
+	keys := make(map[string]*Key)
+	written := make(map[string]time.Time)
+	var signingKIDs []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".pem") {
+			continue
+		}
+
+		kid := strings.TrimSuffix(entry.Name(), ".pem")
+		key, err := loadKeyFile(filepath.Join(dir, entry.Name()), kid)
+		if err != nil {
+			return nil, "", err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, "", fmt.Errorf("key %s: %w", kid, err)
+		}
+		keys[kid] = key
+		written[kid] = info.ModTime()
+		if key.Private != nil {
+			signingKIDs = append(signingKIDs, kid)
+		}
+	}
+
+	if len(signingKIDs) == 0 {
+		return nil, "", fmt.Errorf("no private signing key found in %s", dir)
+	}
+	if activeKID == "" {
+		sort.Strings(signingKIDs)
+		activeKID = signingKIDs[len(signingKIDs)-1]
+	}
+	active, ok := keys[activeKID]
+	if !ok || active.Private == nil {
+		return nil, "", fmt.Errorf("signing key %q not found or has no private key", activeKID)
+	}
+	active.NotAfter = time.Time{}
+
+	// Tokens signed with a retired key were issued before the signing key
+	// was put in place, so none outlives that time by more than a refresh token.
+	retiredAt := written[activeKID]
+	for kid, key := range keys {
+		if kid == activeKID {
+			continue
+		}
+		key.Private = nil
+		if key.NotAfter.IsZero() {
+			key.NotAfter = retiredAt.Add(constants.JWTRefreshExpiration)
+		}
+		if key.expired(now) {
+			delete(keys, kid)
+		}
+	}
+	return keys, activeKID, nil
+}
+
+func loadKeyFile(path, kid string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key %s: %w", kid, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: no PEM block found", kid)
+	}
+
+	var private crypto.PrivateKey
+	var public crypto.PublicKey
+	switch block.Type {
+	case "PRIVATE KEY":
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		public, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("key %s: unsupported PEM type %q", kid, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", kid, err)
+	}
+
+	var notAfter time.Time
+	if value, ok := block.Headers[notAfterHeader]; ok {
+		notAfter, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: invalid %s header: %w", kid, notAfterHeader, err)
+		}
+	}
+
+	if private != nil {
+		public, err = publicKeyOf(private)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", kid, err)
+		}
+	}
+
+	var method jwt.SigningMethod
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("key %s: RSA keys must have at least %d bits", kid, minRSAKeyBits)
+		}
+		method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("key %s: unsupported key type %T, use RSA or Ed25519", kid, public)
+	}
+
+	return &Key{ID: kid, Method: method, Private: private, Public: public, NotAfter: notAfter}, nil
+}
@@ -0,0 +1,159 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"werk-ticketing/internal/constants"
+)
+
+// writeKey writes a new Ed25519 key as <kid>.pem, private or public only,
+// with the given PEM headers and modification time.
+func writeKey(t *testing.T, dir, kid string, private bool, headers map[string]string, written time.Time) ed25519.PrivateKey {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	block := &pem.Block{Type: "PUBLIC KEY", Headers: headers}
+	if private {
+		block.Type = "PRIVATE KEY"
+		block.Bytes, err = x509.MarshalPKCS8PrivateKey(priv)
+	} else {
+		block.Bytes, err = x509.MarshalPKIXPublicKey(pub)
+	}
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	path := filepath.Join(dir, kid+".pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	if err := os.Chtimes(path, written, written); err != nil {
+		t.Fatalf("set key time: %v", err)
+	}
+	return priv
+}
+
+func TestLoadDirRetiredKeys(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+
+	rotatedAt := now.Add(-10 * 24 * time.Hour)
+	writeKey(t, dir, "2025-01", true, nil, now.Add(-90*24*time.Hour))
+	writeKey(t, dir, "2025-05", true, nil, rotatedAt)
+	writeKey(t, dir, "legacy", false, map[string]string{notAfterHeader: now.Add(time.Hour).Format(time.RFC3339)}, now.Add(-200*24*time.Hour))
+	writeKey(t, dir, "gone", false, map[string]string{notAfterHeader: now.Add(-time.Hour).Format(time.RFC3339)}, rotatedAt)
+
+	keys, activeKID, err := loadDir(dir, "", now)
+	if err != nil {
+		t.Fatalf("loadDir: %v", err)
+	}
+	if activeKID != "2025-05" {
+		t.Fatalf("active kid = %q, want 2025-05", activeKID)
+	}
+
+	active := keys["2025-05"]
+	if active.Private == nil || !active.NotAfter.IsZero() {
+		t.Fatalf("active key = %+v, want a private key without not-after", active)
+	}
+
+	retired, ok := keys["2025-01"]
+	if !ok {
+		t.Fatal("retired key 2025-01 not loaded")
+	}
+	if retired.Private != nil {
+		t.Fatal("retired key kept its private key")
+	}
+	if want := rotatedAt.Add(constants.JWTRefreshExpiration); !retired.NotAfter.Equal(want) {
+		t.Fatalf("retired not-after = %s, want %s", retired.NotAfter, want)
+	}
+
+	if legacy, ok := keys["legacy"]; !ok || !legacy.NotAfter.Equal(now.Add(time.Hour).Truncate(time.Second)) {
+		t.Fatalf("legacy key = %+v, want not-after from its PEM header", legacy)
+	}
+	if _, ok := keys["gone"]; ok {
+		t.Fatal("key past its Not-After header was loaded")
+	}
+}
+
+func TestLoadDirDropsKeysRetiredLongAgo(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+
+	writeKey(t, dir, "2025-01", true, nil, now.Add(-90*24*time.Hour))
+	writeKey(t, dir, "2025-03", true, nil, now.Add(-constants.JWTRefreshExpiration-time.Hour))
+
+	keys, activeKID, err := loadDir(dir, "", now)
+	if err != nil {
+		t.Fatalf("loadDir: %v", err)
+	}
+	if activeKID != "2025-03" || len(keys) != 1 {
+		t.Fatalf("keys = %v (active %q), want only 2025-03", keys, activeKID)
+	}
+}
+
+func TestLoadDirExplicitSigningKey(t *testing.T) {
+	now := time.Now()
+	dir := t.TempDir()
+	writeKey(t, dir, "a", true, nil, now)
+	writeKey(t, dir, "b", false, nil, now)
+
+	if _, _, err := loadDir(dir, "b", now); err == nil {
+		t.Fatal("public key accepted as signing key")
+	}
+	if _, _, err := loadDir(dir, "missing", now); err == nil {
+		t.Fatal("unknown signing key accepted")
+	}
+	keys, activeKID, err := loadDir(dir, "a", now)
+	if err != nil || activeKID != "a" || len(keys) != 2 {
+		t.Fatalf("loadDir = %v, %q, %v", keys, activeKID, err)
+	}
+}
+
+func TestKeySetRejectsExpiredKeys(t *testing.T) {
+	_, activePriv, _ := ed25519.GenerateKey(rand.Reader)
+	_, retiredPriv, _ := ed25519.GenerateKey(rand.Reader)
+
+	keys := map[string]*Key{
+		"active":  {ID: "active", Method: jwt.SigningMethodEdDSA, Private: activePriv, Public: activePriv.Public()},
+		"retired": {ID: "retired", Method: jwt.SigningMethodEdDSA, Public: retiredPriv.Public(), NotAfter: time.Now().Add(-time.Minute)},
+	}
+	set := &KeySet{}
+	if err := set.Replace(keys, "active"); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.RegisteredClaims{Subject: "jane"})
+	token.Header["kid"] = "retired"
+	signed, err := token.SignedString(retiredPriv)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if _, err := jwt.Parse(signed, set.Keyfunc, jwt.WithValidMethods(set.ValidMethods())); err == nil {
+		t.Fatal("token signed with an expired key was accepted")
+	}
+
+	jwks := set.JWKS()
+	if len(jwks.Keys) != 1 || jwks.Keys[0].Kid != "active" {
+		t.Fatalf("JWKS = %+v, want only the active key", jwks.Keys)
+	}
+
+	signed, err = set.Sign(jwt.RegisteredClaims{Subject: "jane"})
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if _, err := jwt.Parse(signed, set.Keyfunc, jwt.WithValidMethods(set.ValidMethods())); err != nil {
+		t.Fatalf("token signed with the active key rejected: %v", err)
+	}
+}
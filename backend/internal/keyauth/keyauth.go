@@ -0,0 +1,15 @@
+// Package keyauth holds what the API key service and the auth middleware
+// share, so neither has to import the other.
+package keyauth
+
+// Prefix marks API keys so they can be told apart from JWTs in a Bearer header.
+const Prefix = "wtk_"
+
+// Identity describes the user an API key acts for.
+type Identity struct {
+	KeyID    string
+	Email    string
+	Role     string
+	TenantID string
+	Scopes   []string
+}
@@ -0,0 +1,51 @@
+package loginattempt
+
+import (
+	"strings"
+	"time"
+)
+
+// Scopes failed login attempts are tracked by.
+const (
+	ScopeEmail = "email"
+	ScopeIP    = "ip"
+)
+
+// LoginAttempt tracks consecutive failed logins for an email address or a client IP.
+type LoginAttempt struct {
+	Scope         string     `gorm:"size:10;primaryKey"`  // email or ip
+	Key           string     `gorm:"size:255;primaryKey"` // EmailKey of the tenant and email, or IP address
+	Failures      int        `gorm:"not null;default:0"`
+	LastFailureAt time.Time  `gorm:"not null;index"`
+	NextAttemptAt *time.Time // Earliest time the next attempt is accepted (exponential backoff)
+	LockedUntil   *time.Time // Set when the failure threshold was reached
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime"`
+}
+
+// EmailKey returns the key of the email scope. The same email can be
+// registered with several tenants, each counted on its own.
+func EmailKey(tenantID, email string) string {
+	return tenantID + ":" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// TableName specifies the table name for GORM
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+// IsLocked reports whether the record is locked at the given time.
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
+
+// RetryAfter returns how long the caller has to wait before the next attempt
+// is accepted, or zero when an attempt is allowed now.
+func (a *LoginAttempt) RetryAfter(now time.Time) time.Duration {
+	if a.IsLocked(now) {
+		return a.LockedUntil.Sub(now)
+	}
+	if a.NextAttemptAt != nil && now.Before(*a.NextAttemptAt) {
+		return a.NextAttemptAt.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,12 @@
+package loginattempt
+
+import "testing"
+
+func TestEmailKeyIsPerTenant(t *testing.T) {
+	if a, b := EmailKey("tenant-a", "Jane@Example.com "), EmailKey("tenant-b", "jane@example.com"); a == b {
+		t.Fatalf("keys of two tenants are equal: %q", a)
+	}
+	if got, want := EmailKey("tenant-a", " Jane@Example.com"), "tenant-a:jane@example.com"; got != want {
+		t.Fatalf("EmailKey = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+package loginattempt
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Repository abstracts data persistence for failed login tracking.
+type Repository interface {
+	Get(ctx context.Context, scope, key string) (*LoginAttempt, error)
+	RecordFailure(ctx context.Context, scope, key string, now, windowStart time.Time) (int, error)
+	SetBackoff(ctx context.Context, scope, key string, failures int, nextAttemptAt, lockedUntil *time.Time) error
+	Delete(ctx context.Context, scope, key string) error
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository builds a Gorm-backed login attempt repository.
+func NewRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+func (r *gormRepository) Get(ctx context.Context, scope, key string) (*LoginAttempt, error) {
+	var a LoginAttempt
+	err := r.db.WithContext(ctx).Where("scope = ? AND `key` = ?", scope, key).First(&a).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+// RecordFailure counts a failed login in a single upsert and returns the
+// resulting number of failures. The counter starts over when the last failure
+// is older than windowStart or an earlier lock has expired. MySQL applies the
+// assignments left to right, so failures is computed from the old values.
+func (r *gormRepository) RecordFailure(ctx context.Context, scope, key string, now, windowStart time.Time) (int, error) {
+	var failures int
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(
+			"INSERT INTO login_attempts (scope, `key`, failures, last_failure_at, updated_at) VALUES (?, ?, 1, ?, ?) "+
+				"ON DUPLICATE KEY UPDATE "+
+				"failures = IF(last_failure_at < ? OR (locked_until IS NOT NULL AND locked_until <= ?), 1, failures + 1), "+
+				"next_attempt_at = IF(failures = 1, NULL, next_attempt_at), "+
+				"locked_until = IF(failures = 1, NULL, locked_until), "+
+				"last_failure_at = VALUES(last_failure_at), "+
+				"updated_at = VALUES(updated_at)",
+			scope, key, now, now, windowStart, now,
+		).Error
+		if err != nil {
+			return err
+		}
+		// The row stays locked until commit, so this reads our own increment
+		return tx.Model(&LoginAttempt{}).
+			Where("scope = ? AND `key` = ?", scope, key).
+			Pluck("failures", &failures).Error
+	})
+	return failures, err
+}
+
+// SetBackoff stores the backoff and lock derived from failures. It only
+// applies while the counter is unchanged, so a concurrent newer failure is
+// not overwritten with the decision for an older count.
+func (r *gormRepository) SetBackoff(ctx context.Context, scope, key string, failures int, nextAttemptAt, lockedUntil *time.Time) error {
+	return r.db.WithContext(ctx).Model(&LoginAttempt{}).
+		Where("scope = ? AND `key` = ? AND failures = ?", scope, key, failures).
+		Updates(map[string]interface{}{
+			"next_attempt_at": nextAttemptAt,
+			"locked_until":    lockedUntil,
+		}).Error
+}
+
+func (r *gormRepository) Delete(ctx context.Context, scope, key string) error {
+	return r.db.WithContext(ctx).Delete(&LoginAttempt{}, "scope = ? AND `key` = ?", scope, key).Error
+}
@@ -0,0 +1,26 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// logMailer writes emails to the application log instead of sending them.
+// Intended for local development only, since bodies can contain secrets.
+type logMailer struct {
+	logger *logrus.Logger
+}
+
+// NewLogMailer builds a mailer that only logs messages.
+func NewLogMailer(logger *logrus.Logger) Mailer {
+	return &logMailer{logger: logger}
+}
+
+func (m *logMailer) Send(ctx context.Context, msg Message) error {
+	m.logger.WithFields(logrus.Fields{
+		"to":      msg.To,
+		"subject": msg.Subject,
+	}).Infof("email (log backend):\n%s", msg.Body)
+	return nil
+}
@@ -0,0 +1,43 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/config"
+)
+
+// Supported mail backends (MAIL_BACKEND).
+const (
+	BackendLog  = "log"
+	BackendSMTP = "smtp"
+)
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewFromConfig builds the mailer selected in config.
+func NewFromConfig(cfg *config.Config, logger *logrus.Logger) (Mailer, error) {
+	switch cfg.MailBackend {
+	case BackendLog, "":
+		return NewLogMailer(logger), nil
+	case BackendSMTP:
+		if cfg.SMTPHost == "" || cfg.MailFrom == "" {
+			return nil, fmt.Errorf("SMTP_HOST and MAIL_FROM must be provided for the smtp mail backend")
+		}
+		return NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom), nil
+	default:
+		return nil, fmt.Errorf("unknown mail backend %q", cfg.MailBackend)
+	}
+}
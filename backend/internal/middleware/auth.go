@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
@@ -8,10 +9,28 @@ import (
 
 	"werk-ticketing/internal/auth"
 	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/keyauth"
 	"werk-ticketing/internal/response"
+	"werk-ticketing/internal/user"
 )
 
-const userEmailKey = "userEmail"
+const (
+	userEmailKey = "userEmail"
+	userRoleKey  = "userRole"
+)
+
+const (
+	apiKeyIDKey     = "apiKeyID"
+	apiKeyScopesKey = "apiKeyScopes"
+)
+
+// apiKeyHeader carries a personal API key as an alternative to the Authorization header.
+const apiKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator resolves personal API keys.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*keyauth.Identity, error)
+}
 
 // WithAuth ensures the request has a valid JWT token.
 func WithAuth(authService auth.Service) gin.HandlerFunc {
@@ -28,7 +47,7 @@ func WithAuth(authService auth.Service) gin.HandlerFunc {
 			return
 		}
 
-		claims, err := authService.ParseToken(parts[1])
+		claims, err := authService.ParseToken(c.Request.Context(), parts[1])
 		if err != nil {
 			if appErr, ok := err.(*errors.AppError); ok {
 				response.AppError(c, appErr)
@@ -38,11 +57,86 @@ func WithAuth(authService auth.Service) gin.HandlerFunc {
 			return
 		}
 
+		if !useUserTenant(c, claims.TenantID) {
+			return
+		}
+
 		c.Set(userEmailKey, claims.Subject)
+		c.Set(userRoleKey, claims.Role)
+		auth.SetClaims(c, claims)
+		c.Next()
+	}
+}
+
+// WithAuthOrAPIKey accepts either a valid JWT or a personal API key, sent in the
+// X-API-Key header or as a bearer token. Routes using it should declare the
+// scope they need with RequireScope.
+func WithAuthOrAPIKey(authService auth.Service, apiKeys APIKeyAuthenticator) gin.HandlerFunc {
+	withJWT := WithAuth(authService)
+	return func(c *gin.Context) {
+		key := c.GetHeader(apiKeyHeader)
+		if key == "" {
+			parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+			if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") && strings.HasPrefix(parts[1], keyauth.Prefix) {
+				key = parts[1]
+			}
+		}
+		if key == "" {
+			withJWT(c)
+			return
+		}
+
+		identity, err := apiKeys.AuthenticateAPIKey(c.Request.Context(), key)
+		if err != nil {
+			if appErr, ok := err.(*errors.AppError); ok {
+				response.AppError(c, appErr)
+			} else {
+				response.ErrorWithCode(c, http.StatusUnauthorized, errors.ErrCodeUnauthorized, "invalid api key")
+			}
+			return
+		}
+
+		if !useUserTenant(c, identity.TenantID) {
+			return
+		}
+
+		c.Set(userEmailKey, identity.Email)
+		c.Set(userRoleKey, identity.Role)
+		c.Set(apiKeyIDKey, identity.KeyID)
+		c.Set(apiKeyScopesKey, identity.Scopes)
 		c.Next()
 	}
 }
 
+// IsAPIKeyRequest reports whether the request was authenticated with an API key.
+func IsAPIKeyRequest(c *gin.Context) bool {
+	_, ok := c.Get(apiKeyIDKey)
+	return ok
+}
+
+// RequireScope only lets API key requests through when the key carries the given scope.
+// Requests authenticated with a JWT are not limited by scopes.
+// It must be registered after WithAuthOrAPIKey.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsAPIKeyRequest(c) {
+			c.Next()
+			return
+		}
+
+		scopes, _ := c.Get(apiKeyScopesKey)
+		granted, _ := scopes.([]string)
+		for _, s := range granted {
+			if s == scope {
+				c.Next()
+				return
+			}
+		}
+
+		response.ErrorWithCode(c, http.StatusForbidden, errors.ErrCodeForbidden, "api key is missing scope "+scope)
+	}
+}
+
 // GetUserEmail extracts the authenticated user email from the request context.
 func GetUserEmail(c *gin.Context) string {
 	if email, ok := c.Get(userEmailKey); ok {
@@ -52,3 +146,37 @@ func GetUserEmail(c *gin.Context) string {
 	}
 	return ""
 }
+
+// GetUserRole extracts the authenticated user role from the request context.
+// Tokens issued before roles existed carry no role and are treated as requesters.
+func GetUserRole(c *gin.Context) string {
+	if role, ok := c.Get(userRoleKey); ok {
+		if s, ok := role.(string); ok && s != "" {
+			return s
+		}
+	}
+	return user.RoleRequester
+}
+
+// HasRole reports whether the authenticated user has one of the given roles.
+func HasRole(c *gin.Context, roles ...string) bool {
+	current := GetUserRole(c)
+	for _, role := range roles {
+		if current == role {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireRole only lets the request through when the authenticated user has one of the given roles.
+// It must be registered after WithAuth.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasRole(c, roles...) {
+			response.ErrorWithCode(c, http.StatusForbidden, errors.ErrCodeForbidden, "insufficient permissions")
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"werk-ticketing/internal/ticketmeta"
+)
+
+// Language selects the language of localized names in the response from the
+// lang query parameter or the Accept-Language header.
+func Language() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := ticketmeta.ParseLanguage(c.Query("lang"), c.GetHeader("Accept-Language"))
+		c.Request = c.Request.WithContext(ticketmeta.WithLanguage(c.Request.Context(), lang))
+		c.Next()
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"werk-ticketing/internal/database"
+	"werk-ticketing/internal/requestinfo"
+)
+
+// RequestIDHeader carries the ID of a request, echoed in the response.
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 64
+
+// RequestInfo assigns every request an ID and stores it, together with the
+// client IP, in the request context. A well-formed X-Request-ID sent by the
+// client or a proxy is reused so log lines can be correlated across services.
+func RequestInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = database.NewUUID()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		c.Request = c.Request.WithContext(requestinfo.WithInfo(c.Request.Context(), requestinfo.Info{
+			RequestID: requestID,
+			IPAddress: c.ClientIP(),
+		}))
+		c.Next()
+	}
+}
+
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
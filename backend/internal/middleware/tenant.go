@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/response"
+	"werk-ticketing/internal/tenant"
+)
+
+// ResolveTenant binds the request to the tenant serving the request host, or
+// to the default tenant for unknown hosts. WithAuth and WithAuthOrAPIKey
+// switch to the tenant of the authenticated user afterwards.
+func ResolveTenant(tenants tenant.Service, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t, err := tenants.ForHost(c.Request.Context(), c.Request.Host)
+		if err != nil {
+			logger.WithError(err).WithField("host", c.Request.Host).Error("failed to resolve tenant")
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, "failed to resolve tenant")
+			return
+		}
+
+		ctx := tenant.WithResolver(tenant.NewContext(c.Request.Context(), t), tenants)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// useUserTenant switches the request to the tenant of the authenticated user.
+func useUserTenant(c *gin.Context, tenantID string) bool {
+	ctx, err := tenant.Switch(c.Request.Context(), tenantID)
+	if err != nil {
+		response.ErrorWithCode(c, http.StatusUnauthorized, errors.ErrCodeUnauthorized, "unknown tenant")
+		return false
+	}
+	c.Request = c.Request.WithContext(ctx)
+	return true
+}
@@ -0,0 +1,192 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// minKeyRefreshInterval limits how often an unknown kid triggers a JWKS reload.
+const minKeyRefreshInterval = time.Minute
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type keySet struct {
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+// publicKey returns the signing key for kid, reloading the JWKS once if the
+// key is unknown (the provider may have rotated its keys).
+func (p *Provider) publicKey(ctx context.Context, kid, alg string) (interface{}, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.keys != nil {
+		if key, ok := lookupKey(p.keys, kid); ok {
+			return key, checkKeyType(key, alg)
+		}
+		if time.Since(p.keys.fetchedAt) < minKeyRefreshInterval {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+	}
+
+	keys, err := p.fetchKeys(ctx, doc.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+
+	key, ok := lookupKey(keys, kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, checkKeyType(key, alg)
+}
+
+func (p *Provider) fetchKeys(ctx context.Context, jwksURI string) (*keySet, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.doJSON(req, &doc); err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+
+	set := &keySet{keys: make(map[string]interface{}), fetchedAt: time.Now()}
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Skip keys we cannot use instead of failing the whole set
+			continue
+		}
+		set.keys[jwk.Kid] = key
+	}
+	if len(set.keys) == 0 {
+		return nil, fmt.Errorf("fetch jwks: no usable signing keys")
+	}
+	return set, nil
+}
+
+// lookupKey finds a key by kid. Tokens without kid are accepted only when the set has a single key.
+func lookupKey(set *keySet, kid string) (interface{}, bool) {
+	if kid == "" {
+		if len(set.keys) == 1 {
+			for _, key := range set.keys {
+				return key, true
+			}
+		}
+		return nil, false
+	}
+	key, ok := set.keys[kid]
+	return key, ok
+}
+
+// checkKeyType makes sure the token algorithm matches the key type, so an
+// RSA key can never be used to verify, say, an ECDSA signature.
+func checkKeyType(key interface{}, alg string) error {
+	var ok bool
+	switch {
+	case strings.HasPrefix(alg, "RS"), strings.HasPrefix(alg, "PS"):
+		_, ok = key.(*rsa.PublicKey)
+	case strings.HasPrefix(alg, "ES"):
+		_, ok = key.(*ecdsa.PublicKey)
+	case alg == "EdDSA":
+		_, ok = key.(ed25519.PublicKey)
+	}
+	if !ok {
+		return fmt.Errorf("signing key does not match algorithm %s", alg)
+	}
+	return nil
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
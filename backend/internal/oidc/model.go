@@ -0,0 +1,18 @@
+package oidc
+
+import "time"
+
+// LoginState keeps the data of a started authorization request until the
+// provider redirects back. Each state can be used once.
+type LoginState struct {
+	State        string    `gorm:"size:64;primaryKey"`
+	Nonce        string    `gorm:"size:64;not null"`
+	CodeVerifier string    `gorm:"size:128;not null"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (LoginState) TableName() string {
+	return "oidc_login_states"
+}
@@ -0,0 +1,255 @@
+// Package oidctest runs a minimal OpenID Connect provider on an httptest
+// server. It serves discovery, JWKS, the authorization endpoint and the
+// authorization code grant with PKCE (S256), for tests of SSO logins.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const keyID = "oidctest"
+
+// Identity is the account the provider signs in on the next authorization.
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified *bool
+	Name          string
+}
+
+// authRequest is an issued authorization code waiting to be exchanged.
+type authRequest struct {
+	identity      Identity
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+}
+
+// Provider is a fake identity provider. Issuer is the server URL.
+type Provider struct {
+	Server   *httptest.Server
+	Issuer   string
+	ClientID string
+
+	key *rsa.PrivateKey
+
+	mu       sync.Mutex
+	identity Identity
+	nonce    *string
+	codes    map[string]authRequest
+}
+
+// NewProvider starts a provider for clientID. Call Close when done.
+func NewProvider(clientID string) (*Provider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Provider{
+		ClientID: clientID,
+		key:      key,
+		codes:    make(map[string]authRequest),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/jwks", p.jwks)
+	mux.HandleFunc("/authorize", p.authorize)
+	mux.HandleFunc("/token", p.token)
+
+	p.Server = httptest.NewServer(mux)
+	p.Issuer = p.Server.URL
+	return p, nil
+}
+
+// Close shuts the server down.
+func (p *Provider) Close() {
+	p.Server.Close()
+}
+
+// SetIdentity sets the account signed in by the next authorizations.
+func (p *Provider) SetIdentity(identity Identity) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.identity = identity
+}
+
+// OverrideNonce makes the provider put nonce into ID tokens instead of the
+// one sent with the authorization request.
+func (p *Provider) OverrideNonce(nonce string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.nonce = &nonce
+}
+
+// Authorize follows an authorization URL like a browser would and returns
+// the code and state the provider redirects back with.
+func (p *Provider) Authorize(authURL string) (string, string, error) {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		return "", "", fmt.Errorf("authorize returned status %d", resp.StatusCode)
+	}
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return "", "", err
+	}
+	query := location.Query()
+	return query.Get("code"), query.Get("state"), nil
+}
+
+func (p *Provider) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.Issuer,
+		"authorization_endpoint":                p.Issuer + "/authorize",
+		"token_endpoint":                        p.Issuer + "/token",
+		"jwks_uri":                              p.Issuer + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (p *Provider) jwks(w http.ResponseWriter, _ *http.Request) {
+	pub := p.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func (p *Provider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != p.ClientID {
+		writeError(w, "unauthorized_client")
+		return
+	}
+	if query.Get("code_challenge") == "" || query.Get("code_challenge_method") != "S256" {
+		writeError(w, "invalid_request")
+		return
+	}
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectURI.String() == "" {
+		writeError(w, "invalid_request")
+		return
+	}
+
+	code := randomString()
+	p.mu.Lock()
+	p.codes[code] = authRequest{
+		identity:      p.identity,
+		redirectURI:   redirectURI.String(),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+	}
+	p.mu.Unlock()
+
+	params := redirectURI.Query()
+	params.Set("code", code)
+	params.Set("state", query.Get("state"))
+	redirectURI.RawQuery = params.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeError(w, "unsupported_grant_type")
+		return
+	}
+
+	p.mu.Lock()
+	code := r.PostForm.Get("code")
+	req, ok := p.codes[code]
+	delete(p.codes, code)
+	nonce := req.nonce
+	if p.nonce != nil {
+		nonce = *p.nonce
+	}
+	p.mu.Unlock()
+
+	if !ok || r.PostForm.Get("client_id") != p.ClientID || r.PostForm.Get("redirect_uri") != req.redirectURI {
+		writeError(w, "invalid_grant")
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != req.codeChallenge {
+		writeError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   p.Issuer,
+		"aud":   p.ClientID,
+		"sub":   req.identity.Subject,
+		"email": req.identity.Email,
+		"name":  req.identity.Name,
+		"nonce": nonce,
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+	}
+	if req.identity.EmailVerified != nil {
+		claims["email_verified"] = *req.identity.EmailVerified
+	}
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = keyID
+	signed, err := idToken.SignedString(p.key)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"id_token":     signed,
+		"expires_in":   300,
+	})
+}
+
+func writeError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,214 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Config describes the OIDC client registration.
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Claims are the ID token claims mapped to local users.
+type Claims struct {
+	Email         string `json:"email"`
+	EmailVerified *bool  `json:"email_verified,omitempty"`
+	Name          string `json:"name"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+	Nonce         string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// TokenResponse is the token endpoint response of the authorization code grant.
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// discoveryDocument holds the parts of /.well-known/openid-configuration we use.
+type discoveryDocument struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	SigningAlgs           []string `json:"id_token_signing_alg_values_supported"`
+}
+
+// supportedAlgs are the ID token signature algorithms accepted from the provider.
+var supportedAlgs = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// Provider talks to an OpenID Connect provider. Discovery and keys are loaded
+// lazily and cached; the key set is refreshed when an unknown key ID shows up.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *discoveryDocument
+	keys      *keySet
+}
+
+// NewProvider builds a provider client. The http client may be nil.
+func NewProvider(cfg Config, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	return &Provider{cfg: cfg, client: client}
+}
+
+// AuthCodeURL returns the authorization endpoint URL for the authorization
+// code flow with PKCE (S256).
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.cfg.ClientID)
+	params.Set("redirect_uri", p.cfg.RedirectURL)
+	params.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(doc.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return doc.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// Exchange trades an authorization code for tokens.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (*TokenResponse, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	var tokens TokenResponse
+	if err := p.doJSON(req, &tokens); err != nil {
+		return nil, fmt.Errorf("token exchange: %w", err)
+	}
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("token exchange: no id_token in response")
+	}
+	return &tokens, nil
+}
+
+// VerifyIDToken checks the signature, issuer, audience, expiry and nonce of an ID token.
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*Claims, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &Claims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.publicKey(ctx, kid, t.Method.Alg())
+	},
+		jwt.WithValidMethods(supportedAlgs),
+		jwt.WithIssuer(doc.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("invalid id token: nonce mismatch")
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("invalid id token: missing subject")
+	}
+	return claims, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	wellKnown := strings.TrimSuffix(p.cfg.IssuerURL, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc discoveryDocument
+	if err := p.doJSON(req, &doc); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != strings.TrimSuffix(p.cfg.IssuerURL, "/") {
+		return nil, fmt.Errorf("oidc discovery: issuer %q does not match configured issuer %q", doc.Issuer, p.cfg.IssuerURL)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc discovery: incomplete provider metadata")
+	}
+
+	p.discovery = &doc
+	return p.discovery, nil
+}
+
+func (p *Provider) doJSON(req *http.Request, out interface{}) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s returned status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,146 @@
+package oidc_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"werk-ticketing/internal/oidc"
+	"werk-ticketing/internal/oidc/oidctest"
+)
+
+const redirectURL = "https://app.example.com/api/v1/auth/oidc/callback"
+
+func newProvider(t *testing.T) (*oidctest.Provider, *oidc.Provider) {
+	t.Helper()
+	fake, err := oidctest.NewProvider("werk")
+	if err != nil {
+		t.Fatalf("start fake provider: %v", err)
+	}
+	t.Cleanup(fake.Close)
+
+	verified := true
+	fake.SetIdentity(oidctest.Identity{Subject: "sub-1", Email: "jane@example.com", EmailVerified: &verified, Name: "Jane Doe"})
+
+	return fake, oidc.NewProvider(oidc.Config{
+		IssuerURL:   fake.Issuer,
+		ClientID:    "werk",
+		RedirectURL: redirectURL,
+	}, nil)
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	_, provider := newProvider(t)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", oidc.CodeChallengeS256("verifier"))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse url: %v", err)
+	}
+
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             "werk",
+		"redirect_uri":          redirectURL,
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"code_challenge":        oidc.CodeChallengeS256("verifier"),
+		"code_challenge_method": "S256",
+	}
+	for key, value := range want {
+		if got := u.Query().Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestCodeChallengeS256(t *testing.T) {
+	// RFC 7636 appendix B
+	got := oidc.CodeChallengeS256("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if want := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"; got != want {
+		t.Fatalf("challenge = %q, want %q", got, want)
+	}
+}
+
+func TestExchangeAndVerify(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		exchangeWith string // verifier sent to the token endpoint
+		tokenNonce   string // nonce the provider puts into the ID token, empty to echo it
+		wantExchange bool
+		wantVerify   bool
+	}{
+		{name: "valid", exchangeWith: "verifier", wantExchange: true, wantVerify: true},
+		{name: "wrong PKCE verifier", exchangeWith: "other-verifier"},
+		{name: "nonce mismatch", exchangeWith: "verifier", tokenNonce: "replayed", wantExchange: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, provider := newProvider(t)
+			if tt.tokenNonce != "" {
+				fake.OverrideNonce(tt.tokenNonce)
+			}
+
+			authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", oidc.CodeChallengeS256("verifier"))
+			if err != nil {
+				t.Fatalf("AuthCodeURL: %v", err)
+			}
+			code, state, err := fake.Authorize(authURL)
+			if err != nil {
+				t.Fatalf("authorize: %v", err)
+			}
+			if state != "state-1" {
+				t.Fatalf("state = %q, want state-1", state)
+			}
+
+			tokens, err := provider.Exchange(ctx, code, tt.exchangeWith)
+			if (err == nil) != tt.wantExchange {
+				t.Fatalf("Exchange error = %v, want success %v", err, tt.wantExchange)
+			}
+			if err != nil {
+				return
+			}
+
+			claims, err := provider.VerifyIDToken(ctx, tokens.IDToken, "nonce-1")
+			if (err == nil) != tt.wantVerify {
+				t.Fatalf("VerifyIDToken error = %v, want success %v", err, tt.wantVerify)
+			}
+			if err != nil {
+				return
+			}
+			if claims.Subject != "sub-1" || claims.Email != "jane@example.com" {
+				t.Fatalf("claims = %+v", claims)
+			}
+			if claims.EmailVerified == nil || !*claims.EmailVerified {
+				t.Fatalf("email_verified not set")
+			}
+		})
+	}
+}
+
+func TestExchangeCodeIsSingleUse(t *testing.T) {
+	ctx := context.Background()
+	fake, provider := newProvider(t)
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", oidc.CodeChallengeS256("verifier"))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	code, _, err := fake.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+
+	if _, err := provider.Exchange(ctx, code, "verifier"); err != nil {
+		t.Fatalf("first Exchange: %v", err)
+	}
+	if _, err := provider.Exchange(ctx, code, "verifier"); err == nil {
+		t.Fatal("second Exchange of the same code succeeded")
+	}
+}
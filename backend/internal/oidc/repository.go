@@ -0,0 +1,56 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StateRepository abstracts data persistence for pending OIDC logins.
+type StateRepository interface {
+	Create(ctx context.Context, state *LoginState) error
+	Consume(ctx context.Context, state string) (*LoginState, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type gormStateRepository struct {
+	db *gorm.DB
+}
+
+// NewStateRepository builds a Gorm-backed OIDC state repository.
+func NewStateRepository(db *gorm.DB) StateRepository {
+	return &gormStateRepository{db: db}
+}
+
+func (r *gormStateRepository) Create(ctx context.Context, state *LoginState) error {
+	return r.db.WithContext(ctx).Create(state).Error
+}
+
+// Consume loads and deletes a state. It returns nil when the state is unknown
+// or was already consumed by a concurrent request.
+func (r *gormStateRepository) Consume(ctx context.Context, state string) (*LoginState, error) {
+	var s LoginState
+	err := r.db.WithContext(ctx).Where("state = ?", state).First(&s).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	result := r.db.WithContext(ctx).Delete(&LoginState{}, "state = ?", state)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+func (r *gormStateRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Delete(&LoginState{}, "expires_at <= ?", now)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,37 @@
+package privacy
+
+import "time"
+
+// ExportResponse describes the state of a data export.
+type ExportResponse struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// DeleteAccountRequest confirms the deletion of the own account with the password.
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required" validate:"required"`
+
+	RequestIP string `json:"-"`
+}
+
+// DeletionResponse summarizes a completed account deletion.
+type DeletionResponse struct {
+	DeletionID    string `json:"deletion_id"`
+	InvGateAction string `json:"invgate_action"`
+}
+
+func toExportResponse(e *DataExport) *ExportResponse {
+	return &ExportResponse{
+		ID:          e.ID,
+		Status:      e.Status,
+		Error:       e.Error,
+		CreatedAt:   e.CreatedAt,
+		CompletedAt: e.CompletedAt,
+		ExpiresAt:   e.ExpiresAt,
+	}
+}
@@ -0,0 +1,99 @@
+package privacy
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/middleware"
+	"werk-ticketing/internal/response"
+)
+
+// Handler exposes HTTP handlers for data export and account deletion routes.
+type Handler struct {
+	service Service
+}
+
+// NewHandler wires privacy service into http handler.
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// RequestExport handles POST /api/v1/me/exports
+func (h *Handler) RequestExport(c *gin.Context) {
+	resp, err := h.service.RequestExport(c.Request.Context(), middleware.GetUserEmail(c))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	response.Write(c, http.StatusAccepted, resp)
+}
+
+// GetExport handles GET /api/v1/me/exports/:id
+func (h *Handler) GetExport(c *gin.Context) {
+	resp, err := h.service.GetExport(c.Request.Context(), middleware.GetUserEmail(c), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
+
+// DownloadExport handles GET /api/v1/me/exports/:id/download
+func (h *Handler) DownloadExport(c *gin.Context) {
+	path, err := h.service.OpenExport(c.Request.Context(), middleware.GetUserEmail(c), c.Param("id"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.FileAttachment(path, "personal-data-"+time.Now().UTC().Format("2006-01-02")+".zip")
+}
+
+// DeleteAccount handles DELETE /api/v1/me
+func (h *Handler) DeleteAccount(c *gin.Context) {
+	var req DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+		return
+	}
+
+	req.RequestIP = c.ClientIP()
+	resp, err := h.service.DeleteAccount(c.Request.Context(), middleware.GetUserEmail(c), req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
+
+// DeleteUserAccount handles DELETE /api/v1/admin/users/:id
+func (h *Handler) DeleteUserAccount(c *gin.Context) {
+	userID := c.Param("id")
+	if userID == "" {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "user id is required")
+		return
+	}
+
+	resp, err := h.service.DeleteUserAccount(c.Request.Context(), userID, middleware.GetUserEmail(c), c.ClientIP())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
+
+func writeError(c *gin.Context, err error) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		response.AppError(c, appErr)
+	} else {
+		response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+	}
+}
@@ -0,0 +1,93 @@
+package privacy
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"werk-ticketing/internal/database"
+)
+
+// Data export states.
+const (
+	ExportStatusPending   = "pending"
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+)
+
+// DataExport is a requested personal data export. The ZIP file is built by a
+// background job and can be downloaded until ExpiresAt.
+type DataExport struct {
+	ID          string     `gorm:"type:char(36);primaryKey"`
+	UserID      string     `gorm:"type:char(36);not null;index"`
+	Status      string     `gorm:"size:20;not null;index"`
+	FilePath    string     `gorm:"size:500"` // Location of the ZIP file once completed
+	Error       string     `gorm:"size:500"` // Reason of a failed export
+	ExpiresAt   *time.Time `gorm:"index"`    // Set on completion, the file is removed afterwards
+	CompletedAt *time.Time
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (DataExport) TableName() string {
+	return "data_exports"
+}
+
+// BeforeCreate assigns the UUID in Go so the ID is known to the caller after insert.
+func (e *DataExport) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = database.NewUUID()
+	}
+	return nil
+}
+
+// IsActive reports whether the export is still queued or being built.
+func (e *DataExport) IsActive() bool {
+	return e.Status == ExportStatusPending || e.Status == ExportStatusRunning
+}
+
+// Account deletion states.
+const (
+	DeletionStatusStarted   = "started"
+	DeletionStatusCompleted = "completed"
+	DeletionStatusFailed    = "failed"
+)
+
+// What happened to the InvGate user of a deleted account.
+const (
+	InvGateActionNone        = "none"        // Account was not linked to InvGate
+	InvGateActionDeleted     = "deleted"     // InvGate user was deleted
+	InvGateActionDeactivated = "deactivated" // Deletion failed, the InvGate user was disabled instead
+)
+
+// AccountDeletion is the audit record of a deleted account. It holds no
+// personal data besides a hash of the former email, which lets support
+// confirm a deletion when the user asks about it.
+type AccountDeletion struct {
+	ID            string `gorm:"type:char(36);primaryKey"`
+	UserID        string `gorm:"type:char(36);not null;index"`
+	EmailHash     string `gorm:"size:64;not null;index"` // hex encoded SHA-256 of the lower-cased email
+	InvGateUserID int    `gorm:"not null;default:0;column:invgate_user_id"`
+	InvGateAction string `gorm:"size:20;not null;column:invgate_action"`
+	RequestedBy   string `gorm:"size:190;not null"` // "self" or the email of the admin
+	RequestIP     string `gorm:"size:64"`
+	Status        string `gorm:"size:20;not null"`
+	Error         string `gorm:"size:500"`
+	CompletedAt   *time.Time
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (AccountDeletion) TableName() string {
+	return "account_deletions"
+}
+
+// BeforeCreate assigns the UUID in Go so the ID is known to the caller after insert.
+func (d *AccountDeletion) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = database.NewUUID()
+	}
+	return nil
+}
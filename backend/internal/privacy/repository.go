@@ -0,0 +1,126 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Repository abstracts data persistence for data exports and deletion audits.
+type Repository interface {
+	CreateExport(ctx context.Context, export *DataExport) error
+	GetExport(ctx context.Context, id string) (*DataExport, error)
+	GetActiveExport(ctx context.Context, userID string) (*DataExport, error)
+	ListExportsByStatus(ctx context.Context, statuses ...string) ([]DataExport, error)
+	ListExportsByUser(ctx context.Context, userID string) ([]DataExport, error)
+	ListExpiredExports(ctx context.Context, now time.Time) ([]DataExport, error)
+	ClaimExport(ctx context.Context, id string, staleBefore time.Time) (bool, error)
+	UpdateExport(ctx context.Context, export *DataExport) error
+	DeleteExport(ctx context.Context, id string) error
+	CreateDeletion(ctx context.Context, deletion *AccountDeletion) error
+	UpdateDeletion(ctx context.Context, deletion *AccountDeletion) error
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository builds a Gorm-backed privacy repository.
+func NewRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+func (r *gormRepository) CreateExport(ctx context.Context, export *DataExport) error {
+	return r.db.WithContext(ctx).Create(export).Error
+}
+
+func (r *gormRepository) GetExport(ctx context.Context, id string) (*DataExport, error) {
+	var e DataExport
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&e).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &e, nil
+}
+
+// GetActiveExport returns the pending or running export of a user, if any.
+func (r *gormRepository) GetActiveExport(ctx context.Context, userID string) (*DataExport, error) {
+	var e DataExport
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status IN ?", userID, []string{ExportStatusPending, ExportStatusRunning}).
+		First(&e).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *gormRepository) ListExportsByStatus(ctx context.Context, statuses ...string) ([]DataExport, error) {
+	var exports []DataExport
+	err := r.db.WithContext(ctx).Where("status IN ?", statuses).Order("created_at").Find(&exports).Error
+	return exports, err
+}
+
+func (r *gormRepository) ListExportsByUser(ctx context.Context, userID string) ([]DataExport, error) {
+	var exports []DataExport
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&exports).Error
+	return exports, err
+}
+
+// ListExpiredExports returns completed exports whose download window has passed.
+func (r *gormRepository) ListExpiredExports(ctx context.Context, now time.Time) ([]DataExport, error) {
+	var exports []DataExport
+	err := r.db.WithContext(ctx).Where("expires_at IS NOT NULL AND expires_at < ?", now).Find(&exports).Error
+	return exports, err
+}
+
+// ClaimExport marks an export as running. It only succeeds for pending exports
+// and for running exports not updated since staleBefore (their worker died),
+// so an export is never built by two workers at once.
+func (r *gormRepository) ClaimExport(ctx context.Context, id string, staleBefore time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&DataExport{}).
+		Where("id = ? AND (status = ? OR (status = ? AND updated_at < ?))", id, ExportStatusPending, ExportStatusRunning, staleBefore).
+		Updates(map[string]interface{}{
+			"status":     ExportStatusRunning,
+			"updated_at": time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *gormRepository) UpdateExport(ctx context.Context, export *DataExport) error {
+	return r.db.WithContext(ctx).Model(export).Updates(map[string]interface{}{
+		"status":       export.Status,
+		"file_path":    export.FilePath,
+		"error":        export.Error,
+		"expires_at":   export.ExpiresAt,
+		"completed_at": export.CompletedAt,
+	}).Error
+}
+
+func (r *gormRepository) DeleteExport(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&DataExport{}, "id = ?", id).Error
+}
+
+func (r *gormRepository) CreateDeletion(ctx context.Context, deletion *AccountDeletion) error {
+	return r.db.WithContext(ctx).Create(deletion).Error
+}
+
+func (r *gormRepository) UpdateDeletion(ctx context.Context, deletion *AccountDeletion) error {
+	return r.db.WithContext(ctx).Model(deletion).Updates(map[string]interface{}{
+		"invgate_action": deletion.InvGateAction,
+		"status":         deletion.Status,
+		"error":          deletion.Error,
+		"completed_at":   deletion.CompletedAt,
+	}).Error
+}
@@ -0,0 +1,139 @@
+package privacy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/apikey"
+	"werk-ticketing/internal/auth"
+	"werk-ticketing/internal/constants"
+	"werk-ticketing/internal/invgate"
+	"werk-ticketing/internal/loginattempt"
+	"werk-ticketing/internal/session"
+	"werk-ticketing/internal/tenant"
+	"werk-ticketing/internal/ticket"
+	"werk-ticketing/internal/user"
+	"werk-ticketing/internal/usertoken"
+)
+
+// exportQueueSize bounds the number of exports waiting in memory. Exports that
+// do not fit stay pending in the database and are picked up by the sweeper.
+const exportQueueSize = 100
+
+// Service exposes personal data export and account deletion use cases.
+type Service interface {
+	RequestExport(ctx context.Context, email string) (*ExportResponse, error)
+	GetExport(ctx context.Context, email, exportID string) (*ExportResponse, error)
+	OpenExport(ctx context.Context, email, exportID string) (string, error)
+	DeleteAccount(ctx context.Context, email string, req DeleteAccountRequest) (*DeletionResponse, error)
+	DeleteUserAccount(ctx context.Context, userID, actorEmail, requestIP string) (*DeletionResponse, error)
+}
+
+type service struct {
+	repository       Repository
+	userRepo         user.Repository
+	sessionRepo      session.Repository
+	apiKeyRepo       apikey.Repository
+	tokenRepo        usertoken.Repository
+	loginAttemptRepo loginattempt.Repository
+	ticketService    ticket.Service
+	authService      auth.Service
+	invgateClient    invgate.Service
+	tenants          tenant.Service
+	exportDir        string
+	jobs             chan string
+	queuedMu         sync.Mutex
+	queued           map[string]bool // Export IDs waiting in jobs
+	logger           *logrus.Logger
+}
+
+// NewService instantiates the privacy service and starts the background
+// export worker. Exports left pending or running by a previous process are resumed.
+func NewService(repository Repository, userRepo user.Repository, sessionRepo session.Repository, apiKeyRepo apikey.Repository, tokenRepo usertoken.Repository, loginAttemptRepo loginattempt.Repository, ticketService ticket.Service, authService auth.Service, invgateClient invgate.Service, tenants tenant.Service, exportDir string, logger *logrus.Logger) Service {
+	s := &service{
+		repository:       repository,
+		userRepo:         userRepo,
+		sessionRepo:      sessionRepo,
+		apiKeyRepo:       apiKeyRepo,
+		tokenRepo:        tokenRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		ticketService:    ticketService,
+		authService:      authService,
+		invgateClient:    invgateClient,
+		tenants:          tenants,
+		exportDir:        exportDir,
+		jobs:             make(chan string, exportQueueSize),
+		queued:           make(map[string]bool),
+		logger:           logger,
+	}
+
+	go s.runExportWorker()
+	go s.runSweeper(constants.DataExportSweepInterval)
+
+	return s
+}
+
+// runSweeper periodically removes expired export files and requeues pending
+// exports that did not fit into the queue or were interrupted by a restart.
+func (s *service) runSweeper(interval time.Duration) {
+	s.sweep()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.sweep()
+	}
+}
+
+func (s *service) sweep() {
+	ctx := context.Background()
+
+	expired, err := s.repository.ListExpiredExports(ctx, time.Now().UTC())
+	if err != nil {
+		s.logger.WithError(err).Error("failed to list expired data exports")
+	}
+	for i := range expired {
+		s.removeExport(ctx, &expired[i])
+	}
+
+	pending, err := s.repository.ListExportsByStatus(ctx, ExportStatusPending, ExportStatusRunning)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to list pending data exports")
+		return
+	}
+	for _, export := range pending {
+		if export.Status == ExportStatusRunning && time.Since(export.UpdatedAt) < constants.DataExportTimeout {
+			// Probably still being built by the worker
+			continue
+		}
+		s.enqueueExport(export.ID)
+	}
+}
+
+// enqueueExport hands an export to the worker without blocking the caller.
+// An export already waiting in the queue is not added again, so the sweeper
+// does not fill the queue with copies of exports the worker has not reached.
+func (s *service) enqueueExport(id string) {
+	s.queuedMu.Lock()
+	defer s.queuedMu.Unlock()
+
+	if s.queued[id] {
+		return
+	}
+	select {
+	case s.jobs <- id:
+		s.queued[id] = true
+	default:
+		s.logger.WithField("exportID", id).Warn("data export queue is full, export stays pending")
+	}
+}
+
+// dequeueExport marks an export taken off the queue by the worker.
+func (s *service) dequeueExport(id string) {
+	s.queuedMu.Lock()
+	defer s.queuedMu.Unlock()
+	delete(s.queued, id)
+}
@@ -0,0 +1,252 @@
+package privacy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/loginattempt"
+	"werk-ticketing/internal/session"
+	"werk-ticketing/internal/tenant"
+	"werk-ticketing/internal/user"
+	"werk-ticketing/internal/usertoken"
+)
+
+// requestedBySelf marks deletions requested by the account owner in the audit log.
+const requestedBySelf = "self"
+
+// DeleteAccount deletes the account of the logged-in user after checking the password.
+func (s *service) DeleteAccount(ctx context.Context, email string, req DeleteAccountRequest) (*DeletionResponse, error) {
+	u, err := s.getUser(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(req.Password)); err != nil {
+		s.logger.WithField("userID", u.ID).Warn("account deletion with invalid password")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidCredentials,
+			"password is incorrect",
+			nil,
+		)
+	}
+
+	return s.deleteAccount(ctx, u, requestedBySelf, req.RequestIP)
+}
+
+// DeleteUserAccount deletes the account of another user on behalf of an admin.
+func (s *service) DeleteUserAccount(ctx context.Context, userID, actorEmail, requestIP string) (*DeletionResponse, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		s.logger.WithError(err).WithField("userID", userID).Error("failed to get user by id")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to retrieve user information",
+			err,
+		)
+	}
+	// Admins only manage the users of their own tenant
+	if u == nil || u.AnonymizedAt != nil || u.TenantID != tenant.IDFromContext(ctx) {
+		return nil, errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"user not found",
+			nil,
+		)
+	}
+
+	if strings.EqualFold(u.Email, actorEmail) {
+		return nil, errors.NewAppError(
+			errors.ErrCodeForbidden,
+			"use DELETE /api/v1/me to delete your own account",
+			nil,
+		)
+	}
+
+	return s.deleteAccount(ctx, u, actorEmail, requestIP)
+}
+
+// deleteAccount removes the InvGate user (or disables it when deletion is
+// refused), revokes every token of the user and anonymizes the local rows.
+// Each deletion is recorded in account_deletions before anything is changed,
+// and the record is completed with the outcome.
+func (s *service) deleteAccount(ctx context.Context, u *user.User, requestedBy, requestIP string) (*DeletionResponse, error) {
+	audit := &AccountDeletion{
+		UserID:        u.ID,
+		EmailHash:     hashEmail(u.Email),
+		InvGateUserID: u.InvGateUserID,
+		InvGateAction: InvGateActionNone,
+		RequestedBy:   requestedBy,
+		RequestIP:     requestIP,
+		Status:        DeletionStatusStarted,
+	}
+	if err := s.repository.CreateDeletion(ctx, audit); err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to record account deletion")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to delete account",
+			err,
+		)
+	}
+
+	if u.InvGateUserID > 0 {
+		action, err := s.removeInvGateUser(ctx, u.InvGateUserID)
+		if err != nil {
+			s.finishDeletion(audit, err)
+			return nil, errors.NewAppError(
+				errors.ErrCodeExternalService,
+				"failed to delete or deactivate user in InvGate",
+				err,
+			)
+		}
+		audit.InvGateAction = action
+
+		if action == InvGateActionDeleted {
+			// A retry after a later failure must not try to delete the InvGate user again
+			if err := s.userRepo.UpdateInvGateUserID(ctx, u.ID, 0, requestedBy); err != nil {
+				s.logger.WithError(err).WithField("userID", u.ID).Warn("failed to unlink deleted InvGate user")
+			}
+		}
+	}
+
+	if err := s.eraseLocalData(ctx, u); err != nil {
+		s.finishDeletion(audit, err)
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to delete account",
+			err,
+		)
+	}
+
+	s.finishDeletion(audit, nil)
+
+	s.logger.WithFields(logrus.Fields{
+		"userID":        u.ID,
+		"deletionID":    audit.ID,
+		"invgateAction": audit.InvGateAction,
+		"requestedBy":   requestedBy,
+	}).Info("account deleted")
+
+	return &DeletionResponse{
+		DeletionID:    audit.ID,
+		InvGateAction: audit.InvGateAction,
+	}, nil
+}
+
+// removeInvGateUser deletes the InvGate user. InvGate refuses to delete users
+// that are referenced by tickets, in that case the user is disabled instead.
+func (s *service) removeInvGateUser(ctx context.Context, invGateUserID int) (string, error) {
+	deleteErr := s.invgateClient.DeleteUser(ctx, invGateUserID)
+	if deleteErr == nil {
+		return InvGateActionDeleted, nil
+	}
+
+	s.logger.WithError(deleteErr).WithField("invGateUserID", invGateUserID).Warn("failed to delete InvGate user, disabling it instead")
+	if err := s.invgateClient.DisableUser(ctx, invGateUserID); err != nil {
+		s.logger.WithError(err).WithField("invGateUserID", invGateUserID).Error("failed to disable InvGate user")
+		return "", fmt.Errorf("delete: %v; disable: %w", deleteErr, err)
+	}
+	return InvGateActionDeactivated, nil
+}
+
+// eraseLocalData revokes all tokens of the user and removes or anonymizes
+// everything stored locally about it.
+func (s *service) eraseLocalData(ctx context.Context, u *user.User) error {
+	if err := s.authService.RevokeUserSessions(ctx, u.ID, session.RevokedReasonAccountDeleted); err != nil {
+		return fmt.Errorf("revoke sessions: %w", err)
+	}
+	if err := s.sessionRepo.DeleteAllForUser(ctx, u.ID); err != nil {
+		return fmt.Errorf("delete sessions: %w", err)
+	}
+	if err := s.apiKeyRepo.DeleteAllForUser(ctx, u.ID); err != nil {
+		return fmt.Errorf("delete api keys: %w", err)
+	}
+	for _, purpose := range []string{usertoken.PurposePasswordReset, usertoken.PurposeEmailVerification} {
+		if err := s.tokenRepo.InvalidateForUser(ctx, u.ID, purpose); err != nil {
+			return fmt.Errorf("invalidate %s tokens: %w", purpose, err)
+		}
+	}
+	if err := s.loginAttemptRepo.Delete(ctx, loginattempt.ScopeEmail, loginattempt.EmailKey(u.TenantID, u.Email)); err != nil {
+		return fmt.Errorf("delete login attempts: %w", err)
+	}
+
+	exports, err := s.repository.ListExportsByUser(ctx, u.ID)
+	if err != nil {
+		return fmt.Errorf("list data exports: %w", err)
+	}
+	for i := range exports {
+		s.removeExport(ctx, &exports[i])
+	}
+
+	placeholder := fmt.Sprintf("deleted-%s@deleted.invalid", u.ID)
+	if err := s.ticketService.AnonymizeCreator(ctx, u.TenantID, u.Email, placeholder); err != nil {
+		return fmt.Errorf("anonymize tickets: %w", err)
+	}
+	if err := s.userRepo.Anonymize(ctx, u.ID, placeholder, time.Now().UTC()); err != nil {
+		return fmt.Errorf("anonymize user: %w", err)
+	}
+	return nil
+}
+
+// finishDeletion completes the audit record. Failures are only logged, the
+// deletion itself has already happened or failed at this point.
+func (s *service) finishDeletion(audit *AccountDeletion, cause error) {
+	now := time.Now().UTC()
+	audit.CompletedAt = &now
+	audit.Status = DeletionStatusCompleted
+	if cause != nil {
+		audit.Status = DeletionStatusFailed
+		audit.Error = truncate(cause.Error(), 500)
+	}
+
+	if err := s.repository.UpdateDeletion(context.Background(), audit); err != nil {
+		s.logger.WithError(err).WithField("deletionID", audit.ID).Error("failed to update account deletion record")
+	}
+}
+
+func (s *service) getUser(ctx context.Context, email string) (*user.User, error) {
+	if email == "" {
+		return nil, errors.NewAppError(
+			errors.ErrCodeUnauthorized,
+			"user email not found",
+			nil,
+		)
+	}
+
+	u, err := s.userRepo.GetByEmail(ctx, tenant.IDFromContext(ctx), email)
+	if err != nil {
+		s.logger.WithError(err).WithField("email", email).Error("failed to get user by email")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to retrieve user information",
+			err,
+		)
+	}
+	if u == nil {
+		return nil, errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"user not found",
+			nil,
+		)
+	}
+	return u, nil
+}
+
+// hashEmail returns the hex encoded SHA-256 of the normalized email.
+func hashEmail(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
@@ -0,0 +1,315 @@
+package privacy
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"werk-ticketing/internal/constants"
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/user"
+)
+
+var unsafeFilenameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// RequestExport queues a personal data export. While an export of the user is
+// still queued or running, that export is returned instead of a new one.
+func (s *service) RequestExport(ctx context.Context, email string) (*ExportResponse, error) {
+	u, err := s.getUser(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.repository.GetActiveExport(ctx, u.ID)
+	if err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to get active data export")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to request data export",
+			err,
+		)
+	}
+	if active != nil {
+		return toExportResponse(active), nil
+	}
+
+	export := &DataExport{
+		UserID: u.ID,
+		Status: ExportStatusPending,
+	}
+	if err := s.repository.CreateExport(ctx, export); err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to create data export")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to request data export",
+			err,
+		)
+	}
+
+	s.enqueueExport(export.ID)
+
+	s.logger.WithField("userID", u.ID).WithField("exportID", export.ID).Info("data export requested")
+	return toExportResponse(export), nil
+}
+
+// GetExport returns the state of an export of the user.
+func (s *service) GetExport(ctx context.Context, email, exportID string) (*ExportResponse, error) {
+	export, err := s.getOwnExport(ctx, email, exportID)
+	if err != nil {
+		return nil, err
+	}
+	return toExportResponse(export), nil
+}
+
+// OpenExport returns the path of the ZIP file of a completed export.
+func (s *service) OpenExport(ctx context.Context, email, exportID string) (string, error) {
+	export, err := s.getOwnExport(ctx, email, exportID)
+	if err != nil {
+		return "", err
+	}
+
+	if export.Status != ExportStatusCompleted || export.FilePath == "" {
+		return "", errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"data export is not ready yet",
+			nil,
+		)
+	}
+	if export.ExpiresAt != nil && time.Now().UTC().After(*export.ExpiresAt) {
+		return "", errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"data export has expired",
+			nil,
+		)
+	}
+
+	return export.FilePath, nil
+}
+
+func (s *service) getOwnExport(ctx context.Context, email, exportID string) (*DataExport, error) {
+	u, err := s.getUser(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := s.repository.GetExport(ctx, exportID)
+	if err != nil {
+		s.logger.WithError(err).WithField("exportID", exportID).Error("failed to get data export")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to get data export",
+			err,
+		)
+	}
+	// Exports of other users are reported as missing
+	if export == nil || export.UserID != u.ID {
+		return nil, errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"data export not found",
+			nil,
+		)
+	}
+	return export, nil
+}
+
+// runExportWorker builds queued exports one at a time. Each export is
+// claimed in the database first, so a copy queued by another process or an
+// export that is already done is skipped.
+func (s *service) runExportWorker() {
+	for id := range s.jobs {
+		s.dequeueExport(id)
+		s.processExport(id)
+	}
+}
+
+func (s *service) processExport(id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.DataExportTimeout)
+	defer cancel()
+
+	claimed, err := s.repository.ClaimExport(ctx, id, time.Now().UTC().Add(-constants.DataExportTimeout))
+	if err != nil {
+		s.logger.WithError(err).WithField("exportID", id).Error("failed to claim data export")
+		return
+	}
+	if !claimed {
+		// Already done, or being built by another worker
+		return
+	}
+
+	export, err := s.repository.GetExport(ctx, id)
+	if err != nil || export == nil {
+		s.logger.WithError(err).WithField("exportID", id).Error("failed to load data export")
+		return
+	}
+
+	path, err := s.buildExport(ctx, export)
+	now := time.Now().UTC()
+	if err != nil {
+		s.logger.WithError(err).WithField("exportID", id).Error("failed to build data export")
+		export.Status = ExportStatusFailed
+		export.Error = "failed to collect personal data, please request a new export"
+		export.CompletedAt = &now
+	} else {
+		expiresAt := now.Add(constants.DataExportExpiration)
+		export.Status = ExportStatusCompleted
+		export.FilePath = path
+		export.ExpiresAt = &expiresAt
+		export.CompletedAt = &now
+	}
+
+	if err := s.repository.UpdateExport(context.Background(), export); err != nil {
+		s.logger.WithError(err).WithField("exportID", id).Error("failed to update data export")
+		return
+	}
+
+	s.logger.WithField("exportID", id).WithField("status", export.Status).Info("data export finished")
+}
+
+// buildExport writes the ZIP file of an export and returns its path.
+// The file is written under a temporary name and renamed once complete.
+func (s *service) buildExport(ctx context.Context, export *DataExport) (string, error) {
+	u, err := s.userRepo.GetByID(ctx, export.UserID)
+	if err != nil {
+		return "", err
+	}
+	if u == nil || u.AnonymizedAt != nil {
+		return "", fmt.Errorf("user %s no longer exists", export.UserID)
+	}
+
+	// Exports run in the background, outside of the request that bound the tenant
+	ctx, err = s.tenants.WithTenant(ctx, u.TenantID)
+	if err != nil {
+		return "", fmt.Errorf("resolve tenant of user %s: %w", u.ID, err)
+	}
+
+	if err := os.MkdirAll(s.exportDir, 0o700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(s.exportDir, export.ID+".zip")
+	tmpPath := path + ".tmp"
+
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmpPath)
+
+	zw := zip.NewWriter(f)
+	if err := s.writeExport(ctx, zw, u); err != nil {
+		zw.Close()
+		f.Close()
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// writeExport adds the user profile, the tickets with their InvGate detail and
+// comments, and all attachment files to the archive.
+func (s *service) writeExport(ctx context.Context, zw *zip.Writer, u *user.User) error {
+	if err := writeJSON(zw, "user.json", exportedUser(u)); err != nil {
+		return err
+	}
+
+	tickets, err := s.ticketService.ExportUserTickets(ctx, u.Email)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tickets {
+		dir := "tickets/" + sanitizeFilename(t.Ticket.InvGateID)
+		for _, attachmentID := range t.AttachmentIDs {
+			data, filename, _, err := s.invgateClient.GetTicketAttachment(ctx, attachmentID)
+			if err != nil {
+				t.Errors = append(t.Errors, fmt.Sprintf("attachment %s: %v", attachmentID, err))
+				continue
+			}
+			name := sanitizeFilename(attachmentID)
+			if filename != "" {
+				name += "_" + sanitizeFilename(filepath.Base(filename))
+			}
+			w, err := zw.Create(dir + "/attachments/" + name)
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+		}
+
+		if err := writeJSON(zw, dir+"/ticket.json", t); err != nil {
+			return err
+		}
+	}
+
+	return ctx.Err()
+}
+
+// removeExport deletes the file and row of an export.
+func (s *service) removeExport(ctx context.Context, export *DataExport) {
+	if export.FilePath != "" {
+		if err := os.Remove(export.FilePath); err != nil && !os.IsNotExist(err) {
+			s.logger.WithError(err).WithField("exportID", export.ID).Warn("failed to remove data export file")
+			return
+		}
+	}
+	if err := s.repository.DeleteExport(ctx, export.ID); err != nil {
+		s.logger.WithError(err).WithField("exportID", export.ID).Warn("failed to delete data export")
+	}
+}
+
+// exportedUser lists the stored user fields. Secrets (password hash, MFA secret
+// and recovery codes) are left out; only whether they are set is reported.
+func exportedUser(u *user.User) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                 u.ID,
+		"name":               u.Name,
+		"last_name":          u.LastName,
+		"email":              u.Email,
+		"phone":              u.Phone,
+		"preferred_language": u.PreferredLanguage,
+		"role":               u.Role,
+		"invgate_user_id":    u.InvGateUserID,
+		"email_verified_at":  u.EmailVerifiedAt,
+		"mfa_enabled_at":     u.MFAEnabledAt,
+		"sso_linked":         u.OIDCSubject != nil,
+		"deactivated_at":     u.DeactivatedAt,
+		"created_by":         u.CreatedBy,
+		"updated_by":         u.UpdatedBy,
+		"created_at":         u.CreatedAt,
+		"updated_at":         u.UpdatedAt,
+	}
+}
+
+func writeJSON(zw *zip.Writer, name string, v interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func sanitizeFilename(name string) string {
+	name = unsafeFilenameChars.ReplaceAllString(name, "_")
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
@@ -0,0 +1,35 @@
+package privacy
+
+import (
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestEnqueueExportSkipsQueuedExports(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := &service{jobs: make(chan string, 2), queued: make(map[string]bool), logger: logger}
+
+	// The sweeper finds the same pending export on every run
+	s.enqueueExport("export-1")
+	s.enqueueExport("export-1")
+	s.enqueueExport("export-2")
+	if len(s.jobs) != 2 {
+		t.Fatalf("queue length = %d, want 2", len(s.jobs))
+	}
+
+	// A full queue drops the export; it stays pending and is retried later
+	s.enqueueExport("export-3")
+	if s.queued["export-3"] {
+		t.Fatal("export-3 marked as queued although the queue was full")
+	}
+
+	id := <-s.jobs
+	s.dequeueExport(id)
+	s.enqueueExport(id)
+	if len(s.jobs) != 2 {
+		t.Fatalf("queue length = %d, want the taken export to be queued again", len(s.jobs))
+	}
+}
@@ -0,0 +1,54 @@
+package profile
+
+import (
+	"time"
+
+	"werk-ticketing/internal/user"
+)
+
+// UpdateRequest changes the profile of the logged-in user.
+// Fields left out of the body are not changed.
+type UpdateRequest struct {
+	Name              *string `json:"name"`
+	LastName          *string `json:"last_name"`
+	Phone             *string `json:"phone"`
+	PreferredLanguage *string `json:"preferred_language"`
+}
+
+// Response is the profile of the logged-in user.
+type Response struct {
+	ID                string    `json:"id"`
+	Name              string    `json:"name"`
+	LastName          string    `json:"last_name"`
+	Email             string    `json:"email"`
+	Phone             string    `json:"phone"`
+	PreferredLanguage string    `json:"preferred_language"`
+	Role              string    `json:"role"`
+	EmailVerified     bool      `json:"email_verified"`
+	MFAEnabled        bool      `json:"mfa_enabled"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+func toResponse(u *user.User) *Response {
+	role := u.Role
+	if role == "" {
+		role = user.RoleRequester
+	}
+	lang := u.PreferredLanguage
+	if lang == "" {
+		lang = user.LanguageIndonesian
+	}
+
+	return &Response{
+		ID:                u.ID,
+		Name:              u.Name,
+		LastName:          u.LastName,
+		Email:             u.Email,
+		Phone:             u.Phone,
+		PreferredLanguage: lang,
+		Role:              role,
+		EmailVerified:     u.IsEmailVerified(),
+		MFAEnabled:        u.IsMFAEnabled(),
+		CreatedAt:         u.CreatedAt,
+	}
+}
@@ -0,0 +1,57 @@
+package profile
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/middleware"
+	"werk-ticketing/internal/response"
+)
+
+// Handler exposes HTTP handlers for profile routes.
+type Handler struct {
+	service Service
+}
+
+// NewHandler wires profile service into http handler.
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Get handles GET /api/v1/me
+func (h *Handler) Get(c *gin.Context) {
+	resp, err := h.service.Get(c.Request.Context(), middleware.GetUserEmail(c))
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
+
+// Update handles PATCH /api/v1/me
+func (h *Handler) Update(c *gin.Context) {
+	var req UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "invalid JSON body")
+		return
+	}
+
+	resp, err := h.service.Update(c.Request.Context(), middleware.GetUserEmail(c), req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, resp)
+}
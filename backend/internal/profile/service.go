@@ -0,0 +1,190 @@
+package profile
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/invgate"
+	"werk-ticketing/internal/tenant"
+	"werk-ticketing/internal/user"
+)
+
+var phoneRegex = regexp.MustCompile(`^\+?[0-9][0-9 \-]{4,28}$`)
+
+// Service exposes self-service profile use cases.
+type Service interface {
+	Get(ctx context.Context, email string) (*Response, error)
+	Update(ctx context.Context, email string, req UpdateRequest) (*Response, error)
+}
+
+type service struct {
+	userRepo      user.Repository
+	invgateClient invgate.Service
+	logger        *logrus.Logger
+}
+
+// NewService instantiates profile service.
+func NewService(userRepo user.Repository, invgateClient invgate.Service, logger *logrus.Logger) Service {
+	return &service{
+		userRepo:      userRepo,
+		invgateClient: invgateClient,
+		logger:        logger,
+	}
+}
+
+func (s *service) Get(ctx context.Context, email string) (*Response, error) {
+	u, err := s.getUser(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	return toResponse(u), nil
+}
+
+// Update changes the profile locally and in InvGate. When InvGate rejects the
+// change the previous local profile is restored.
+func (s *service) Update(ctx context.Context, email string, req UpdateRequest) (*Response, error) {
+	u, err := s.getUser(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := u.Profile()
+	updated, err := applyUpdate(previous, req)
+	if err != nil {
+		return nil, err
+	}
+	if updated == previous {
+		return toResponse(u), nil
+	}
+
+	if err := s.userRepo.UpdateProfile(ctx, u.ID, updated, u.Email); err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to update profile")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to update profile",
+			err,
+		)
+	}
+
+	if u.InvGateUserID > 0 {
+		payload := invgate.UpdateUserPayload{
+			ID:       u.InvGateUserID,
+			Name:     updated.Name,
+			LastName: updated.LastName,
+			Phone:    updated.Phone,
+			Language: updated.PreferredLanguage,
+		}
+		if err := s.invgateClient.UpdateUser(ctx, payload); err != nil {
+			s.logger.WithError(err).WithField("invgateUserID", u.InvGateUserID).Error("failed to update user in InvGate")
+
+			// Keep both systems consistent: restore the previous local profile
+			if rbErr := s.userRepo.UpdateProfile(ctx, u.ID, previous, u.UpdatedBy); rbErr != nil {
+				s.logger.WithError(rbErr).WithField("userID", u.ID).Error("failed to restore previous profile after InvGate failure")
+			}
+
+			return nil, errors.NewAppError(
+				errors.ErrCodeExternalService,
+				"failed to update profile in InvGate",
+				err,
+			)
+		}
+	}
+
+	u.Name = updated.Name
+	u.LastName = updated.LastName
+	u.Phone = updated.Phone
+	u.PreferredLanguage = updated.PreferredLanguage
+
+	s.logger.WithField("userID", u.ID).Info("profile updated")
+	return toResponse(u), nil
+}
+
+func (s *service) getUser(ctx context.Context, email string) (*user.User, error) {
+	if email == "" {
+		return nil, errors.NewAppError(
+			errors.ErrCodeUnauthorized,
+			"user email not found",
+			nil,
+		)
+	}
+
+	u, err := s.userRepo.GetByEmail(ctx, tenant.IDFromContext(ctx), email)
+	if err != nil {
+		s.logger.WithError(err).WithField("email", email).Error("failed to get user by email")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to retrieve user information",
+			err,
+		)
+	}
+	if u == nil {
+		return nil, errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"user not found",
+			nil,
+		)
+	}
+	return u, nil
+}
+
+// applyUpdate validates the requested changes and merges them into the current profile.
+func applyUpdate(current user.Profile, req UpdateRequest) (user.Profile, error) {
+	updated := current
+	if updated.PreferredLanguage == "" {
+		updated.PreferredLanguage = user.LanguageIndonesian
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || len(name) > 100 {
+			return current, errors.NewAppError(
+				errors.ErrCodeInvalidInput,
+				"name must be between 1 and 100 characters",
+				nil,
+			)
+		}
+		updated.Name = name
+	}
+
+	if req.LastName != nil {
+		lastName := strings.TrimSpace(*req.LastName)
+		if lastName == "" || len(lastName) > 100 {
+			return current, errors.NewAppError(
+				errors.ErrCodeInvalidInput,
+				"last name must be between 1 and 100 characters",
+				nil,
+			)
+		}
+		updated.LastName = lastName
+	}
+
+	if req.Phone != nil {
+		phone := strings.TrimSpace(*req.Phone)
+		if phone != "" && !phoneRegex.MatchString(phone) {
+			return current, errors.NewAppError(
+				errors.ErrCodeInvalidInput,
+				"invalid phone number format",
+				nil,
+			)
+		}
+		updated.Phone = phone
+	}
+
+	if req.PreferredLanguage != nil {
+		lang := strings.ToLower(strings.TrimSpace(*req.PreferredLanguage))
+		if !user.IsValidLanguage(lang) {
+			return current, errors.NewAppError(
+				errors.ErrCodeInvalidInput,
+				"preferred language must be one of: id, en",
+				nil,
+			)
+		}
+		updated.PreferredLanguage = lang
+	}
+
+	return updated, nil
+}
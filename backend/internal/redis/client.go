@@ -0,0 +1,331 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrNil is returned when the server replies with a nil bulk string.
+var ErrNil = errors.New("redis: nil reply")
+
+// Options configures the client.
+type Options struct {
+	Addr     string
+	Password string
+	DB       int
+	PoolSize int
+	Timeout  time.Duration
+}
+
+// Client is a minimal RESP2 client that speaks to Redis or any Redis-protocol
+// compatible server (Valkey, KeyDB, Dragonfly, or a local stand-in).
+// Only the commands used by this service are wrapped; Do can send any command.
+type Client struct {
+	opts  Options
+	conns chan *conn
+	mu    sync.Mutex
+	open  int
+}
+
+type conn struct {
+	nc net.Conn
+	rd *bufio.Reader
+}
+
+// NewClient creates a client. Connections are opened lazily.
+func NewClient(opts Options) *Client {
+	if opts.PoolSize <= 0 {
+		opts.PoolSize = 10
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 3 * time.Second
+	}
+	return &Client{
+		opts:  opts,
+		conns: make(chan *conn, opts.PoolSize),
+	}
+}
+
+// Ping checks that the server is reachable.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+// Get returns the value of key, or ErrNil when it does not exist.
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	reply, err := c.Do(ctx, "GET", key)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := reply.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("redis: unexpected GET reply %T", reply)
+	}
+	return b, nil
+}
+
+// Set stores value under key. A positive ttl is applied with millisecond precision.
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", key, string(value)}
+	if ttl > 0 {
+		ms := ttl.Milliseconds()
+		if ms < 1 {
+			ms = 1
+		}
+		args = append(args, "PX", strconv.FormatInt(ms, 10))
+	}
+	_, err := c.Do(ctx, args...)
+	return err
+}
+
+// Exists reports whether key exists.
+func (c *Client) Exists(ctx context.Context, key string) (bool, error) {
+	reply, err := c.Do(ctx, "EXISTS", key)
+	if err != nil {
+		return false, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return false, fmt.Errorf("redis: unexpected EXISTS reply %T", reply)
+	}
+	return n > 0, nil
+}
+
+// Del removes the given keys and returns how many existed.
+func (c *Client) Del(ctx context.Context, keys ...string) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	reply, err := c.Do(ctx, append([]string{"DEL"}, keys...)...)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := reply.(int64)
+	return n, nil
+}
+
+// Do sends a command and returns the decoded reply.
+// Replies are string (simple string), int64, []byte (bulk string) or []interface{} (array).
+// A nil bulk string is reported as ErrNil; server errors are returned as error.
+func (c *Client) Do(ctx context.Context, args ...string) (interface{}, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(c.opts.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = cn.nc.SetDeadline(deadline)
+
+	reply, err := cn.roundTrip(args)
+	if err != nil {
+		var serverErr Error
+		if errors.As(err, &serverErr) || errors.Is(err, ErrNil) {
+			// Protocol-level replies are read completely, including
+			// error elements of arrays, so the connection stays usable.
+			c.put(cn)
+		} else {
+			c.discard(cn)
+		}
+		return nil, err
+	}
+
+	c.put(cn)
+	return reply, nil
+}
+
+// Close closes all idle connections.
+func (c *Client) Close() error {
+	for {
+		select {
+		case cn := <-c.conns:
+			c.discard(cn)
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-c.conns:
+		return cn, nil
+	default:
+	}
+
+	c.mu.Lock()
+	if c.open >= c.opts.PoolSize {
+		c.mu.Unlock()
+		select {
+		case cn := <-c.conns:
+			return cn, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	c.open++
+	c.mu.Unlock()
+
+	cn, err := c.dial(ctx)
+	if err != nil {
+		c.mu.Lock()
+		c.open--
+		c.mu.Unlock()
+		return nil, err
+	}
+	return cn, nil
+}
+
+func (c *Client) put(cn *conn) {
+	select {
+	case c.conns <- cn:
+	default:
+		c.discard(cn)
+	}
+}
+
+func (c *Client) discard(cn *conn) {
+	_ = cn.nc.Close()
+	c.mu.Lock()
+	c.open--
+	c.mu.Unlock()
+}
+
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	dialer := net.Dialer{Timeout: c.opts.Timeout}
+	nc, err := dialer.DialContext(ctx, "tcp", c.opts.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis: dial %s: %w", c.opts.Addr, err)
+	}
+	cn := &conn{nc: nc, rd: bufio.NewReader(nc)}
+	_ = nc.SetDeadline(time.Now().Add(c.opts.Timeout))
+
+	if c.opts.Password != "" {
+		if _, err := cn.roundTrip([]string{"AUTH", c.opts.Password}); err != nil {
+			nc.Close()
+			return nil, fmt.Errorf("redis: auth: %w", err)
+		}
+	}
+	if c.opts.DB > 0 {
+		if _, err := cn.roundTrip([]string{"SELECT", strconv.Itoa(c.opts.DB)}); err != nil {
+			nc.Close()
+			return nil, fmt.Errorf("redis: select db: %w", err)
+		}
+	}
+	return cn, nil
+}
+
+func (cn *conn) roundTrip(args []string) (interface{}, error) {
+	if err := writeCommand(cn.nc, args); err != nil {
+		return nil, err
+	}
+	return readReply(cn.rd)
+}
+
+// Error is an error reply sent by the server.
+type Error string
+
+func (e Error) Error() string { return "redis: " + string(e) }
+
+func writeCommand(w io.Writer, args []string) error {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+func readReply(rd *bufio.Reader) (interface{}, error) {
+	line, err := readLine(rd)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return string(line[1:]), nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		return strconv.ParseInt(string(line[1:]), 10, 64)
+	case '$':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, ErrNil
+		}
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, b); err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	case '*':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, ErrNil
+		}
+		// Read every element before reporting an error element, so the
+		// connection is left at the start of the next reply.
+		items := make([]interface{}, 0, n)
+		var replyErr error
+		for i := 0; i < n; i++ {
+			item, err := readReply(rd)
+			if err != nil {
+				var serverErr Error
+				switch {
+				case errors.Is(err, ErrNil):
+				case errors.As(err, &serverErr):
+					if replyErr == nil {
+						replyErr = err
+					}
+				default:
+					return nil, err
+				}
+			}
+			items = append(items, item)
+		}
+		if replyErr != nil {
+			return nil, replyErr
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply type %q", line[0])
+	}
+}
+
+func readLine(rd *bufio.Reader) ([]byte, error) {
+	line, err := rd.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, errors.New("redis: malformed reply line")
+	}
+	return line[:len(line)-2], nil
+}
@@ -0,0 +1,172 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"werk-ticketing/internal/redis"
+	"werk-ticketing/internal/redis/redistest"
+)
+
+func newServer(t *testing.T) *redistest.Server {
+	t.Helper()
+	srv, err := redistest.NewServer()
+	if err != nil {
+		t.Fatalf("start fake server: %v", err)
+	}
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newClient(t *testing.T, opts redis.Options) *redis.Client {
+	t.Helper()
+	client := redis.NewClient(opts)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestClientCommands(t *testing.T) {
+	srv := newServer(t)
+	client := newClient(t, redis.Options{Addr: srv.Addr})
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	if _, err := client.Get(ctx, "missing"); !errors.Is(err, redis.ErrNil) {
+		t.Fatalf("Get missing = %v, want ErrNil", err)
+	}
+	if err := client.Set(ctx, "key", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if ttl := srv.TTL("key"); ttl <= 0 || ttl > time.Minute {
+		t.Fatalf("ttl = %s, want up to a minute", ttl)
+	}
+	got, err := client.Get(ctx, "key")
+	if err != nil || string(got) != "value" {
+		t.Fatalf("Get = %q, %v", got, err)
+	}
+	if ok, err := client.Exists(ctx, "key"); err != nil || !ok {
+		t.Fatalf("Exists = %v, %v", ok, err)
+	}
+	if n, err := client.Del(ctx, "key", "missing"); err != nil || n != 1 {
+		t.Fatalf("Del = %d, %v", n, err)
+	}
+	if ok, err := client.Exists(ctx, "key"); err != nil || ok {
+		t.Fatalf("Exists after Del = %v, %v", ok, err)
+	}
+}
+
+func TestClientAuthAndSelect(t *testing.T) {
+	srv := newServer(t)
+	srv.RequirePassword("secret")
+	ctx := context.Background()
+
+	client := newClient(t, redis.Options{Addr: srv.Addr, Password: "secret", DB: 2})
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	want := [][]string{{"AUTH", "secret"}, {"SELECT", "2"}, {"PING"}}
+	if got := srv.Commands(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("commands = %q, want %q", got, want)
+	}
+
+	wrong := newClient(t, redis.Options{Addr: srv.Addr, Password: "wrong"})
+	if err := wrong.Ping(ctx); err == nil {
+		t.Fatal("Ping with wrong password succeeded")
+	}
+}
+
+func TestClientServerErrorKeepsConnection(t *testing.T) {
+	srv := newServer(t)
+	client := newClient(t, redis.Options{Addr: srv.Addr, PoolSize: 1})
+	ctx := context.Background()
+
+	var serverErr redis.Error
+	if _, err := client.Do(ctx, "NOPE"); !errors.As(err, &serverErr) {
+		t.Fatalf("Do = %v, want server error", err)
+	}
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping after error: %v", err)
+	}
+	if n := srv.Connections(); n != 1 {
+		t.Fatalf("connections = %d, want the connection to be reused", n)
+	}
+}
+
+func TestClientArrayReplies(t *testing.T) {
+	srv := newServer(t)
+	srv.Handle(func(args []string) (string, bool) {
+		switch args[0] {
+		case "ARRAY":
+			return "*3\r\n$1\r\na\r\n$-1\r\n:7\r\n", true
+		case "NESTED":
+			return "*2\r\n*1\r\n+OK\r\n$1\r\nb\r\n", true
+		case "ERRARRAY":
+			// The error element is followed by more data of the same reply
+			return "*4\r\n$1\r\na\r\n-ERR first\r\n-ERR second\r\n$5\r\ntrail\r\n", true
+		}
+		return "", false
+	})
+	client := newClient(t, redis.Options{Addr: srv.Addr, PoolSize: 1})
+	ctx := context.Background()
+
+	got, err := client.Do(ctx, "ARRAY")
+	if err != nil {
+		t.Fatalf("ARRAY: %v", err)
+	}
+	if want := []interface{}{[]byte("a"), nil, int64(7)}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("ARRAY = %#v, want %#v", got, want)
+	}
+
+	got, err = client.Do(ctx, "NESTED")
+	if err != nil {
+		t.Fatalf("NESTED: %v", err)
+	}
+	if want := []interface{}{[]interface{}{"OK"}, []byte("b")}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("NESTED = %#v, want %#v", got, want)
+	}
+
+	_, err = client.Do(ctx, "ERRARRAY")
+	if err == nil || err.Error() != "redis: ERR first" {
+		t.Fatalf("ERRARRAY = %v, want the first error element", err)
+	}
+
+	// The rest of the array must have been consumed: the next reply on the
+	// same connection belongs to the next command.
+	if err := client.Set(ctx, "key", []byte("value"), 0); err != nil {
+		t.Fatalf("Set after error array: %v", err)
+	}
+	value, err := client.Get(ctx, "key")
+	if err != nil || string(value) != "value" {
+		t.Fatalf("Get after error array = %q, %v", value, err)
+	}
+	if n := srv.Connections(); n != 1 {
+		t.Fatalf("connections = %d, want 1", n)
+	}
+}
+
+func TestClientDiscardsBrokenConnection(t *testing.T) {
+	srv := newServer(t)
+	srv.Handle(func(args []string) (string, bool) {
+		if args[0] == "BROKEN" {
+			return "?garbage\r\n", true
+		}
+		return "", false
+	})
+	client := newClient(t, redis.Options{Addr: srv.Addr, PoolSize: 1})
+	ctx := context.Background()
+
+	if _, err := client.Do(ctx, "BROKEN"); err == nil {
+		t.Fatal("BROKEN succeeded")
+	}
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping after protocol error: %v", err)
+	}
+	if n := srv.Connections(); n != 2 {
+		t.Fatalf("connections = %d, want a new connection after a protocol error", n)
+	}
+}
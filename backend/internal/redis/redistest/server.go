@@ -0,0 +1,260 @@
+// Package redistest runs an in-memory Redis-protocol server for tests. It
+// understands the commands used by this service (PING, AUTH, SELECT, GET,
+// SET with PX, EXISTS, DEL); others can be answered with raw replies.
+package redistest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Handler answers a command with a raw RESP reply. It returns false to fall
+// back to the built-in commands.
+type Handler func(args []string) (string, bool)
+
+// Server is a fake Redis-protocol server listening on a local port.
+type Server struct {
+	Addr string
+
+	listener net.Listener
+
+	mu       sync.Mutex
+	password string
+	values   map[string]entry
+	handler  Handler
+	commands [][]string
+	conns    int
+}
+
+type entry struct {
+	value     string
+	expiresAt time.Time // zero means no expiry
+}
+
+// NewServer starts a server on a random local port. Call Close when done.
+func NewServer() (*Server, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		Addr:     l.Addr().String(),
+		listener: l,
+		values:   make(map[string]entry),
+	}
+	go s.serve()
+	return s, nil
+}
+
+// Close stops accepting connections.
+func (s *Server) Close() {
+	_ = s.listener.Close()
+}
+
+// RequirePassword makes new connections authenticate with AUTH first.
+func (s *Server) RequirePassword(password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.password = password
+}
+
+// Handle installs a handler consulted before the built-in commands.
+func (s *Server) Handle(h Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handler = h
+}
+
+// Commands returns the commands received so far.
+func (s *Server) Commands() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]string(nil), s.commands...)
+}
+
+// Connections returns how many connections were accepted.
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conns
+}
+
+// TTL returns the remaining lifetime of key, or -1 when it has no expiry.
+func (s *Server) TTL(key string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.values[key]
+	if !ok || e.expiresAt.IsZero() {
+		return -1
+	}
+	return time.Until(e.expiresAt)
+}
+
+func (s *Server) serve() {
+	for {
+		nc, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns++
+		s.mu.Unlock()
+		go s.serveConn(nc)
+	}
+}
+
+func (s *Server) serveConn(nc net.Conn) {
+	defer nc.Close()
+	rd := bufio.NewReader(nc)
+	s.mu.Lock()
+	password := s.password
+	s.mu.Unlock()
+	authed := password == ""
+
+	for {
+		args, err := readCommand(rd)
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.commands = append(s.commands, args)
+		handler := s.handler
+		s.mu.Unlock()
+
+		var reply string
+		handled := false
+		if handler != nil {
+			reply, handled = handler(args)
+		}
+		if !handled {
+			switch {
+			case strings.EqualFold(args[0], "AUTH"):
+				authed = len(args) == 2 && args[1] == password
+				reply = "+OK\r\n"
+				if !authed {
+					reply = "-WRONGPASS invalid password\r\n"
+				}
+			case !authed:
+				reply = "-NOAUTH Authentication required.\r\n"
+			default:
+				reply = s.execute(args)
+			}
+		}
+
+		if _, err := io.WriteString(nc, reply); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) execute(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "SELECT":
+		return "+OK\r\n"
+	case "GET":
+		if len(args) != 2 {
+			return wrongArgs(args[0])
+		}
+		e, ok := s.lookup(args[1])
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(e.value)
+	case "SET":
+		if len(args) != 3 && len(args) != 5 {
+			return wrongArgs(args[0])
+		}
+		e := entry{value: args[2]}
+		if len(args) == 5 {
+			ms, err := strconv.ParseInt(args[4], 10, 64)
+			if !strings.EqualFold(args[3], "PX") || err != nil || ms <= 0 {
+				return "-ERR syntax error\r\n"
+			}
+			e.expiresAt = time.Now().Add(time.Duration(ms) * time.Millisecond)
+		}
+		s.values[args[1]] = e
+		return "+OK\r\n"
+	case "EXISTS", "DEL":
+		if len(args) < 2 {
+			return wrongArgs(args[0])
+		}
+		n := 0
+		for _, key := range args[1:] {
+			if _, ok := s.lookup(key); ok {
+				n++
+				if strings.EqualFold(args[0], "DEL") {
+					delete(s.values, key)
+				}
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	default:
+		return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+	}
+}
+
+// lookup returns a live entry; the caller holds s.mu.
+func (s *Server) lookup(key string) (entry, bool) {
+	e, ok := s.values[key]
+	if ok && !e.expiresAt.IsZero() && !time.Now().Before(e.expiresAt) {
+		delete(s.values, key)
+		return entry{}, false
+	}
+	return e, ok
+}
+
+func bulk(v string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+}
+
+func wrongArgs(cmd string) string {
+	return fmt.Sprintf("-ERR wrong number of arguments for '%s' command\r\n", strings.ToLower(cmd))
+}
+
+// readCommand reads a command sent as an array of bulk strings.
+func readCommand(rd *bufio.Reader) ([]string, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("redistest: expected array, got %q", line)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("redistest: invalid array length %q", line)
+	}
+
+	args := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		line, err := rd.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(line, "$") {
+			return nil, fmt.Errorf("redistest: expected bulk string, got %q", line)
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("redistest: invalid bulk length %q", line)
+		}
+		b := make([]byte, size+2)
+		if _, err := io.ReadFull(rd, b); err != nil {
+			return nil, err
+		}
+		args = append(args, string(b[:size]))
+	}
+	return args, nil
+}
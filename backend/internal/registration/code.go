@@ -0,0 +1,30 @@
+package registration
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+)
+
+// CodePrefix marks invite codes so they are recognizable when pasted.
+const CodePrefix = "inv_"
+
+var codeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateCode returns a new random invite code.
+func generateCode() (string, error) {
+	b := make([]byte, 15)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return CodePrefix + strings.ToLower(codeEncoding.EncodeToString(b)), nil
+}
+
+// hashCode hashes a code as entered by the user. Case and surrounding
+// whitespace are ignored so codes read out loud or retyped still match.
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
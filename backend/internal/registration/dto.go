@@ -0,0 +1,66 @@
+package registration
+
+import "time"
+
+// CreateInviteRequest incoming body for issuing an invite code.
+// Scope IDs that are omitted fall back to the configured defaults and
+// ExpiresAt defaults to constants.InviteDefaultExpiration from now.
+type CreateInviteRequest struct {
+	Email      string     `json:"email" binding:"omitempty,email" validate:"omitempty,email"`
+	CompanyID  int        `json:"company_id" binding:"min=0" validate:"min=0"`
+	GroupID    int        `json:"group_id" binding:"min=0" validate:"min=0"`
+	LocationID int        `json:"location_id" binding:"min=0" validate:"min=0"`
+	Note       string     `json:"note" binding:"max=255" validate:"max=255"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
+
+// InviteResponse describes an invite without its code.
+type InviteResponse struct {
+	ID        string     `json:"id"`
+	Email     string     `json:"email,omitempty"`
+	Scope     Scope      `json:"scope"`
+	Note      string     `json:"note,omitempty"`
+	Status    string     `json:"status"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	UsedBy    string     `json:"used_by,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// CreateInviteResponse is returned once on creation. Code is never shown again.
+type CreateInviteResponse struct {
+	InviteResponse
+	Code string `json:"code"`
+}
+
+// InviteListResponse is a page of invites.
+type InviteListResponse struct {
+	Data  []InviteResponse `json:"data"`
+	Total int64            `json:"total"`
+	Page  int              `json:"page"`
+	Limit int              `json:"limit"`
+}
+
+// Grant is the outcome of a successful registration check.
+type Grant struct {
+	Scope    Scope
+	InviteID string // Set when an invite was claimed, empty for domain matches
+}
+
+func toInviteResponse(i *Invite, now time.Time) InviteResponse {
+	return InviteResponse{
+		ID:        i.ID,
+		Email:     i.Email,
+		Scope:     i.Scope(),
+		Note:      i.Note,
+		Status:    i.Status(now),
+		ExpiresAt: i.ExpiresAt,
+		UsedAt:    i.UsedAt,
+		UsedBy:    i.UsedBy,
+		RevokedAt: i.RevokedAt,
+		CreatedBy: i.CreatedBy,
+		CreatedAt: i.CreatedAt,
+	}
+}
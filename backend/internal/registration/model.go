@@ -0,0 +1,74 @@
+package registration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"werk-ticketing/internal/database"
+)
+
+// Invite states reported to admins.
+const (
+	InviteStatusActive  = "active"
+	InviteStatusUsed    = "used"
+	InviteStatusExpired = "expired"
+	InviteStatusRevoked = "revoked"
+)
+
+// Invite is a single-use invite code issued by an admin. It lets one person
+// register regardless of their email domain and assigns them to the scope of
+// the invite. Only the SHA-256 hash of the code is stored.
+type Invite struct {
+	ID         string     `gorm:"type:char(36);primaryKey"`
+	TenantID   string     `gorm:"type:char(36);not null;default:'';index"` // Tenant the invite registers users for
+	CodeHash   string     `gorm:"size:64;not null;uniqueIndex"`            // hex encoded SHA-256 of the code
+	Email      string     `gorm:"size:190;index"`                          // Optional, restricts the invite to this address
+	CompanyID  int        `gorm:"not null;default:0;column:invgate_company_id"`
+	GroupID    int        `gorm:"not null;default:0;column:invgate_group_id"`
+	LocationID int        `gorm:"not null;default:0;column:invgate_location_id"`
+	Note       string     `gorm:"size:255"`
+	ExpiresAt  time.Time  `gorm:"not null;index"`
+	UsedAt     *time.Time // Set when a registration claimed the invite
+	UsedBy     string     `gorm:"size:190"` // Email of the user who registered with the invite
+	RevokedAt  *time.Time
+	CreatedBy  string    `gorm:"size:190;column:created_by"` // Email of the admin who issued the invite
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (Invite) TableName() string {
+	return "registration_invites"
+}
+
+// BeforeCreate assigns the UUID in Go so the ID is known to the caller after insert.
+func (i *Invite) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = database.NewUUID()
+	}
+	return nil
+}
+
+// Scope returns the InvGate scope of the invite.
+func (i *Invite) Scope() Scope {
+	return Scope{
+		CompanyID:  i.CompanyID,
+		GroupID:    i.GroupID,
+		LocationID: i.LocationID,
+	}
+}
+
+// Status reports whether the invite can still be used, and why not.
+func (i *Invite) Status(now time.Time) string {
+	switch {
+	case i.RevokedAt != nil:
+		return InviteStatusRevoked
+	case i.UsedAt != nil:
+		return InviteStatusUsed
+	case !now.Before(i.ExpiresAt):
+		return InviteStatusExpired
+	default:
+		return InviteStatusActive
+	}
+}
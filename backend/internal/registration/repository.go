@@ -0,0 +1,116 @@
+package registration
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Repository abstracts data persistence for invite codes.
+type Repository interface {
+	Create(ctx context.Context, invite *Invite) error
+	GetByID(ctx context.Context, id string) (*Invite, error)
+	GetByCodeHash(ctx context.Context, codeHash string) (*Invite, error)
+	List(ctx context.Context, tenantID string, limit, offset int) ([]Invite, int64, error)
+	Claim(ctx context.Context, id, email string, at time.Time) (bool, error)
+	Release(ctx context.Context, id string) error
+	Revoke(ctx context.Context, id string, at time.Time) (bool, error)
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository builds a Gorm-backed invite repository.
+func NewRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+func (r *gormRepository) Create(ctx context.Context, invite *Invite) error {
+	return r.db.WithContext(ctx).Create(invite).Error
+}
+
+func (r *gormRepository) GetByID(ctx context.Context, id string) (*Invite, error) {
+	var invite Invite
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&invite).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (r *gormRepository) GetByCodeHash(ctx context.Context, codeHash string) (*Invite, error) {
+	var invite Invite
+	err := r.db.WithContext(ctx).Where("code_hash = ?", codeHash).First(&invite).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// List returns the invites of a tenant newest first together with the total count.
+func (r *gormRepository) List(ctx context.Context, tenantID string, limit, offset int) ([]Invite, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&Invite{}).Where("tenant_id = ?", tenantID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var invites []Invite
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&invites).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return invites, total, nil
+}
+
+// Claim marks an unused, unrevoked and unexpired invite as used by email.
+// It reports false when another registration claimed the invite first.
+func (r *gormRepository) Claim(ctx context.Context, id, email string, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&Invite{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL AND expires_at > ?", id, at).
+		Updates(map[string]interface{}{
+			"used_at": at,
+			"used_by": email,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Release makes a claimed invite usable again after the registration failed.
+func (r *gormRepository) Release(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).
+		Model(&Invite{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"used_at": nil,
+			"used_by": "",
+		}).Error
+}
+
+// Revoke revokes an invite that has not been used yet.
+func (r *gormRepository) Revoke(ctx context.Context, id string, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&Invite{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
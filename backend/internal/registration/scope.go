@@ -0,0 +1,102 @@
+package registration
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Scope is the InvGate company, group and location a new user is assigned to.
+// A zero ID skips that assignment.
+type Scope struct {
+	CompanyID  int `json:"company_id"`
+	GroupID    int `json:"group_id"`
+	LocationID int `json:"location_id"`
+}
+
+// IsZero reports whether no ID is set.
+func (s Scope) IsZero() bool {
+	return s.CompanyID == 0 && s.GroupID == 0 && s.LocationID == 0
+}
+
+// withDefaults fills IDs that are not set from defaults.
+func (s Scope) withDefaults(defaults Scope) Scope {
+	if s.CompanyID == 0 {
+		s.CompanyID = defaults.CompanyID
+	}
+	if s.GroupID == 0 {
+		s.GroupID = defaults.GroupID
+	}
+	if s.LocationID == 0 {
+		s.LocationID = defaults.LocationID
+	}
+	return s
+}
+
+// DomainRule allows self-service registration for one email domain and maps
+// it to the InvGate scope its users are assigned to.
+type DomainRule struct {
+	Domain string
+	Scope  Scope
+}
+
+// ParseDomainRules parses a comma separated list of rules of the form
+// domain=company[:group[:location]], e.g. "armmada.id=135:134:136,partner.co.id=200".
+// IDs that are omitted or 0 fall back to defaults.
+func ParseDomainRules(raw string, defaults Scope) ([]DomainRule, error) {
+	var rules []DomainRule
+	seen := make(map[string]bool)
+
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		domain, ids, _ := strings.Cut(entry, "=")
+		domain = normalizeDomain(domain)
+		if domain == "" || strings.ContainsAny(domain, "@ ") {
+			return nil, fmt.Errorf("invalid registration domain %q", entry)
+		}
+		if seen[domain] {
+			return nil, fmt.Errorf("registration domain %q is listed twice", domain)
+		}
+		seen[domain] = true
+
+		var scope Scope
+		if ids != "" {
+			parts := strings.Split(ids, ":")
+			if len(parts) > 3 {
+				return nil, fmt.Errorf("registration domain %q: expected company[:group[:location]]", domain)
+			}
+			targets := []*int{&scope.CompanyID, &scope.GroupID, &scope.LocationID}
+			for i, part := range parts {
+				if part == "" {
+					continue
+				}
+				id, err := strconv.Atoi(part)
+				if err != nil || id < 0 {
+					return nil, fmt.Errorf("registration domain %q: invalid id %q", domain, part)
+				}
+				*targets[i] = id
+			}
+		}
+
+		rules = append(rules, DomainRule{Domain: domain, Scope: scope.withDefaults(defaults)})
+	}
+
+	return rules, nil
+}
+
+// EmailDomain returns the lower-cased domain part of an email address.
+func EmailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return normalizeDomain(email[at+1:])
+}
+
+func normalizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
@@ -0,0 +1,322 @@
+package registration
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/constants"
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/tenant"
+)
+
+const (
+	defaultInvitePageSize = 20
+	maxInvitePageSize     = 100
+)
+
+// Service decides who may register and manages invite codes.
+type Service interface {
+	// Resolve checks that email may register, either with inviteCode or
+	// through the domain allowlist, and returns the scope to assign.
+	// A claimed invite must be released with ReleaseInvite when the
+	// registration fails afterwards.
+	Resolve(ctx context.Context, email, inviteCode string) (*Grant, error)
+	ReleaseInvite(ctx context.Context, inviteID string)
+	ScopeForEmail(ctx context.Context, email string) (Scope, bool)
+	DefaultScope(ctx context.Context) Scope
+
+	CreateInvite(ctx context.Context, actorEmail string, req CreateInviteRequest) (*CreateInviteResponse, error)
+	ListInvites(ctx context.Context, page, limit int) (*InviteListResponse, error)
+	RevokeInvite(ctx context.Context, id, actorEmail string) error
+}
+
+type service struct {
+	repository   Repository
+	domains      map[string]Scope
+	defaultScope Scope
+	logger       *logrus.Logger
+}
+
+// NewService instantiates the registration service. Requests bound to a tenant
+// use the domains and scope of that tenant, domains and defaultScope are only
+// used outside of one.
+func NewService(repository Repository, domains []DomainRule, defaultScope Scope, logger *logrus.Logger) Service {
+	byDomain := make(map[string]Scope, len(domains))
+	for _, rule := range domains {
+		byDomain[rule.Domain] = rule.Scope
+	}
+	if len(byDomain) == 0 {
+		logger.Warn("no registration domains configured, self-service registration requires an invite code")
+	}
+
+	return &service{
+		repository:   repository,
+		domains:      byDomain,
+		defaultScope: defaultScope,
+		logger:       logger,
+	}
+}
+
+func (s *service) Resolve(ctx context.Context, email, inviteCode string) (*Grant, error) {
+	if code := strings.TrimSpace(inviteCode); code != "" {
+		return s.claimInvite(ctx, email, code)
+	}
+
+	if scope, ok := s.ScopeForEmail(ctx, email); ok {
+		return &Grant{Scope: scope}, nil
+	}
+
+	s.logger.WithField("domain", EmailDomain(email)).Warn("registration rejected: email domain not allowed")
+	return nil, errors.NewAppError(
+		errors.ErrCodeForbidden,
+		"registration is only open to invited users and allowed email domains",
+		nil,
+	)
+}
+
+func (s *service) claimInvite(ctx context.Context, email, code string) (*Grant, error) {
+	invalid := errors.NewAppError(
+		errors.ErrCodeInvalidInput,
+		"invite code is invalid or has expired",
+		nil,
+	)
+
+	invite, err := s.repository.GetByCodeHash(ctx, hashCode(code))
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get invite")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to check invite code",
+			err,
+		)
+	}
+
+	now := time.Now().UTC()
+	if invite == nil || invite.Status(now) != InviteStatusActive || invite.TenantID != tenant.IDFromContext(ctx) {
+		return nil, invalid
+	}
+	// Invites bound to an address are reported like unknown codes so they cannot be probed
+	if invite.Email != "" && !strings.EqualFold(invite.Email, email) {
+		s.logger.WithField("inviteID", invite.ID).Warn("invite code used with another email address")
+		return nil, invalid
+	}
+
+	claimed, err := s.repository.Claim(ctx, invite.ID, email, now)
+	if err != nil {
+		s.logger.WithError(err).WithField("inviteID", invite.ID).Error("failed to claim invite")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to check invite code",
+			err,
+		)
+	}
+	if !claimed {
+		return nil, invalid
+	}
+
+	s.logger.WithField("inviteID", invite.ID).Info("invite claimed")
+	return &Grant{Scope: invite.Scope(), InviteID: invite.ID}, nil
+}
+
+// ReleaseInvite makes a claimed invite usable again. Failures are only logged.
+func (s *service) ReleaseInvite(ctx context.Context, inviteID string) {
+	if inviteID == "" {
+		return
+	}
+	if err := s.repository.Release(ctx, inviteID); err != nil {
+		s.logger.WithError(err).WithField("inviteID", inviteID).Error("failed to release invite after failed registration")
+		return
+	}
+	s.logger.WithField("inviteID", inviteID).Info("invite released after failed registration")
+}
+
+// ScopeForEmail returns the scope of the domain rule matching email.
+func (s *service) ScopeForEmail(ctx context.Context, email string) (Scope, bool) {
+	domain := EmailDomain(email)
+
+	t := tenant.FromContext(ctx)
+	if t == nil {
+		scope, ok := s.domains[domain]
+		return scope, ok
+	}
+
+	rules, err := ParseDomainRules(t.RegistrationDomains, s.DefaultScope(ctx))
+	if err != nil {
+		s.logger.WithError(err).WithField("tenantID", t.ID).Error("invalid registration domains of tenant")
+		return Scope{}, false
+	}
+	for _, rule := range rules {
+		if rule.Domain == domain {
+			return rule.Scope, true
+		}
+	}
+	return Scope{}, false
+}
+
+// DefaultScope returns the default company, group and location of the tenant.
+func (s *service) DefaultScope(ctx context.Context) Scope {
+	if t := tenant.FromContext(ctx); t != nil {
+		return Scope{
+			CompanyID:  t.CompanyID,
+			GroupID:    t.GroupID,
+			LocationID: t.LocationID,
+		}
+	}
+	return s.defaultScope
+}
+
+func (s *service) CreateInvite(ctx context.Context, actorEmail string, req CreateInviteRequest) (*CreateInviteResponse, error) {
+	if req.CompanyID < 0 || req.GroupID < 0 || req.LocationID < 0 {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"company_id, group_id and location_id must not be negative",
+			nil,
+		)
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(constants.InviteDefaultExpiration)
+	if req.ExpiresAt != nil {
+		expiresAt = req.ExpiresAt.UTC()
+	}
+	if !expiresAt.After(now) {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"expires_at must be in the future",
+			nil,
+		)
+	}
+	if expiresAt.After(now.Add(constants.InviteMaxExpiration)) {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"expires_at must be within 90 days",
+			nil,
+		)
+	}
+
+	code, err := generateCode()
+	if err != nil {
+		s.logger.WithError(err).Error("failed to generate invite code")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to create invite",
+			err,
+		)
+	}
+
+	scope := Scope{
+		CompanyID:  req.CompanyID,
+		GroupID:    req.GroupID,
+		LocationID: req.LocationID,
+	}.withDefaults(s.DefaultScope(ctx))
+
+	invite := &Invite{
+		TenantID:   tenant.IDFromContext(ctx),
+		CodeHash:   hashCode(code),
+		Email:      strings.ToLower(strings.TrimSpace(req.Email)),
+		CompanyID:  scope.CompanyID,
+		GroupID:    scope.GroupID,
+		LocationID: scope.LocationID,
+		Note:       strings.TrimSpace(req.Note),
+		ExpiresAt:  expiresAt,
+		CreatedBy:  actorEmail,
+	}
+	if err := s.repository.Create(ctx, invite); err != nil {
+		s.logger.WithError(err).Error("failed to store invite")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to create invite",
+			err,
+		)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"inviteID":   invite.ID,
+		"actorEmail": actorEmail,
+		"companyID":  invite.CompanyID,
+		"groupID":    invite.GroupID,
+		"locationID": invite.LocationID,
+	}).Info("invite created")
+
+	return &CreateInviteResponse{
+		InviteResponse: toInviteResponse(invite, now),
+		Code:           code,
+	}, nil
+}
+
+func (s *service) ListInvites(ctx context.Context, page, limit int) (*InviteListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultInvitePageSize
+	}
+	if limit > maxInvitePageSize {
+		limit = maxInvitePageSize
+	}
+
+	invites, total, err := s.repository.List(ctx, tenant.IDFromContext(ctx), limit, (page-1)*limit)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to list invites")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to list invites",
+			err,
+		)
+	}
+
+	now := time.Now().UTC()
+	data := make([]InviteResponse, 0, len(invites))
+	for i := range invites {
+		data = append(data, toInviteResponse(&invites[i], now))
+	}
+
+	return &InviteListResponse{
+		Data:  data,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}, nil
+}
+
+func (s *service) RevokeInvite(ctx context.Context, id, actorEmail string) error {
+	invite, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).WithField("inviteID", id).Error("failed to get invite")
+		return errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to revoke invite",
+			err,
+		)
+	}
+	if invite == nil || invite.TenantID != tenant.IDFromContext(ctx) {
+		return errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"invite not found",
+			nil,
+		)
+	}
+
+	revoked, err := s.repository.Revoke(ctx, id, time.Now().UTC())
+	if err != nil {
+		s.logger.WithError(err).WithField("inviteID", id).Error("failed to revoke invite")
+		return errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to revoke invite",
+			err,
+		)
+	}
+	if !revoked {
+		return errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"invite has already been used or revoked",
+			nil,
+		)
+	}
+
+	s.logger.WithField("inviteID", id).WithField("actorEmail", actorEmail).Info("invite revoked")
+	return nil
+}
@@ -0,0 +1,25 @@
+// Package requestinfo carries details of the HTTP request a context was
+// created for, so services can record them in audit trails.
+package requestinfo
+
+import "context"
+
+// Info describes the request a context belongs to.
+type Info struct {
+	RequestID string
+	IPAddress string
+}
+
+type infoKey struct{}
+
+// WithInfo returns a context carrying the request details.
+func WithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// FromContext returns the request details set by WithInfo. Background jobs
+// have none and get the zero Info.
+func FromContext(ctx context.Context) Info {
+	info, _ := ctx.Value(infoKey{}).(Info)
+	return info
+}
@@ -0,0 +1,53 @@
+package response
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"werk-ticketing/internal/errors"
+)
+
+// WriteCacheable writes a JSON response that clients and proxies may cache for
+// maxAge. The ETag is derived from the body, so a request whose If-None-Match
+// matches it is answered with 304 Not Modified and no body.
+func WriteCacheable(c *gin.Context, status int, payload interface{}, maxAge time.Duration) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, "failed to encode response")
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge.Seconds())))
+	// Responses differ per tenant host and language
+	c.Header("Vary", "Host, Accept-Language")
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(status, "application/json; charset=utf-8", body)
+}
+
+// etagMatches reports whether an If-None-Match header lists etag. Weak
+// validators match as well, as If-None-Match uses weak comparison.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -59,6 +59,14 @@ func AppError(c *gin.Context, appErr *errors.AppError) {
 	case errors.ErrCodeEmailAlreadyExist:
 		// Email already exists should return 409 Conflict (not 500)
 		status = http.StatusConflict
+	case errors.ErrCodeEmailNotVerified:
+		status = http.StatusForbidden
+	case errors.ErrCodeAccountLocked:
+		status = http.StatusLocked
+	case errors.ErrCodeTooManyAttempts:
+		status = http.StatusTooManyRequests
+	case errors.ErrCodeAccountDisabled:
+		status = http.StatusForbidden
 	case errors.ErrCodeExternalService:
 		status = http.StatusBadGateway
 	default:
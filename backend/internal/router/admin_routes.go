@@ -0,0 +1,89 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"werk-ticketing/internal/middleware"
+	"werk-ticketing/internal/user"
+)
+
+// setupAdminRoutes configures admin-only routes
+// All admin routes require a valid JWT token with the admin role
+func (r *Router) setupAdminRoutes(api *gin.RouterGroup) {
+	adminRoutes := api.Group("/admin")
+	adminRoutes.Use(
+		middleware.WithAuth(r.authService),
+		middleware.RequireRole(user.RoleAdmin),
+	)
+	{
+		// GET /api/v1/admin/users - List and search local users
+		// Query params: ?q=search&role=requester|agent|admin&status=active|deactivated&page=1&limit=20
+		adminRoutes.GET("/users", r.adminHandler.ListUsers)
+
+		// GET /api/v1/admin/users/:id - Get a user with the state of its InvGate link
+		adminRoutes.GET("/users/:id", r.adminHandler.GetUser)
+
+		// DELETE /api/v1/admin/users/:id - Delete a user account (InvGate user, tokens and personal data)
+		adminRoutes.DELETE("/users/:id", r.privacyHandler.DeleteUserAccount)
+
+		// PUT /api/v1/admin/users/:id/role - Change the role of a local user
+		// Body JSON: { "role": "requester" | "agent" | "admin" }
+		// A changed role logs the user out of all sessions so old tokens lose the old role
+		adminRoutes.PUT("/users/:id/role", r.adminHandler.UpdateUserRole)
+
+		// POST /api/v1/admin/users/:id/unlock - Lift a temporary lock caused by failed logins
+		adminRoutes.POST("/users/:id/unlock", r.adminHandler.UnlockUser)
+
+		// POST /api/v1/admin/users/:id/deactivate - Block login and end all sessions of a user
+		adminRoutes.POST("/users/:id/deactivate", r.adminHandler.DeactivateUser)
+
+		// POST /api/v1/admin/users/:id/reactivate - Allow a deactivated user to log in again
+		adminRoutes.POST("/users/:id/reactivate", r.adminHandler.ReactivateUser)
+
+		// PUT /api/v1/admin/users/:id/invgate - Link the user to another InvGate user
+		// Body JSON: { "invgate_user_id"?: number }, looked up by email when omitted
+		adminRoutes.PUT("/users/:id/invgate", r.adminHandler.RelinkInvGateUser)
+
+		// POST /api/v1/admin/users/:id/scopes - Assign the InvGate user to its registration company, group and location again
+		adminRoutes.POST("/users/:id/scopes", r.adminHandler.AssignDefaultScopes)
+
+		// GET /api/v1/admin/invites - List registration invite codes
+		// Query params: ?page=1&limit=20
+		adminRoutes.GET("/invites", r.adminHandler.ListInvites)
+
+		// POST /api/v1/admin/invites - Issue a single-use registration invite code
+		// Body JSON: { "email"?: string, "company_id"?: number, "group_id"?: number, "location_id"?: number, "note"?: string, "expires_at"?: RFC3339 }
+		// The code is only returned in this response
+		adminRoutes.POST("/invites", r.adminHandler.CreateInvite)
+
+		// DELETE /api/v1/admin/invites/:id - Revoke an unused invite code
+		adminRoutes.DELETE("/invites/:id", r.adminHandler.RevokeInvite)
+
+		// GET /api/v1/admin/categories - All InvGate categories as a tree, each marked with "allowed"
+		adminRoutes.GET("/categories", r.categoryHandler.ListAdminCategories)
+
+		// PUT /api/v1/admin/categories/allowed - Replace the categories users may create tickets in
+		// Body JSON: { "category_ids": number[] }
+		adminRoutes.PUT("/categories/allowed", r.categoryHandler.UpdateAllowed)
+
+		// PUT /api/v1/admin/categories/:id - Set the local icon and description of a category
+		// Body JSON: { "icon"?: string, "description"?: string }, both empty removes the override
+		adminRoutes.PUT("/categories/:id", r.categoryHandler.UpdateOverride)
+
+		// DELETE /api/v1/admin/cache - Purge the cached InvGate reference data of the tenant
+		// (categories, articles, ticket types, priorities and statuses)
+		adminRoutes.DELETE("/cache", r.adminHandler.PurgeCache)
+
+		// GET /api/v1/admin/tenants - List tenants (admins of the default tenant only)
+		adminRoutes.GET("/tenants", r.adminHandler.ListTenants)
+
+		// POST /api/v1/admin/tenants - Add a tenant with its own InvGate connection
+		// Body JSON: { "slug": string, "name": string, "hosts"?: string[], "invgate": { "base_url", "username", "password", "page_key"? },
+		//   "company_id"?: number, "group_id"?: number, "location_id"?: number, "allowed_category_ids"?: number[], "registration_domains"?: string }
+		adminRoutes.POST("/tenants", r.adminHandler.CreateTenant)
+
+		// PATCH /api/v1/admin/tenants/:id - Update a tenant, omitted fields are kept
+		// The default tenant is configured through environment variables and cannot be changed here
+		adminRoutes.PATCH("/tenants/:id", r.adminHandler.UpdateTenant)
+	}
+}
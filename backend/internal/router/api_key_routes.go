@@ -0,0 +1,30 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"werk-ticketing/internal/middleware"
+)
+
+// setupAPIKeyRoutes configures personal API key management routes
+// Keys can only be managed with a JWT token, never with another API key
+func (r *Router) setupAPIKeyRoutes(api *gin.RouterGroup) {
+	apiKeyRoutes := api.Group("/api-keys")
+	apiKeyRoutes.Use(middleware.WithAuth(r.authService))
+	{
+		// POST /api/v1/api-keys - Create a personal API key
+		// Body JSON: { "name": string, "scopes": ["tickets:read", "tickets:write"], "expires_at"?: RFC3339 }
+		// The plain key is only returned once in this response
+		apiKeyRoutes.POST("", r.apiKeyHandler.Create)
+
+		// GET /api/v1/api-keys - List the API keys of the current user
+		apiKeyRoutes.GET("", r.apiKeyHandler.List)
+
+		// PATCH /api/v1/api-keys/:id - Rename a key or change its scopes
+		// Body JSON: { "name"?: string, "scopes"?: [string] }
+		apiKeyRoutes.PATCH("/:id", r.apiKeyHandler.Update)
+
+		// DELETE /api/v1/api-keys/:id - Revoke an API key
+		apiKeyRoutes.DELETE("/:id", r.apiKeyHandler.Delete)
+	}
+}
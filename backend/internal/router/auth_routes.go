@@ -1,6 +1,10 @@
 package router
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gin-gonic/gin"
+
+	"werk-ticketing/internal/middleware"
+)
 
 // setupAuthRoutes configures authentication routes
 func (r *Router) setupAuthRoutes(api *gin.RouterGroup) {
@@ -10,6 +14,28 @@ func (r *Router) setupAuthRoutes(api *gin.RouterGroup) {
 		authRoutes.POST("/login", r.authHandler.Login)
 		authRoutes.POST("/refresh", r.authHandler.RefreshToken)
 		authRoutes.POST("/revoke", r.authHandler.RevokeToken)
+		authRoutes.POST("/password/forgot", r.authHandler.ForgotPassword)
+		authRoutes.POST("/password/reset", r.authHandler.ResetPassword)
+		authRoutes.POST("/verify", r.authHandler.VerifyEmail)
+		authRoutes.POST("/verify/resend", r.authHandler.ResendVerification)
+
+		// Active sessions of the current user (bearer access token required).
+		// Revoking a session invalidates both its access and refresh tokens.
+		authRoutes.GET("/sessions", middleware.WithAuth(r.authService), r.authHandler.ListSessions)
+		authRoutes.DELETE("/sessions/:id", middleware.WithAuth(r.authService), r.authHandler.RevokeSession)
+		authRoutes.POST("/logout-all", middleware.WithAuth(r.authService), r.authHandler.LogoutAll)
+
+		// Two-factor authentication. Enroll and confirm require a bearer access token,
+		// verify completes a login with the mfa_token returned by /login.
+		authRoutes.POST("/mfa/enroll", middleware.WithAuth(r.authService), r.authHandler.EnrollMFA)
+		authRoutes.POST("/mfa/confirm", middleware.WithAuth(r.authService), r.authHandler.ConfirmMFA)
+		authRoutes.POST("/mfa/verify", r.authHandler.VerifyMFA)
+
+		// OpenID Connect single sign-on (authorization code flow with PKCE).
+		// login sets an HttpOnly state cookie; callback only accepts the
+		// state of the browser holding it.
+		authRoutes.GET("/oidc/login", r.authHandler.StartOIDCLogin)
+		authRoutes.GET("/oidc/callback", r.authHandler.OIDCCallback)
 	}
 }
 
@@ -0,0 +1,40 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"werk-ticketing/internal/middleware"
+)
+
+// setupMeRoutes configures the profile routes of the logged-in user
+// Profile changes are propagated to the linked InvGate user
+func (r *Router) setupMeRoutes(api *gin.RouterGroup) {
+	meRoutes := api.Group("/me")
+	{
+		// GET /api/v1/me - Get the profile of the current user
+		meRoutes.GET("", middleware.WithAuth(r.authService), r.profileHandler.Get)
+
+		// PATCH /api/v1/me - Update the profile of the current user
+		// Body JSON: { "name"?: string, "last_name"?: string, "phone"?: string, "preferred_language"?: "id" | "en" }
+		meRoutes.PATCH("", middleware.WithAuth(r.authService), r.profileHandler.Update)
+
+		// PUT /api/v1/me/password - Change the password using the current one
+		// Body JSON: { "current_password": string, "new_password": string }
+		// Other sessions of the user are logged out, the current one stays active
+		meRoutes.PUT("/password", middleware.WithAuth(r.authService), r.authHandler.ChangePassword)
+
+		// DELETE /api/v1/me - Delete the account of the current user
+		// Body JSON: { "password": string }
+		// The InvGate user is deleted (or disabled), all tokens are revoked and local data is anonymized
+		meRoutes.DELETE("", middleware.WithAuth(r.authService), r.privacyHandler.DeleteAccount)
+
+		// POST /api/v1/me/exports - Request an export of all personal data (built in the background)
+		meRoutes.POST("/exports", middleware.WithAuth(r.authService), r.privacyHandler.RequestExport)
+
+		// GET /api/v1/me/exports/:id - Get the state of a data export
+		meRoutes.GET("/exports/:id", middleware.WithAuth(r.authService), r.privacyHandler.GetExport)
+
+		// GET /api/v1/me/exports/:id/download - Download a completed data export as ZIP
+		meRoutes.GET("/exports/:id/download", middleware.WithAuth(r.authService), r.privacyHandler.DownloadExport)
+	}
+}
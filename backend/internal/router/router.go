@@ -4,32 +4,60 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 
+	"werk-ticketing/internal/admin"
+	"werk-ticketing/internal/apikey"
 	"werk-ticketing/internal/auth"
+	"werk-ticketing/internal/category"
 	"werk-ticketing/internal/constants"
 	"werk-ticketing/internal/middleware"
+	"werk-ticketing/internal/privacy"
+	"werk-ticketing/internal/profile"
+	"werk-ticketing/internal/tenant"
 	"werk-ticketing/internal/ticket"
+	"werk-ticketing/internal/user"
 )
 
 // Router holds all route dependencies
 type Router struct {
-	authHandler   *auth.Handler
-	ticketHandler *ticket.Handler
-	authService   auth.Service
-	logger        *logrus.Logger
+	authHandler     *auth.Handler
+	adminHandler    *admin.Handler
+	apiKeyHandler   *apikey.Handler
+	ticketHandler   *ticket.Handler
+	categoryHandler *category.Handler
+	profileHandler  *profile.Handler
+	privacyHandler  *privacy.Handler
+	authService     auth.Service
+	apiKeyService   apikey.Service
+	tenantService   tenant.Service
+	logger          *logrus.Logger
 }
 
 // NewRouter creates a new router instance
 func NewRouter(
 	authHandler *auth.Handler,
+	adminHandler *admin.Handler,
+	apiKeyHandler *apikey.Handler,
 	ticketHandler *ticket.Handler,
+	categoryHandler *category.Handler,
+	profileHandler *profile.Handler,
+	privacyHandler *privacy.Handler,
 	authService auth.Service,
+	apiKeyService apikey.Service,
+	tenantService tenant.Service,
 	logger *logrus.Logger,
 ) *Router {
 	return &Router{
-		authHandler:   authHandler,
-		ticketHandler: ticketHandler,
-		authService:   authService,
-		logger:        logger,
+		authHandler:     authHandler,
+		adminHandler:    adminHandler,
+		apiKeyHandler:   apiKeyHandler,
+		ticketHandler:   ticketHandler,
+		categoryHandler: categoryHandler,
+		profileHandler:  profileHandler,
+		privacyHandler:  privacyHandler,
+		authService:     authService,
+		apiKeyService:   apiKeyService,
+		tenantService:   tenantService,
+		logger:          logger,
 	}
 }
 
@@ -39,11 +67,14 @@ func (r *Router) SetupRoutes() *gin.Engine {
 
 	// Global middleware (order matters!)
 	router.Use(
+		middleware.RequestInfo(),
 		gin.Logger(),
 		middleware.Recover(r.logger),
 		middleware.CORS(),
 		middleware.SecurityHeaders(),
 		middleware.RateLimit(),
+		middleware.ResolveTenant(r.tenantService, r.logger),
+		middleware.Language(),
 	)
 
 	// Set max request size
@@ -55,24 +86,35 @@ func (r *Router) SetupRoutes() *gin.Engine {
 	// Setup route groups
 	r.setupAuthRoutes(apiV1)
 	r.setupTicketRoutes(apiV1)
+	r.setupAdminRoutes(apiV1)
+	r.setupAPIKeyRoutes(apiV1)
+	r.setupMeRoutes(apiV1)
 
-	// User endpoint (proxy to InvGate user API, requires auth)
+	// User endpoint (proxy to InvGate user API, requires auth as agent or admin)
 	userRoutes := apiV1.Group("/users")
-	userRoutes.Use(middleware.WithAuth(r.authService))
+	userRoutes.Use(
+		middleware.WithAuth(r.authService),
+		middleware.RequireRole(user.RoleAgent, user.RoleAdmin),
+	)
 	{
 		// GET /api/users/:id - Get InvGate user detail by ID
 		userRoutes.GET("/:id", r.ticketHandler.GetInvGateUser)
 	}
 
 	// Categories endpoint (public, no auth required for reference data)
-	apiV1.GET("/categories", r.ticketHandler.GetCategories)
+	// Returns the allowed categories of the tenant as a tree: [{ id, name, icon?, description?, children: [...] }]
+	apiV1.GET("/categories", r.categoryHandler.GetCategories)
 	// Ticket meta endpoint (public, no auth required for reference data)
+	// Types, priorities and statuses are synced from InvGate; names follow ?lang=id|en or Accept-Language
 	apiV1.GET("/ticket-meta", r.ticketHandler.GetMeta)
 	// Statuses endpoint (public, no auth required for reference data)
 	apiV1.GET("/statuses", r.ticketHandler.GetStatuses)
 	// Articles endpoint (public, no auth required for reference data)
 	apiV1.GET("/articles", r.ticketHandler.GetArticlesByCategory)
 
+	// Public keys to verify tokens issued by this service (no versioning)
+	router.GET("/.well-known/jwks.json", r.authHandler.JWKS)
+
 	// Health check endpoint (no versioning)
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -3,53 +3,70 @@ package router
 import (
 	"github.com/gin-gonic/gin"
 
+	"werk-ticketing/internal/apikey"
 	"werk-ticketing/internal/middleware"
 )
 
 // setupTicketRoutes configures ticket routes
-// All ticket routes require authentication via JWT token
+// All ticket routes require authentication via JWT token or a personal API key
+// API keys additionally need the tickets:read or tickets:write scope
 func (r *Router) setupTicketRoutes(api *gin.RouterGroup) {
 	ticketRoutes := api.Group("/tickets")
-	ticketRoutes.Use(middleware.WithAuth(r.authService))
+	ticketRoutes.Use(middleware.WithAuthOrAPIKey(r.authService, r.apiKeyService))
+	read := middleware.RequireScope(apikey.ScopeTicketsRead)
+	write := middleware.RequireScope(apikey.ScopeTicketsWrite)
 	{
 		// POST /api/tickets - Create a new ticket
 		// Creates a ticket in InvGate Armmada and saves it to local database
-		ticketRoutes.POST("", r.ticketHandler.Create)
+		ticketRoutes.POST("", write, r.ticketHandler.Create)
 
 		// GET /api/tickets - List all tickets
-		// Returns paginated list of tickets, filtered by creator_id (optional)
+		// Returns paginated list of tickets from the local read model, filtered by creator_id (optional)
+		// Listing tickets of another user requires the agent or admin role
 		// Query params: ?creator_id=email&page=1&limit=10
-		ticketRoutes.GET("", r.ticketHandler.List)
+		// Filters: status_id, category_id, type_id, priority_id (comma separated IDs),
+		// created_from, created_to, updated_from, updated_to (YYYY-MM-DD or RFC3339, to is exclusive)
+		// Search: q (full-text over title and description)
+		// Sorting: sort=created_at|updated_at|status|category|type|priority|relevance, order=asc|desc
+		// Cursor pagination: pass pagination.next_cursor/prev_cursor as ?cursor= instead of page
+		// (only with sort=created_at); cursor pages skip the total count
+		ticketRoutes.GET("", read, r.ticketHandler.List)
 
 		// GET /api/tickets/:id - Get ticket detail by ID
 		// Returns detailed information about a specific ticket
 		// Path param: id (InvGate ticket ID)
-		ticketRoutes.GET("/:id", r.ticketHandler.GetByID)
+		ticketRoutes.GET("/:id", read, r.ticketHandler.GetByID)
 
 		// PUT /api/tickets/:id - Update an existing ticket
 		// Updates ticket fields in InvGate Armmada
 		// Path param: id (InvGate ticket ID)
 		// Body JSON: { "title"?: string, "description"?: string, "category_id"?: number, ... }
 		// All fields are optional - only provided fields will be updated
-		ticketRoutes.PUT("/:id", r.ticketHandler.Update)
+		ticketRoutes.PUT("/:id", write, r.ticketHandler.Update)
+
+		// GET /api/tickets/:id/history - Timeline of changes made through this service
+		// Events: created, updated (with changed fields), commented, solution_accepted, solution_rejected
+		// Each event carries the actor email, time and request ID; the client IP only for agents and admins
+		ticketRoutes.GET("/:id/history", read, r.ticketHandler.GetHistory)
 
 		// GET /api/tickets/:id/comments - Get comments for a ticket
-		ticketRoutes.GET("/:id/comments", r.ticketHandler.GetComments)
+		// Query params: ?limit=20&cursor= (optional, at most 100 per page; all comments are returned without them)
+		ticketRoutes.GET("/:id/comments", read, r.ticketHandler.GetComments)
 
 		// POST /api/tickets/:id/comments - Add comment to ticket
-		ticketRoutes.POST("/:id/comments", r.ticketHandler.AddComment)
+		ticketRoutes.POST("/:id/comments", write, r.ticketHandler.AddComment)
 
 		// GET /api/tickets/attachments/:attachment_id - Download attachment file
-		ticketRoutes.GET("/attachments/:attachment_id", r.ticketHandler.GetAttachment)
+		ticketRoutes.GET("/attachments/:attachment_id", read, r.ticketHandler.GetAttachment)
 
 		// PUT /api/tickets/:id/solution - Accept/solve ticket with rating and comment
 		// This will call InvGate endpoint: incident.solution.accept
 		// Body JSON: { "comment": string, "rating": 1-5 }
-		ticketRoutes.PUT("/:id/solution", r.ticketHandler.AcceptSolution)
+		ticketRoutes.PUT("/:id/solution", write, r.ticketHandler.AcceptSolution)
 
 		// PUT /api/tickets/:id/solution/reject - Reject ticket solution with comment
 		// This will call InvGate endpoint: incident.solution.reject
 		// Body JSON: { "comment": string }
-		ticketRoutes.PUT("/:id/solution/reject", r.ticketHandler.RejectSolution)
+		ticketRoutes.PUT("/:id/solution/reject", write, r.ticketHandler.RejectSolution)
 	}
 }
@@ -0,0 +1,88 @@
+// Package secretbox encrypts credentials that must be stored in the database
+// but read back in plain text, such as the InvGate passwords of tenants.
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Prefix marks sealed values so plain text stored before encryption was
+// introduced can be told apart.
+const Prefix = "v1:"
+
+// KeySize is the length of the AES-256 key.
+const KeySize = 32
+
+// ErrMalformed is returned by Open for sealed values that cannot be decoded
+// or were not sealed with the same key.
+var ErrMalformed = errors.New("secretbox: malformed or tampered value")
+
+// Box seals and opens values with AES-256-GCM.
+type Box struct {
+	aead cipher.AEAD
+}
+
+// New builds a Box from a 32 byte key.
+func New(key []byte) (*Box, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("secretbox: key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Box{aead: aead}, nil
+}
+
+// NewFromBase64 builds a Box from a base64 encoded key, as generated by
+// `openssl rand -base64 32`.
+func NewFromBase64(encoded string) (*Box, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("secretbox: key is not valid base64: %w", err)
+	}
+	return New(key)
+}
+
+// IsSealed reports whether value was produced by Seal.
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// Seal encrypts plain with a random nonce. Sealing the same value twice gives
+// different results.
+func (b *Box) Seal(plain string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plain), nil)
+	return Prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value produced by Seal.
+func (b *Box) Open(value string) (string, error) {
+	if !IsSealed(value) {
+		return "", ErrMalformed
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, Prefix))
+	if err != nil || len(sealed) < b.aead.NonceSize() {
+		return "", ErrMalformed
+	}
+	nonce, ciphertext := sealed[:b.aead.NonceSize()], sealed[b.aead.NonceSize():]
+	plain, err := b.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrMalformed
+	}
+	return string(plain), nil
+}
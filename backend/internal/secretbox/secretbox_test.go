@@ -0,0 +1,45 @@
+package secretbox
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	box, err := NewFromBase64(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, KeySize)))
+	if err != nil {
+		t.Fatalf("NewFromBase64: %v", err)
+	}
+
+	first, err := box.Seal("j8f2yDzu")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	second, _ := box.Seal("j8f2yDzu")
+	if first == second || !IsSealed(first) {
+		t.Fatalf("sealed values %q and %q, want distinct sealed values", first, second)
+	}
+
+	plain, err := box.Open(first)
+	if err != nil || plain != "j8f2yDzu" {
+		t.Fatalf("Open = %q, %v", plain, err)
+	}
+
+	other, _ := New(bytes.Repeat([]byte{8}, KeySize))
+	if _, err := other.Open(first); err != ErrMalformed {
+		t.Fatalf("Open with another key = %v, want ErrMalformed", err)
+	}
+	if _, err := box.Open("j8f2yDzu"); err != ErrMalformed {
+		t.Fatalf("Open of plain text = %v, want ErrMalformed", err)
+	}
+}
+
+func TestNewRejectsBadKeys(t *testing.T) {
+	if _, err := New([]byte("short")); err == nil {
+		t.Fatal("short key accepted")
+	}
+	if _, err := NewFromBase64("not base64!"); err == nil {
+		t.Fatal("invalid base64 accepted")
+	}
+}
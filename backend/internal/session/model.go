@@ -0,0 +1,46 @@
+package session
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"werk-ticketing/internal/database"
+)
+
+// Session represents one logged-in device.
+// Every refresh token belongs to a session; rotating the refresh token keeps the
+// session row and swaps RefreshTokenID, so an old refresh token presented again
+// can be detected as reuse and the whole session revoked.
+type Session struct {
+	ID             string     `gorm:"type:char(36);primaryKey"`     // Session (family) identifier, carried as "sid" in tokens
+	UserID         string     `gorm:"type:char(36);not null;index"` // Local user ID
+	UserEmail      string     `gorm:"size:190;not null;index"`      // Email of the session owner
+	RefreshTokenID string     `gorm:"size:64;not null;uniqueIndex"` // JTI of the currently valid refresh token
+	Device         string     `gorm:"size:255"`                     // User agent of the client
+	IPAddress      string     `gorm:"size:64;column:ip_address"`    // Client IP at the last use
+	ExpiresAt      time.Time  `gorm:"not null;index"`               // Expiry of the current refresh token
+	LastSeenAt     time.Time  `gorm:"not null"`                     // Last login, refresh or authenticated request
+	RevokedAt      *time.Time `gorm:"index"`                        // Set when the session is logged out or revoked
+	RevokedReason  string     `gorm:"size:50"`                      // e.g. logout, reuse_detected
+	CreatedAt      time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// BeforeCreate assigns the session ID when the caller did not set one.
+func (s *Session) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = database.NewUUID()
+	}
+	return nil
+}
+
+// IsActive reports whether the session can still be used to refresh tokens.
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
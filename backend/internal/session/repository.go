@@ -0,0 +1,128 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Revocation reasons stored on sessions.
+const (
+	RevokedReasonLogout         = "logout"
+	RevokedReasonReuseDetected  = "reuse_detected"
+	RevokedReasonPasswordReset  = "password_reset"
+	RevokedReasonPasswordChange = "password_change"
+	RevokedReasonRemoteLogout   = "remote_logout"
+	RevokedReasonLogoutAll      = "logout_all"
+	RevokedReasonDeactivated    = "deactivated"
+	RevokedReasonAccountDeleted = "account_deleted"
+	RevokedReasonRoleChanged    = "role_changed"
+)
+
+// Repository abstracts data persistence for sessions.
+type Repository interface {
+	Create(ctx context.Context, session *Session) error
+	GetByID(ctx context.Context, id string) (*Session, error)
+	ListActiveByUser(ctx context.Context, userID string, now time.Time) ([]Session, error)
+	Rotate(ctx context.Context, id, oldTokenID, newTokenID, ipAddress string, expiresAt time.Time) (bool, error)
+	Touch(ctx context.Context, id, ipAddress string, seenAt, staleBefore time.Time) error
+	Revoke(ctx context.Context, id, reason string) error
+	RevokeAllForUser(ctx context.Context, userID, reason string) error
+	DeleteAllForUser(ctx context.Context, userID string) error
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository builds a Gorm-backed session repository.
+func NewRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+func (r *gormRepository) Create(ctx context.Context, session *Session) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *gormRepository) GetByID(ctx context.Context, id string) (*Session, error) {
+	var s Session
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&s).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListActiveByUser returns the sessions of a user that are neither revoked nor expired,
+// most recently used first.
+func (r *gormRepository) ListActiveByUser(ctx context.Context, userID string, now time.Time) ([]Session, error) {
+	var sessions []Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Rotate swaps the refresh token of an active session.
+// The update only succeeds while oldTokenID is still the current token, so two
+// concurrent refreshes with the same token cannot both win. It returns false
+// when nothing was updated.
+func (r *gormRepository) Rotate(ctx context.Context, id, oldTokenID, newTokenID, ipAddress string, expiresAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&Session{}).
+		Where("id = ? AND refresh_token_id = ? AND revoked_at IS NULL", id, oldTokenID).
+		Updates(map[string]interface{}{
+			"refresh_token_id": newTokenID,
+			"ip_address":       ipAddress,
+			"expires_at":       expiresAt,
+			"last_seen_at":     time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Touch records activity on an active session. Only sessions last seen before
+// staleBefore are written, which keeps it to one update per interval. An
+// empty ipAddress keeps the stored one.
+func (r *gormRepository) Touch(ctx context.Context, id, ipAddress string, seenAt, staleBefore time.Time) error {
+	updates := map[string]interface{}{"last_seen_at": seenAt}
+	if ipAddress != "" {
+		updates["ip_address"] = ipAddress
+	}
+	return r.db.WithContext(ctx).Model(&Session{}).
+		Where("id = ? AND revoked_at IS NULL AND last_seen_at < ?", id, staleBefore).
+		Updates(updates).Error
+}
+
+// Revoke marks a session as revoked. Already revoked sessions keep their original reason.
+func (r *gormRepository) Revoke(ctx context.Context, id, reason string) error {
+	return r.db.WithContext(ctx).Model(&Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now().UTC(),
+			"revoked_reason": reason,
+		}).Error
+}
+
+// RevokeAllForUser revokes every active session of a user.
+func (r *gormRepository) RevokeAllForUser(ctx context.Context, userID, reason string) error {
+	return r.db.WithContext(ctx).Model(&Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now().UTC(),
+			"revoked_reason": reason,
+		}).Error
+}
+
+// DeleteAllForUser removes every session row of a user, revoked or not.
+// Callers revoke the sessions first so outstanding tokens stop working.
+func (r *gormRepository) DeleteAllForUser(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Delete(&Session{}, "user_id = ?", userID).Error
+}
@@ -0,0 +1,65 @@
+package tenant
+
+import (
+	"context"
+
+	"werk-ticketing/internal/invgate"
+)
+
+type tenantKey struct{}
+
+type resolverKey struct{}
+
+// Resolver loads tenants by ID.
+type Resolver interface {
+	Get(ctx context.Context, id string) (*Tenant, error)
+}
+
+// NewContext returns a context bound to t. InvGate requests made with it use
+// the connection of the tenant.
+func NewContext(ctx context.Context, t *Tenant) context.Context {
+	ctx = context.WithValue(ctx, tenantKey{}, t)
+	return invgate.WithConnection(ctx, t.Connection())
+}
+
+// FromContext returns the tenant of the request, or nil outside of one.
+func FromContext(ctx context.Context) *Tenant {
+	t, _ := ctx.Value(tenantKey{}).(*Tenant)
+	return t
+}
+
+// IDFromContext returns the ID of the tenant of the request, or an empty string.
+func IDFromContext(ctx context.Context) string {
+	if t := FromContext(ctx); t != nil {
+		return t.ID
+	}
+	return ""
+}
+
+// WithResolver stores the resolver Switch uses on ctx.
+func WithResolver(ctx context.Context, resolver Resolver) context.Context {
+	return context.WithValue(ctx, resolverKey{}, resolver)
+}
+
+// Switch rebinds ctx to the tenant with the given ID, e.g. to the tenant of
+// the authenticated user instead of the one selected by the request host.
+// ctx is returned unchanged when tenantID is empty, already selected, or no
+// resolver is available.
+func Switch(ctx context.Context, tenantID string) (context.Context, error) {
+	if tenantID == "" || tenantID == IDFromContext(ctx) {
+		return ctx, nil
+	}
+	resolver, ok := ctx.Value(resolverKey{}).(Resolver)
+	if !ok {
+		return ctx, nil
+	}
+
+	t, err := resolver.Get(ctx, tenantID)
+	if err != nil {
+		return ctx, err
+	}
+	if t == nil {
+		return ctx, ErrUnknownTenant
+	}
+	return NewContext(ctx, t), nil
+}
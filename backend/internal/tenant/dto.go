@@ -0,0 +1,91 @@
+package tenant
+
+import "time"
+
+// ConnectionRequest holds the InvGate connection of a tenant.
+type ConnectionRequest struct {
+	BaseURL  string `json:"base_url"`
+	Username string `json:"username"`
+	Password string `json:"password"` // Left empty on update to keep the stored password
+	PageKey  string `json:"page_key"`
+}
+
+// CreateRequest incoming body for creating a tenant.
+type CreateRequest struct {
+	Slug                string            `json:"slug" binding:"required" validate:"required"`
+	Name                string            `json:"name" binding:"required,max=100" validate:"required,max=100"`
+	Hosts               []string          `json:"hosts"`
+	InvGate             ConnectionRequest `json:"invgate"`
+	CompanyID           int               `json:"company_id"`
+	GroupID             int               `json:"group_id"`
+	LocationID          int               `json:"location_id"`
+	AllowedCategoryIDs  []int             `json:"allowed_category_ids"`
+	RegistrationDomains string            `json:"registration_domains"`
+}
+
+// UpdateRequest incoming body for updating a tenant. Omitted fields are kept.
+type UpdateRequest struct {
+	Name                *string            `json:"name"`
+	Hosts               []string           `json:"hosts"`
+	InvGate             *ConnectionRequest `json:"invgate"`
+	CompanyID           *int               `json:"company_id"`
+	GroupID             *int               `json:"group_id"`
+	LocationID          *int               `json:"location_id"`
+	AllowedCategoryIDs  []int              `json:"allowed_category_ids"`
+	RegistrationDomains *string            `json:"registration_domains"`
+}
+
+// ConnectionResponse describes the InvGate connection without the password.
+type ConnectionResponse struct {
+	BaseURL  string `json:"base_url"`
+	Username string `json:"username"`
+	PageKey  string `json:"page_key,omitempty"`
+}
+
+// Response is the admin view of a tenant.
+type Response struct {
+	ID                  string             `json:"id"`
+	Slug                string             `json:"slug"`
+	Name                string             `json:"name"`
+	Hosts               []string           `json:"hosts"`
+	IsDefault           bool               `json:"is_default"`
+	InvGate             ConnectionResponse `json:"invgate"`
+	CompanyID           int                `json:"company_id"`
+	GroupID             int                `json:"group_id"`
+	LocationID          int                `json:"location_id"`
+	AllowedCategoryIDs  []int              `json:"allowed_category_ids"`
+	RegistrationDomains string             `json:"registration_domains"`
+	CreatedAt           time.Time          `json:"created_at"`
+	UpdatedAt           time.Time          `json:"updated_at"`
+}
+
+func toResponse(t *Tenant) *Response {
+	hosts := t.HostList()
+	if hosts == nil {
+		hosts = []string{}
+	}
+	categories := t.AllowedCategoryIDs()
+	if categories == nil {
+		categories = []int{}
+	}
+
+	return &Response{
+		ID:        t.ID,
+		Slug:      t.Slug,
+		Name:      t.Name,
+		Hosts:     hosts,
+		IsDefault: t.IsDefault,
+		InvGate: ConnectionResponse{
+			BaseURL:  t.InvGateBaseURL,
+			Username: t.InvGateUsername,
+			PageKey:  t.InvGatePageKey,
+		},
+		CompanyID:           t.CompanyID,
+		GroupID:             t.GroupID,
+		LocationID:          t.LocationID,
+		AllowedCategoryIDs:  categories,
+		RegistrationDomains: t.RegistrationDomains,
+		CreatedAt:           t.CreatedAt,
+		UpdatedAt:           t.UpdatedAt,
+	}
+}
@@ -0,0 +1,125 @@
+package tenant
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"werk-ticketing/internal/database"
+	"werk-ticketing/internal/invgate"
+)
+
+// Tenant is a client company served by this deployment. Each tenant has its
+// own InvGate instance or credentials, its default company/group/location for
+// new users and the ticket categories its users may see.
+type Tenant struct {
+	ID                  string    `gorm:"type:char(36);primaryKey"`
+	Slug                string    `gorm:"size:50;not null;uniqueIndex"`
+	Name                string    `gorm:"size:100;not null"`
+	Hosts               string    `gorm:"size:500"`                     // Comma separated request hosts that select this tenant
+	IsDefault           bool      `gorm:"not null;default:false;index"` // Used for unknown hosts, mirrors the ARMMADA_* settings
+	InvGateBaseURL      string    `gorm:"size:255;not null;column:invgate_base_url"`
+	InvGateUsername     string    `gorm:"size:190;not null;column:invgate_username"`
+	InvGatePassword     string    `gorm:"size:512;not null;column:invgate_password"` // Encrypted at rest, never returned by the API
+	InvGatePageKey      string    `gorm:"size:100;column:invgate_page_key"`
+	CompanyID           int       `gorm:"not null;default:0;column:invgate_company_id"`
+	GroupID             int       `gorm:"not null;default:0;column:invgate_group_id"`
+	LocationID          int       `gorm:"not null;default:0;column:invgate_location_id"`
+	AllowedCategories   string    `gorm:"size:1000;column:allowed_category_ids"` // Comma separated InvGate category IDs
+	RegistrationDomains string    `gorm:"size:1000"`                             // See registration.ParseDomainRules
+	CreatedBy           string    `gorm:"size:190;column:created_by"`
+	UpdatedBy           string    `gorm:"size:190;column:updated_by"`
+	CreatedAt           time.Time `gorm:"autoCreateTime"`
+	UpdatedAt           time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (Tenant) TableName() string {
+	return "tenants"
+}
+
+// BeforeCreate assigns the UUID in Go so the ID is known to the caller after insert.
+func (t *Tenant) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = database.NewUUID()
+	}
+	return nil
+}
+
+// Connection returns the InvGate connection of the tenant.
+func (t *Tenant) Connection() invgate.Connection {
+	return invgate.Connection{
+		BaseURL:  t.InvGateBaseURL,
+		Username: t.InvGateUsername,
+		Password: t.InvGatePassword,
+		PageKey:  t.InvGatePageKey,
+	}
+}
+
+// HostList returns the hosts that select the tenant.
+func (t *Tenant) HostList() []string {
+	return splitList(t.Hosts)
+}
+
+// AllowedCategoryIDs returns the InvGate category IDs users of the tenant may see.
+func (t *Tenant) AllowedCategoryIDs() []int {
+	var ids []int
+	for _, item := range splitList(t.AllowedCategories) {
+		if id, err := strconv.Atoi(item); err == nil && id > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// IsCategoryAllowed reports whether users of the tenant may see the category.
+func (t *Tenant) IsCategoryAllowed(categoryID int) bool {
+	for _, id := range t.AllowedCategoryIDs() {
+		if id == categoryID {
+			return true
+		}
+	}
+	return false
+}
+
+// JoinIDs formats IDs for the AllowedCategories column.
+func JoinIDs(ids []int) string {
+	sorted := append([]int(nil), ids...)
+	sort.Ints(sorted)
+
+	parts := make([]string, 0, len(sorted))
+	for i, id := range sorted {
+		if i > 0 && id == sorted[i-1] {
+			continue
+		}
+		parts = append(parts, strconv.Itoa(id))
+	}
+	return strings.Join(parts, ",")
+}
+
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// normalizeHost lower-cases a host and strips the port.
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if strings.HasPrefix(host, "[") {
+		if end := strings.Index(host, "]"); end >= 0 {
+			return host[:end+1]
+		}
+	}
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.Contains(host[:i], ":") {
+		host = host[:i]
+	}
+	return strings.TrimSuffix(host, ".")
+}
@@ -0,0 +1,144 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"werk-ticketing/internal/secretbox"
+)
+
+// Repository abstracts data persistence for tenants. InvGate passwords are
+// encrypted at rest; tenants passed in and returned hold them in plain text.
+type Repository interface {
+	Create(ctx context.Context, t *Tenant) error
+	Update(ctx context.Context, t *Tenant) error
+	GetDefault(ctx context.Context) (*Tenant, error)
+	List(ctx context.Context) ([]Tenant, error)
+	AssignOrphans(ctx context.Context, tenantID, table string) (int64, error)
+	SealPasswords(ctx context.Context) (int64, error)
+}
+
+type gormRepository struct {
+	db  *gorm.DB
+	box *secretbox.Box
+}
+
+// NewRepository builds a Gorm-backed tenant repository encrypting InvGate
+// passwords with box.
+func NewRepository(db *gorm.DB, box *secretbox.Box) Repository {
+	return &gormRepository{db: db, box: box}
+}
+
+func (r *gormRepository) Create(ctx context.Context, t *Tenant) error {
+	return r.save(t, func(row *Tenant) error {
+		return r.db.WithContext(ctx).Create(row).Error
+	})
+}
+
+func (r *gormRepository) Update(ctx context.Context, t *Tenant) error {
+	return r.save(t, func(row *Tenant) error {
+		return r.db.WithContext(ctx).Save(row).Error
+	})
+}
+
+// save writes a copy of t with the password sealed and copies the fields
+// filled in by the database back to t.
+func (r *gormRepository) save(t *Tenant, write func(row *Tenant) error) error {
+	row := *t
+	sealed, err := r.box.Seal(t.InvGatePassword)
+	if err != nil {
+		return err
+	}
+	row.InvGatePassword = sealed
+	if err := write(&row); err != nil {
+		return err
+	}
+	row.InvGatePassword = t.InvGatePassword
+	*t = row
+	return nil
+}
+
+func (r *gormRepository) GetDefault(ctx context.Context) (*Tenant, error) {
+	var t Tenant
+	err := r.db.WithContext(ctx).Where("is_default = ?", true).First(&t).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := r.open(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// List returns all tenants ordered by slug.
+func (r *gormRepository) List(ctx context.Context) ([]Tenant, error) {
+	var tenants []Tenant
+	if err := r.db.WithContext(ctx).Order("slug ASC").Find(&tenants).Error; err != nil {
+		return nil, err
+	}
+	for i := range tenants {
+		if err := r.open(&tenants[i]); err != nil {
+			return nil, err
+		}
+	}
+	return tenants, nil
+}
+
+// open decrypts the password of a loaded tenant. Passwords stored before
+// encryption was introduced are still plain text until SealPasswords ran.
+func (r *gormRepository) open(t *Tenant) error {
+	if !secretbox.IsSealed(t.InvGatePassword) {
+		return nil
+	}
+	plain, err := r.box.Open(t.InvGatePassword)
+	if err != nil {
+		return fmt.Errorf("tenant %s: invgate password: %w", t.Slug, err)
+	}
+	t.InvGatePassword = plain
+	return nil
+}
+
+// SealPasswords encrypts InvGate passwords still stored in plain text.
+func (r *gormRepository) SealPasswords(ctx context.Context) (int64, error) {
+	var tenants []Tenant
+	err := r.db.WithContext(ctx).
+		Select("id", "invgate_password").
+		Where("invgate_password NOT LIKE ?", secretbox.Prefix+"%").
+		Find(&tenants).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var sealedCount int64
+	for _, t := range tenants {
+		sealed, err := r.box.Seal(t.InvGatePassword)
+		if err != nil {
+			return sealedCount, err
+		}
+		result := r.db.WithContext(ctx).
+			Model(&Tenant{}).
+			Where("id = ? AND invgate_password = ?", t.ID, t.InvGatePassword).
+			UpdateColumn("invgate_password", sealed)
+		if result.Error != nil {
+			return sealedCount, result.Error
+		}
+		sealedCount += result.RowsAffected
+	}
+	return sealedCount, nil
+}
+
+// AssignOrphans moves rows of table that belong to no tenant, e.g. rows
+// created before tenants existed, to tenantID.
+func (r *gormRepository) AssignOrphans(ctx context.Context, tenantID, table string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Table(table).
+		Where("tenant_id = ?", "").
+		Update("tenant_id", tenantID)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,508 @@
+package tenant
+
+import (
+	"context"
+	stdErrors "errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/constants"
+	"werk-ticketing/internal/errors"
+)
+
+// ErrUnknownTenant is returned by Switch for IDs that do not exist.
+var ErrUnknownTenant = stdErrors.New("tenant: unknown tenant")
+
+// DefaultSlug is the slug of the tenant created from the configuration.
+const DefaultSlug = "default"
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,49}$`)
+
+// Service resolves and manages tenants. Tenants returned by it are shared
+// between requests and must not be modified.
+type Service interface {
+	Resolver
+	ForHost(ctx context.Context, host string) (*Tenant, error)
+	WithTenant(ctx context.Context, tenantID string) (context.Context, error)
+	EnsureDefault(ctx context.Context, seed Tenant) (*Tenant, error)
+	AssignOrphans(ctx context.Context, tenantID string, tables ...string) error
+	SealPasswords(ctx context.Context) error
+
+	List(ctx context.Context) ([]*Response, error)
+	Create(ctx context.Context, actorEmail string, req CreateRequest) (*Response, error)
+	Update(ctx context.Context, id, actorEmail string, req UpdateRequest) (*Response, error)
+	SetAllowedCategories(ctx context.Context, id, actorEmail string, categoryIDs []int) (*Tenant, error)
+}
+
+// snapshot is the in-memory copy of the tenants table.
+type snapshot struct {
+	byID     map[string]*Tenant
+	byHost   map[string]*Tenant
+	fallback *Tenant
+	loadedAt time.Time
+}
+
+type service struct {
+	repository Repository
+	logger     *logrus.Logger
+
+	mu    sync.Mutex
+	cache *snapshot
+}
+
+// NewService instantiates tenant service.
+func NewService(repository Repository, logger *logrus.Logger) Service {
+	return &service{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+// Get returns the tenant with the given ID, or nil if it does not exist.
+func (s *service) Get(ctx context.Context, id string) (*Tenant, error) {
+	snap, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return snap.byID[id], nil
+}
+
+// ForHost returns the tenant serving a request host, falling back to the
+// default tenant for unknown hosts.
+func (s *service) ForHost(ctx context.Context, host string) (*Tenant, error) {
+	snap, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := snap.byHost[normalizeHost(host)]; ok {
+		return t, nil
+	}
+	if snap.fallback == nil {
+		return nil, ErrUnknownTenant
+	}
+	return snap.fallback, nil
+}
+
+// WithTenant binds ctx to a tenant outside of a request, e.g. in background jobs.
+// An empty ID selects the default tenant.
+func (s *service) WithTenant(ctx context.Context, tenantID string) (context.Context, error) {
+	snap, err := s.snapshot(ctx)
+	if err != nil {
+		return ctx, err
+	}
+
+	t := snap.fallback
+	if tenantID != "" {
+		t = snap.byID[tenantID]
+	}
+	if t == nil {
+		return ctx, ErrUnknownTenant
+	}
+	return WithResolver(NewContext(ctx, t), s), nil
+}
+
+// EnsureDefault creates the default tenant from seed, or updates it so it
+// keeps matching the configuration the seed was built from. The allowed
+// categories are only taken from seed on creation, afterwards admins manage
+// them at runtime.
+func (s *service) EnsureDefault(ctx context.Context, seed Tenant) (*Tenant, error) {
+	defer s.invalidate()
+
+	existing, err := s.repository.GetDefault(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing == nil {
+		seed.IsDefault = true
+		if seed.Slug == "" {
+			seed.Slug = DefaultSlug
+		}
+		if err := s.repository.Create(ctx, &seed); err != nil {
+			return nil, err
+		}
+		s.logger.WithField("tenantID", seed.ID).Info("default tenant created")
+		return &seed, nil
+	}
+
+	existing.Name = seed.Name
+	existing.InvGateBaseURL = seed.InvGateBaseURL
+	existing.InvGateUsername = seed.InvGateUsername
+	existing.InvGatePassword = seed.InvGatePassword
+	existing.InvGatePageKey = seed.InvGatePageKey
+	existing.CompanyID = seed.CompanyID
+	existing.GroupID = seed.GroupID
+	existing.LocationID = seed.LocationID
+	existing.RegistrationDomains = seed.RegistrationDomains
+	existing.Hosts = seed.Hosts
+	if err := s.repository.Update(ctx, existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// AssignOrphans moves rows without a tenant in the given tables to tenantID.
+func (s *service) AssignOrphans(ctx context.Context, tenantID string, tables ...string) error {
+	for _, table := range tables {
+		n, err := s.repository.AssignOrphans(ctx, tenantID, table)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			s.logger.WithFields(logrus.Fields{
+				"tenantID": tenantID,
+				"table":    table,
+				"rows":     n,
+			}).Info("assigned rows without tenant")
+		}
+	}
+	return nil
+}
+
+// SealPasswords encrypts InvGate passwords stored before encryption at rest
+// was introduced.
+func (s *service) SealPasswords(ctx context.Context) error {
+	n, err := s.repository.SealPasswords(ctx)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		s.logger.WithField("tenants", n).Info("encrypted stored invgate passwords")
+	}
+	return nil
+}
+
+func (s *service) List(ctx context.Context) ([]*Response, error) {
+	tenants, err := s.repository.List(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to list tenants")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to list tenants",
+			err,
+		)
+	}
+
+	resp := make([]*Response, 0, len(tenants))
+	for i := range tenants {
+		resp = append(resp, toResponse(&tenants[i]))
+	}
+	return resp, nil
+}
+
+func (s *service) Create(ctx context.Context, actorEmail string, req CreateRequest) (*Response, error) {
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !slugPattern.MatchString(slug) {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"slug must be 2 to 50 lowercase letters, digits or dashes",
+			nil,
+		)
+	}
+
+	t := &Tenant{
+		Slug:                slug,
+		Name:                strings.TrimSpace(req.Name),
+		CompanyID:           req.CompanyID,
+		GroupID:             req.GroupID,
+		LocationID:          req.LocationID,
+		AllowedCategories:   JoinIDs(req.AllowedCategoryIDs),
+		RegistrationDomains: strings.TrimSpace(req.RegistrationDomains),
+		CreatedBy:           actorEmail,
+		UpdatedBy:           actorEmail,
+	}
+	if err := applyConnection(t, req.InvGate); err != nil {
+		return nil, err
+	}
+	if err := s.applyHosts(ctx, t, req.Hosts); err != nil {
+		return nil, err
+	}
+	if err := validate(t); err != nil {
+		return nil, err
+	}
+
+	for _, other := range s.listForCheck(ctx) {
+		if other.Slug == t.Slug {
+			return nil, errors.NewAppError(
+				errors.ErrCodeInvalidInput,
+				"slug is already in use",
+				nil,
+			)
+		}
+	}
+
+	if err := s.repository.Create(ctx, t); err != nil {
+		s.logger.WithError(err).WithField("slug", t.Slug).Error("failed to create tenant")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to create tenant",
+			err,
+		)
+	}
+	s.invalidate()
+
+	s.logger.WithField("tenantID", t.ID).WithField("actorEmail", actorEmail).Info("tenant created")
+	return toResponse(t), nil
+}
+
+func (s *service) Update(ctx context.Context, id, actorEmail string, req UpdateRequest) (*Response, error) {
+	current, err := s.Get(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).WithField("tenantID", id).Error("failed to get tenant")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to update tenant",
+			err,
+		)
+	}
+	if current == nil {
+		return nil, errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"tenant not found",
+			nil,
+		)
+	}
+	if current.IsDefault {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"the default tenant is configured through environment variables",
+			nil,
+		)
+	}
+
+	// Work on a copy, cached tenants are shared between requests
+	t := *current
+	if req.Name != nil {
+		t.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.InvGate != nil {
+		conn := *req.InvGate
+		if conn.Password == "" {
+			conn.Password = t.InvGatePassword
+		}
+		if err := applyConnection(&t, conn); err != nil {
+			return nil, err
+		}
+	}
+	if req.Hosts != nil {
+		if err := s.applyHosts(ctx, &t, req.Hosts); err != nil {
+			return nil, err
+		}
+	}
+	if req.CompanyID != nil {
+		t.CompanyID = *req.CompanyID
+	}
+	if req.GroupID != nil {
+		t.GroupID = *req.GroupID
+	}
+	if req.LocationID != nil {
+		t.LocationID = *req.LocationID
+	}
+	if req.AllowedCategoryIDs != nil {
+		t.AllowedCategories = JoinIDs(req.AllowedCategoryIDs)
+	}
+	if req.RegistrationDomains != nil {
+		t.RegistrationDomains = strings.TrimSpace(*req.RegistrationDomains)
+	}
+	t.UpdatedBy = actorEmail
+	if err := validate(&t); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.Update(ctx, &t); err != nil {
+		s.logger.WithError(err).WithField("tenantID", id).Error("failed to update tenant")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to update tenant",
+			err,
+		)
+	}
+	s.invalidate()
+
+	s.logger.WithField("tenantID", id).WithField("actorEmail", actorEmail).Info("tenant updated")
+	return toResponse(&t), nil
+}
+
+// SetAllowedCategories replaces the categories users of the tenant may
+// create tickets in. Unlike Update it also applies to the default tenant.
+func (s *service) SetAllowedCategories(ctx context.Context, id, actorEmail string, categoryIDs []int) (*Tenant, error) {
+	current, err := s.Get(ctx, id)
+	if err != nil {
+		s.logger.WithError(err).WithField("tenantID", id).Error("failed to get tenant")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to update allowed categories",
+			err,
+		)
+	}
+	if current == nil {
+		return nil, errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"tenant not found",
+			nil,
+		)
+	}
+
+	t := *current
+	t.AllowedCategories = JoinIDs(categoryIDs)
+	t.UpdatedBy = actorEmail
+	if err := s.repository.Update(ctx, &t); err != nil {
+		s.logger.WithError(err).WithField("tenantID", id).Error("failed to update allowed categories")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to update allowed categories",
+			err,
+		)
+	}
+	s.invalidate()
+	return &t, nil
+}
+
+// applyHosts normalizes hosts and makes sure no other tenant uses them.
+func (s *service) applyHosts(ctx context.Context, t *Tenant, hosts []string) error {
+	seen := make(map[string]bool, len(hosts))
+	normalized := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		host = normalizeHost(host)
+		if host == "" || strings.ContainsAny(host, "/, ") {
+			return errors.NewAppError(
+				errors.ErrCodeInvalidInput,
+				"hosts must be plain host names such as helpdesk.example.com",
+				nil,
+			)
+		}
+		if seen[host] {
+			continue
+		}
+		seen[host] = true
+		normalized = append(normalized, host)
+	}
+
+	for _, other := range s.listForCheck(ctx) {
+		if other.ID == t.ID {
+			continue
+		}
+		for _, host := range other.HostList() {
+			if seen[host] {
+				return errors.NewAppError(
+					errors.ErrCodeInvalidInput,
+					"host "+host+" is already used by tenant "+other.Slug,
+					nil,
+				)
+			}
+		}
+	}
+
+	t.Hosts = strings.Join(normalized, ",")
+	return nil
+}
+
+// listForCheck returns the cached tenants for uniqueness checks.
+func (s *service) listForCheck(ctx context.Context) []*Tenant {
+	snap, err := s.snapshot(ctx)
+	if err != nil {
+		s.logger.WithError(err).Warn("failed to load tenants for validation")
+		return nil
+	}
+	tenants := make([]*Tenant, 0, len(snap.byID))
+	for _, t := range snap.byID {
+		tenants = append(tenants, t)
+	}
+	return tenants
+}
+
+func applyConnection(t *Tenant, conn ConnectionRequest) error {
+	baseURL := strings.TrimSpace(conn.BaseURL)
+	parsed, err := url.Parse(baseURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"invgate.base_url must be an absolute http(s) URL",
+			nil,
+		)
+	}
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+
+	t.InvGateBaseURL = baseURL
+	t.InvGateUsername = strings.TrimSpace(conn.Username)
+	t.InvGatePassword = conn.Password
+	t.InvGatePageKey = strings.TrimSpace(conn.PageKey)
+	return nil
+}
+
+func validate(t *Tenant) error {
+	if t.Name == "" || len(t.Name) > 100 {
+		return errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"name must be between 1 and 100 characters",
+			nil,
+		)
+	}
+	if t.InvGateUsername == "" || t.InvGatePassword == "" {
+		return errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"invgate.username and invgate.password are required",
+			nil,
+		)
+	}
+	if t.CompanyID < 0 || t.GroupID < 0 || t.LocationID < 0 {
+		return errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"company_id, group_id and location_id must not be negative",
+			nil,
+		)
+	}
+	return nil
+}
+
+// snapshot returns the cached tenants, reloading them once the cache expired.
+func (s *service) snapshot(ctx context.Context) (*snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cache != nil && time.Since(s.cache.loadedAt) < constants.TenantCacheTTL {
+		return s.cache, nil
+	}
+
+	tenants, err := s.repository.List(ctx)
+	if err != nil {
+		if s.cache != nil {
+			// Keep serving the last known tenants while the database is unavailable
+			s.logger.WithError(err).Warn("failed to reload tenants, using cached copy")
+			return s.cache, nil
+		}
+		return nil, err
+	}
+
+	snap := &snapshot{
+		byID:     make(map[string]*Tenant, len(tenants)),
+		byHost:   make(map[string]*Tenant),
+		loadedAt: time.Now(),
+	}
+	for i := range tenants {
+		t := &tenants[i]
+		snap.byID[t.ID] = t
+		for _, host := range t.HostList() {
+			snap.byHost[host] = t
+		}
+		if t.IsDefault {
+			snap.fallback = t
+		}
+	}
+
+	s.cache = snap
+	return snap, nil
+}
+
+func (s *service) invalidate() {
+	s.mu.Lock()
+	s.cache = nil
+	s.mu.Unlock()
+}
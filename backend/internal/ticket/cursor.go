@@ -0,0 +1,95 @@
+package ticket
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"werk-ticketing/internal/errors"
+)
+
+// cursor is a position in a list ordered by (created_at, id). Clients get it
+// as an opaque string, signed so it cannot be forged or moved to another list.
+type cursor struct {
+	Scope     string `json:"s"` // List the cursor belongs to
+	CreatedAt int64  `json:"t"` // UNIX nanoseconds
+	ID        string `json:"i"`
+	Backward  bool   `json:"b,omitempty"` // Page before the position instead of after it
+}
+
+func newCursor(scope string, createdAt time.Time, id string) *cursor {
+	return &cursor{Scope: scope, CreatedAt: createdAt.UnixNano(), ID: id}
+}
+
+func (c *cursor) keyset() Keyset {
+	return Keyset{CreatedAt: time.Unix(0, c.CreatedAt).UTC(), ID: c.ID}
+}
+
+// cursorCodec encodes and verifies cursors.
+type cursorCodec struct {
+	secret []byte
+}
+
+func (cc cursorCodec) encode(c cursor) string {
+	payload, _ := json.Marshal(c)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(cc.sign(encoded))
+}
+
+// decode verifies a cursor and checks that it was issued for scope.
+func (cc cursorCodec) decode(scope, raw string) (*cursor, error) {
+	invalid := errors.NewAppError(
+		errors.ErrCodeInvalidInput,
+		"invalid cursor",
+		nil,
+	)
+
+	encoded, signature, ok := strings.Cut(raw, ".")
+	if !ok {
+		return nil, invalid
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, cc.sign(encoded)) {
+		return nil, invalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, invalid
+	}
+
+	var c cursor
+	if err := json.Unmarshal(payload, &c); err != nil || c.Scope != scope {
+		return nil, invalid
+	}
+	return &c, nil
+}
+
+func (cc cursorCodec) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, cc.secret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+// around returns the cursors of the pages next to and before a page whose
+// first and last items are given (nil for an empty page). from is the cursor
+// the page was read from, nil for a page not read by cursor. Cursors of
+// missing pages are nil so they serialize as null.
+func (cc cursorCodec) around(from, first, last *cursor, hasNext, hasPrev bool) (next, prev interface{}) {
+	if first == nil && from != nil {
+		first, last = from, from
+	}
+	if hasNext && last != nil {
+		c := *last
+		c.Backward = false
+		next = cc.encode(c)
+	}
+	if hasPrev && first != nil {
+		c := *first
+		c.Backward = true
+		prev = cc.encode(c)
+	}
+	return next, prev
+}
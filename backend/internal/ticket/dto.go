@@ -1,6 +1,10 @@
 package ticket
 
-import "mime/multipart"
+import (
+	"encoding/json"
+	"mime/multipart"
+	"time"
+)
 
 // TicketRequest represents payload for creating InvGate ticket.
 type TicketRequest struct {
@@ -18,6 +22,67 @@ type TicketRequest struct {
 	AttachmentFiles []*multipart.FileHeader `json:"-"`
 }
 
+// ListTicketsQuery holds the query parameters of the ticket list. ID lists
+// are comma separated, dates are either YYYY-MM-DD or RFC 3339. A Cursor
+// replaces Page and only works with the creation time sort.
+type ListTicketsQuery struct {
+	CreatorEmail string
+	Status       string
+	Category     string
+	Type         string
+	Priority     string
+	CreatedFrom  string
+	CreatedTo    string
+	UpdatedFrom  string
+	UpdatedTo    string
+	Query        string
+	Sort         string
+	Order        string // asc or desc
+	Cursor       string
+	Page         int
+	Limit        int
+}
+
+// TicketListItem is a ticket in a listing, served from the local read model.
+// Field names and formats follow the InvGate incident detail the list used to
+// return: timestamps are UNIX seconds.
+type TicketListItem struct {
+	ID              int    `json:"id"` // InvGate incident ID
+	PrettyID        string `json:"pretty_id,omitempty"`
+	InvGateID       string `json:"inv_gate_id"`
+	WrkTicketID     string `json:"wrk_ticket_id"`
+	Title           string `json:"title"`
+	Description     string `json:"description"`
+	SourceID        int    `json:"source_id"`
+	CreatorID       int    `json:"creator_id"`
+	CustomerID      int    `json:"customer_id"`
+	CategoryID      int    `json:"category_id"`
+	TypeID          int    `json:"type_id"`
+	PriorityID      int    `json:"priority_id"`
+	StatusID        int    `json:"status_id"`
+	Status          string `json:"status"`
+	AssignedID      int    `json:"assigned_id"`
+	AssignedGroupID int    `json:"assigned_group_id"`
+	CreatorEmail    string `json:"creator_email"`
+	CreatedAt       int64  `json:"created_at"`
+	LastUpdate      *int64 `json:"last_update"`
+	SolvedAt        *int64 `json:"solved_at"`
+	ClosedAt        *int64 `json:"closed_at"`
+	LastCommentAt   *int64 `json:"last_comment_at"`
+	SyncedAt        *int64 `json:"synced_at"` // null until the state was first loaded from InvGate
+}
+
+// TicketEventResponse is an entry of the ticket history.
+type TicketEventResponse struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	ActorEmail string          `json:"actor_email"`
+	Details    json.RawMessage `json:"details,omitempty"` // Changed fields as {"field": {"from": ..., "to": ...}} for created and updated
+	RequestID  string          `json:"request_id,omitempty"`
+	IPAddress  string          `json:"ip_address,omitempty"` // Only shown to agents and admins
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
 // TicketListResponse wraps ticket list response.
 type TicketListResponse struct {
 	PageKey string        `json:"page_key,omitempty"`
@@ -60,3 +125,14 @@ type TicketUpdateRequest struct {
 	Description *string `json:"description,omitempty"`
 	DateOcurred *int    `json:"date_ocurred,omitempty"` // UNIX timestamp
 }
+
+// TicketExport bundles what is known about one ticket of a user for a
+// personal data export. Parts InvGate could not deliver are listed in Errors.
+type TicketExport struct {
+	Ticket        Ticket                 `json:"ticket"`
+	Detail        map[string]interface{} `json:"detail,omitempty"`
+	Comments      map[string]interface{} `json:"comments,omitempty"`
+	AttachmentIDs []string               `json:"attachment_ids"`
+	Events        []TicketEvent          `json:"events,omitempty"`
+	Errors        []string               `json:"errors,omitempty"`
+}
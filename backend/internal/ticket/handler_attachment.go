@@ -8,6 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/middleware"
 	"werk-ticketing/internal/response"
 )
 
@@ -19,9 +20,15 @@ func (h *Handler) GetAttachment(c *gin.Context) {
 		return
 	}
 
+	requesterEmail := middleware.GetUserEmail(c)
+	if requesterEmail == "" {
+		response.ErrorWithCode(c, http.StatusUnauthorized, errors.ErrCodeUnauthorized, "user email not found")
+		return
+	}
+
 	acceptHeader := c.GetHeader("Accept")
 	if strings.Contains(acceptHeader, "application/json") {
-		info, err := h.service.GetTicketAttachmentInfo(c.Request.Context(), attachmentID)
+		info, err := h.service.GetTicketAttachmentInfo(c.Request.Context(), attachmentID, requesterEmail)
 		if err != nil {
 			if appErr, ok := err.(*errors.AppError); ok {
 				response.AppError(c, appErr)
@@ -34,7 +41,7 @@ func (h *Handler) GetAttachment(c *gin.Context) {
 		return
 	}
 
-	data, filename, contentType, err := h.service.GetTicketAttachment(c.Request.Context(), attachmentID)
+	data, filename, contentType, err := h.service.GetTicketAttachment(c.Request.Context(), attachmentID, requesterEmail)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			response.AppError(c, appErr)
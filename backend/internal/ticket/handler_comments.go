@@ -83,7 +83,15 @@ func (h *Handler) GetComments(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.service.GetTicketComments(c.Request.Context(), requestID)
+	requesterEmail := middleware.GetUserEmail(c)
+	if requesterEmail == "" {
+		response.ErrorWithCode(c, http.StatusUnauthorized, errors.ErrCodeUnauthorized, "user email not found")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	resp, err := h.service.GetTicketComments(c.Request.Context(), requestID, requesterEmail, c.Query("cursor"), limit)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			response.AppError(c, appErr)
@@ -3,37 +3,58 @@ package ticket
 import (
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"werk-ticketing/internal/constants"
 	"werk-ticketing/internal/errors"
 	"werk-ticketing/internal/middleware"
 	"werk-ticketing/internal/response"
+	"werk-ticketing/internal/user"
 )
 
 // List handles GET /api/tickets
 func (h *Handler) List(c *gin.Context) {
+	requesterEmail := middleware.GetUserEmail(c)
 	creatorID := c.Query("creator_id")
 	if creatorID == "" {
-		creatorID = middleware.GetUserEmail(c)
+		creatorID = requesterEmail
+	} else if !strings.EqualFold(creatorID, requesterEmail) && !middleware.HasRole(c, user.RoleAgent, user.RoleAdmin) {
+		response.ErrorWithCode(c, http.StatusForbidden, errors.ErrCodeForbidden, "not allowed to list tickets of other users")
+		return
+	}
+
+	query := ListTicketsQuery{
+		CreatorEmail: creatorID,
+		Status:       strings.Join(c.QueryArray("status_id"), ","),
+		Category:     strings.Join(c.QueryArray("category_id"), ","),
+		Type:         strings.Join(c.QueryArray("type_id"), ","),
+		Priority:     strings.Join(c.QueryArray("priority_id"), ","),
+		CreatedFrom:  c.Query("created_from"),
+		CreatedTo:    c.Query("created_to"),
+		UpdatedFrom:  c.Query("updated_from"),
+		UpdatedTo:    c.Query("updated_to"),
+		Query:        c.Query("q"),
+		Sort:         c.Query("sort"),
+		Order:        c.Query("order"),
+		Cursor:       c.Query("cursor"),
+		Page:         1,
+		Limit:        10,
 	}
-
-	page := 1
 	if pageStr := c.Query("page"); pageStr != "" {
 		if parsed, err := strconv.Atoi(pageStr); err == nil && parsed > 0 {
-			page = parsed
+			query.Page = parsed
 		}
 	}
-
-	limit := 10
 	if limitStr := c.Query("limit"); limitStr != "" {
 		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
-			limit = parsed
+			query.Limit = parsed
 		}
 	}
 
-	resp, err := h.service.GetTickets(c.Request.Context(), creatorID, page, limit)
+	resp, err := h.service.GetTickets(c.Request.Context(), query)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			response.AppError(c, appErr)
@@ -54,7 +75,13 @@ func (h *Handler) GetByID(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.service.GetTicketDetail(c.Request.Context(), ticketID)
+	requesterEmail := middleware.GetUserEmail(c)
+	if requesterEmail == "" {
+		response.ErrorWithCode(c, http.StatusUnauthorized, errors.ErrCodeUnauthorized, "user email not found")
+		return
+	}
+
+	resp, err := h.service.GetTicketDetail(c.Request.Context(), ticketID, requesterEmail)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			response.AppError(c, appErr)
@@ -79,22 +106,7 @@ func (h *Handler) GetStatuses(c *gin.Context) {
 		return
 	}
 
-	response.Write(c, http.StatusOK, resp)
-}
-
-// GetCategories handles GET /api/categories
-func (h *Handler) GetCategories(c *gin.Context) {
-	resp, err := h.service.GetCategories(c.Request.Context())
-	if err != nil {
-		if appErr, ok := err.(*errors.AppError); ok {
-			response.AppError(c, appErr)
-		} else {
-			response.ErrorWithCode(c, http.StatusBadGateway, errors.ErrCodeExternalService, err.Error())
-		}
-		return
-	}
-
-	response.Write(c, http.StatusOK, resp)
+	response.WriteCacheable(c, http.StatusOK, resp, constants.ReferenceCacheMaxAge)
 }
 
 // GetMeta handles GET /api/ticket-meta
@@ -109,7 +121,7 @@ func (h *Handler) GetMeta(c *gin.Context) {
 		return
 	}
 
-	response.Write(c, http.StatusOK, resp)
+	response.WriteCacheable(c, http.StatusOK, resp, constants.ReferenceCacheMaxAge)
 }
 
 // GetArticlesByCategory handles GET /api/articles
@@ -136,7 +148,7 @@ func (h *Handler) GetArticlesByCategory(c *gin.Context) {
 		return
 	}
 
-	response.Write(c, http.StatusOK, resp)
+	response.WriteCacheable(c, http.StatusOK, resp, constants.ReferenceCacheMaxAge)
 }
 
 // GetInvGateUser handles GET /api/users/:id
@@ -195,7 +207,13 @@ func (h *Handler) Update(c *gin.Context) {
 		}
 	}
 
-	resp, err := h.service.UpdateTicket(c.Request.Context(), ticketID, req)
+	requesterEmail := middleware.GetUserEmail(c)
+	if requesterEmail == "" {
+		response.ErrorWithCode(c, http.StatusUnauthorized, errors.ErrCodeUnauthorized, "user email not found")
+		return
+	}
+
+	resp, err := h.service.UpdateTicket(c.Request.Context(), ticketID, req, requesterEmail)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			response.AppError(c, appErr)
@@ -0,0 +1,40 @@
+package ticket
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/middleware"
+	"werk-ticketing/internal/response"
+	"werk-ticketing/internal/user"
+)
+
+// GetHistory handles GET /api/tickets/:id/history
+func (h *Handler) GetHistory(c *gin.Context) {
+	ticketID := c.Param("id")
+	if ticketID == "" {
+		response.ErrorWithCode(c, http.StatusBadRequest, errors.ErrCodeInvalidInput, "ticket id is required")
+		return
+	}
+
+	requesterEmail := middleware.GetUserEmail(c)
+	if requesterEmail == "" {
+		response.ErrorWithCode(c, http.StatusUnauthorized, errors.ErrCodeUnauthorized, "user email not found")
+		return
+	}
+
+	includeSource := middleware.HasRole(c, user.RoleAgent, user.RoleAdmin)
+	resp, err := h.service.GetTicketHistory(c.Request.Context(), ticketID, requesterEmail, includeSource)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			response.ErrorWithCode(c, http.StatusInternalServerError, errors.ErrCodeInternal, err.Error())
+		}
+		return
+	}
+
+	response.Write(c, http.StatusOK, gin.H{"data": resp})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/middleware"
 	"werk-ticketing/internal/response"
 )
 
@@ -51,7 +52,13 @@ func (h *Handler) AcceptSolution(c *gin.Context) {
 		Comment:   body.Comment,
 	}
 
-	resp, err := h.service.UpdateTicketSolution(c.Request.Context(), req)
+	requesterEmail := middleware.GetUserEmail(c)
+	if requesterEmail == "" {
+		response.ErrorWithCode(c, http.StatusUnauthorized, errors.ErrCodeUnauthorized, "user email not found")
+		return
+	}
+
+	resp, err := h.service.UpdateTicketSolution(c.Request.Context(), req, requesterEmail)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			response.AppError(c, appErr)
@@ -97,7 +104,13 @@ func (h *Handler) RejectSolution(c *gin.Context) {
 		Comment:   body.Comment,
 	}
 
-	resp, err := h.service.RejectTicketSolution(c.Request.Context(), req)
+	requesterEmail := middleware.GetUserEmail(c)
+	if requesterEmail == "" {
+		response.ErrorWithCode(c, http.StatusUnauthorized, errors.ErrCodeUnauthorized, "user email not found")
+		return
+	}
+
+	resp, err := h.service.RejectTicketSolution(c.Request.Context(), req, requesterEmail)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			response.AppError(c, appErr)
@@ -1,10 +1,17 @@
 package ticket
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"werk-ticketing/internal/database"
+)
 
 // Ticket represents the persisted ticket entity in local database.
 type Ticket struct {
 	ID           string    `gorm:"type:char(36);primaryKey;default:(UUID())"` // Local identifier, use UUID generated by DB
+	TenantID     string    `gorm:"type:char(36);not null;default:'';index"`   // Tenant the ticket belongs to
 	InvGateID    string    `gorm:"size:100;not null;index"`                   // ID dari InvGate Armmada
 	SourceID     int       `gorm:"not null"`
 	CreatorID    int       `gorm:"not null"`
@@ -12,15 +19,76 @@ type Ticket struct {
 	CategoryID   int       `gorm:"not null"`
 	TypeID       int       `gorm:"not null"`
 	PriorityID   int       `gorm:"not null"`
-	Title        string    `gorm:"size:255;not null"`
-	Description  string    `gorm:"type:text;not null"`
+	Title        string    `gorm:"size:255;not null;index:idx_tickets_search,class:FULLTEXT"`
+	Description  string    `gorm:"type:text;not null;index:idx_tickets_search,class:FULLTEXT"`
 	CreatorEmail string    `gorm:"size:190;not null;index"`    // Email user yang membuat ticket
 	CreatedBy    string    `gorm:"size:190;column:created_by"` // Email of user who created this record
 	UpdatedBy    string    `gorm:"size:190;column:updated_by"` // Email of user who last updated this record
 	CreatedAt    time.Time `gorm:"autoCreateTime"`
 	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+
+	// Read model of the InvGate state, refreshed by the sync worker
+	PrettyID         string     `gorm:"size:50;column:pretty_id"` // Display ID of the InvGate incident
+	StatusID         int        `gorm:"not null;default:0;index"` // 0 until the first sync
+	AssignedID       int        `gorm:"not null;default:0;index"` // InvGate user the ticket is assigned to
+	AssignedGroupID  int        `gorm:"not null;default:0"`       // InvGate group the ticket is assigned to
+	SolvedAt         *time.Time // When a solution was last provided
+	ClosedAt         *time.Time `gorm:"index"`
+	LastCommentAt    *time.Time
+	InvGateUpdatedAt *time.Time `gorm:"column:invgate_updated_at"` // last_update of the InvGate incident
+	SyncedAt         *time.Time `gorm:"index"`                     // Last refresh from InvGate, NULL schedules the next sync
 }
 
 func (Ticket) TableName() string {
 	return "tickets"
 }
+
+// TicketAttachment maps an InvGate attachment ID back to the ticket it belongs to.
+// Rows are recorded whenever attachment IDs show up in ticket or comment payloads,
+// so downloads can be authorized against the owning ticket. Attachment IDs are
+// only unique within one InvGate instance, hence the tenant in the key.
+type TicketAttachment struct {
+	TenantID     string    `gorm:"type:char(36);primaryKey;default:''"`
+	AttachmentID string    `gorm:"size:100;primaryKey"`
+	InvGateID    string    `gorm:"size:100;not null;index"` // InvGate ID of the owning ticket
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+func (TicketAttachment) TableName() string {
+	return "ticket_attachments"
+}
+
+// Ticket event types recorded in the history of a ticket.
+const (
+	EventCreated          = "created"
+	EventUpdated          = "updated"
+	EventCommented        = "commented"
+	EventSolutionAccepted = "solution_accepted"
+	EventSolutionRejected = "solution_rejected"
+)
+
+// TicketEvent is an entry in the history of a ticket, recorded for every
+// change made through this service.
+type TicketEvent struct {
+	ID         string    `gorm:"type:char(36);primaryKey"`
+	TenantID   string    `gorm:"type:char(36);not null;default:'';index:idx_ticket_events_ticket"`
+	InvGateID  string    `gorm:"size:100;not null;index:idx_ticket_events_ticket"` // InvGate ID of the ticket
+	Type       string    `gorm:"size:30;not null"`                                 // One of the Event constants
+	ActorEmail string    `gorm:"size:190;not null;index"`
+	Details    string    `gorm:"type:text"`                 // JSON, e.g. the changed fields of an update
+	RequestID  string    `gorm:"size:64;column:request_id"` // X-Request-ID of the HTTP request
+	IPAddress  string    `gorm:"size:64;column:ip_address"` // Client IP of the HTTP request
+	CreatedAt  time.Time `gorm:"autoCreateTime;index"`
+}
+
+func (TicketEvent) TableName() string {
+	return "ticket_events"
+}
+
+// BeforeCreate assigns the UUID in Go so the ID is known to the caller after insert.
+func (e *TicketEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = database.NewUUID()
+	}
+	return nil
+}
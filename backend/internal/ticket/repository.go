@@ -3,18 +3,78 @@ package ticket
 import (
 	"context"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Repository abstracts data persistence for tickets.
 type Repository interface {
 	Create(ctx context.Context, ticket *Ticket) error
-	GetByInvGateID(ctx context.Context, invGateID string) (*Ticket, error)
-	GetByCreatorEmail(ctx context.Context, creatorEmail string) ([]*Ticket, error)
-	GetByCreatorEmailPaginated(ctx context.Context, creatorEmail string, limit, offset int) ([]*Ticket, error)
-	CountByCreatorEmail(ctx context.Context, creatorEmail string) (int64, error)
+	GetByInvGateID(ctx context.Context, tenantID, invGateID string) (*Ticket, error)
+	GetByCreatorEmail(ctx context.Context, tenantID, creatorEmail string) ([]*Ticket, error)
+	List(ctx context.Context, filter ListFilter, limit, offset int) ([]*Ticket, error)
+	Count(ctx context.Context, filter ListFilter) (int64, error)
+	ListAfter(ctx context.Context, filter ListFilter, after Keyset, backward bool, limit int) ([]*Ticket, error)
 	GetByID(ctx context.Context, id string) (*Ticket, error)
+	SaveAttachments(ctx context.Context, tenantID, invGateID string, attachmentIDs []string) error
+	GetAttachment(ctx context.Context, tenantID, attachmentID string) (*TicketAttachment, error)
+	GetAttachmentsByInvGateID(ctx context.Context, tenantID, invGateID string) ([]TicketAttachment, error)
+	AnonymizeCreator(ctx context.Context, tenantID, creatorEmail, replacement string) error
+	ListForSync(ctx context.Context, closedAfter time.Time, limit int) ([]*Ticket, error)
+	UpdateReadModel(ctx context.Context, t *Ticket) error
+	MarkStale(ctx context.Context, tenantID, invGateID string) error
+	ApplyUpdate(ctx context.Context, t *Ticket) error
+	TouchLastComment(ctx context.Context, tenantID, invGateID string, at time.Time) error
+	CreateEvent(ctx context.Context, event *TicketEvent) error
+	ListEvents(ctx context.Context, tenantID, invGateID string) ([]TicketEvent, error)
+}
+
+// Sort orders accepted by ListFilter.
+const (
+	SortCreatedAt = "created_at"
+	SortUpdatedAt = "updated_at" // last_update of the InvGate incident
+	SortStatus    = "status"
+	SortCategory  = "category"
+	SortType      = "type"
+	SortPriority  = "priority"
+	SortRelevance = "relevance" // Only meaningful together with Query
+)
+
+// sortColumns maps the sort orders to the columns they sort on. Tickets not
+// synced yet have no InvGate update time and fall back to their creation.
+var sortColumns = map[string]string{
+	SortCreatedAt: "created_at",
+	SortUpdatedAt: "COALESCE(invgate_updated_at, created_at)",
+	SortStatus:    "status_id",
+	SortCategory:  "category_id",
+	SortType:      "type_id",
+	SortPriority:  "priority_id",
+}
+
+// ListFilter narrows down List and Count. Empty fields do not filter; ID
+// lists match any of their IDs and time ranges include From and exclude To.
+type ListFilter struct {
+	TenantID     string
+	CreatorEmail string
+	StatusIDs    []int
+	CategoryIDs  []int
+	TypeIDs      []int
+	PriorityIDs  []int
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	UpdatedFrom  *time.Time
+	UpdatedTo    *time.Time
+	Query        string // Full-text search over title and description, in MySQL boolean mode syntax
+	Sort         string // One of the Sort constants, SortCreatedAt when empty
+	Desc         bool
+}
+
+// Keyset is a position in the ticket list ordered by creation, see ListAfter.
+type Keyset struct {
+	CreatedAt time.Time
+	ID        string
 }
 
 type gormRepository struct {
@@ -30,9 +90,11 @@ func (r *gormRepository) Create(ctx context.Context, ticket *Ticket) error {
 	return r.db.WithContext(ctx).Create(ticket).Error
 }
 
-func (r *gormRepository) GetByInvGateID(ctx context.Context, invGateID string) (*Ticket, error) {
+// GetByInvGateID looks a ticket up by its InvGate ID. InvGate IDs are only
+// unique within one InvGate instance, so the tenant is part of the lookup.
+func (r *gormRepository) GetByInvGateID(ctx context.Context, tenantID, invGateID string) (*Ticket, error) {
 	var t Ticket
-	err := r.db.WithContext(ctx).Where("inv_gate_id = ?", invGateID).First(&t).Error
+	err := r.db.WithContext(ctx).Where("tenant_id = ? AND inv_gate_id = ?", tenantID, invGateID).First(&t).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -42,12 +104,12 @@ func (r *gormRepository) GetByInvGateID(ctx context.Context, invGateID string) (
 	return &t, nil
 }
 
-func (r *gormRepository) GetByCreatorEmail(ctx context.Context, creatorEmail string) ([]*Ticket, error) {
+func (r *gormRepository) GetByCreatorEmail(ctx context.Context, tenantID, creatorEmail string) ([]*Ticket, error) {
 	var tickets []*Ticket
 	// Order by created_at DESC to show newest tickets first
 	// Note: This makes tickets with newer inv_gate_id appear first because
 	// newer tickets typically have both newer created_at and higher inv_gate_id
-	query := r.db.WithContext(ctx).Order("created_at DESC")
+	query := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).Order("created_at DESC")
 	if creatorEmail != "" {
 		query = query.Where("creator_email = ?", creatorEmail)
 	}
@@ -59,14 +121,31 @@ func (r *gormRepository) GetByCreatorEmail(ctx context.Context, creatorEmail str
 	return tickets, nil
 }
 
-// GetByCreatorEmailPaginated retrieves tickets by creator email with pagination support.
-func (r *gormRepository) GetByCreatorEmailPaginated(ctx context.Context, creatorEmail string, limit, offset int) ([]*Ticket, error) {
-	var tickets []*Ticket
-	query := r.db.WithContext(ctx).Order("created_at DESC")
-	if creatorEmail != "" {
-		query = query.Where("creator_email = ?", creatorEmail)
+// List returns one page of the tickets matching the filter. Ties are broken
+// by ID so pages stay stable.
+func (r *gormRepository) List(ctx context.Context, filter ListFilter, limit, offset int) ([]*Ticket, error) {
+	direction := "ASC"
+	if filter.Desc {
+		direction = "DESC"
 	}
 
+	query := applyListFilter(r.db.WithContext(ctx), filter)
+	if filter.Sort == SortRelevance && filter.Query != "" {
+		// A single expression, as gorm drops ORDER BY expressions merged with columns
+		query = query.Clauses(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "MATCH(title, description) AGAINST (? IN BOOLEAN MODE) " + direction + ", id " + direction,
+			Vars:               []interface{}{filter.Query},
+			WithoutParentheses: true,
+		}})
+	} else {
+		column, ok := sortColumns[filter.Sort]
+		if !ok {
+			column = sortColumns[SortCreatedAt]
+		}
+		query = query.Order(column + " " + direction).Order("id " + direction)
+	}
+
+	var tickets []*Ticket
 	err := query.Limit(limit).Offset(offset).Find(&tickets).Error
 	if err != nil {
 		return nil, err
@@ -74,21 +153,81 @@ func (r *gormRepository) GetByCreatorEmailPaginated(ctx context.Context, creator
 	return tickets, nil
 }
 
-// CountByCreatorEmail counts total tickets by creator email.
-func (r *gormRepository) CountByCreatorEmail(ctx context.Context, creatorEmail string) (int64, error) {
-	var count int64
-	query := r.db.WithContext(ctx).Model(&Ticket{})
-	if creatorEmail != "" {
-		query = query.Where("creator_email = ?", creatorEmail)
+// ListAfter returns up to limit tickets matching the filter that follow the
+// given position in creation order (ascending or descending as in the filter),
+// or precede it when backward is set. Unlike List, pages stay consistent while
+// tickets are added. The filter's Sort is ignored.
+func (r *gormRepository) ListAfter(ctx context.Context, filter ListFilter, after Keyset, backward bool, limit int) ([]*Ticket, error) {
+	// Walking backward reads the list in reverse and flips the page afterwards
+	op, direction := ">", "ASC"
+	if filter.Desc != backward {
+		op, direction = "<", "DESC"
 	}
 
-	err := query.Count(&count).Error
+	var tickets []*Ticket
+	err := applyListFilter(r.db.WithContext(ctx), filter).
+		Where("created_at "+op+" ? OR (created_at = ? AND id "+op+" ?)", after.CreatedAt, after.CreatedAt, after.ID).
+		Order("created_at " + direction).
+		Order("id " + direction).
+		Limit(limit).
+		Find(&tickets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if backward {
+		for i, j := 0, len(tickets)-1; i < j; i, j = i+1, j-1 {
+			tickets[i], tickets[j] = tickets[j], tickets[i]
+		}
+	}
+	return tickets, nil
+}
+
+// Count counts the tickets matching the filter.
+func (r *gormRepository) Count(ctx context.Context, filter ListFilter) (int64, error) {
+	var count int64
+	err := applyListFilter(r.db.WithContext(ctx).Model(&Ticket{}), filter).Count(&count).Error
 	if err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
+func applyListFilter(query *gorm.DB, filter ListFilter) *gorm.DB {
+	query = query.Where("tenant_id = ?", filter.TenantID)
+	if filter.CreatorEmail != "" {
+		query = query.Where("creator_email = ?", filter.CreatorEmail)
+	}
+	if len(filter.StatusIDs) > 0 {
+		query = query.Where("status_id IN ?", filter.StatusIDs)
+	}
+	if len(filter.CategoryIDs) > 0 {
+		query = query.Where("category_id IN ?", filter.CategoryIDs)
+	}
+	if len(filter.TypeIDs) > 0 {
+		query = query.Where("type_id IN ?", filter.TypeIDs)
+	}
+	if len(filter.PriorityIDs) > 0 {
+		query = query.Where("priority_id IN ?", filter.PriorityIDs)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
+	}
+	if filter.UpdatedFrom != nil {
+		query = query.Where(sortColumns[SortUpdatedAt]+" >= ?", *filter.UpdatedFrom)
+	}
+	if filter.UpdatedTo != nil {
+		query = query.Where(sortColumns[SortUpdatedAt]+" < ?", *filter.UpdatedTo)
+	}
+	if filter.Query != "" {
+		query = query.Where("MATCH(title, description) AGAINST (? IN BOOLEAN MODE)", filter.Query)
+	}
+	return query
+}
+
 func (r *gormRepository) GetByID(ctx context.Context, id string) (*Ticket, error) {
 	var t Ticket
 	err := r.db.WithContext(ctx).Where("id = ?", id).First(&t).Error
@@ -100,3 +239,142 @@ func (r *gormRepository) GetByID(ctx context.Context, id string) (*Ticket, error
 	}
 	return &t, nil
 }
+
+// SaveAttachments records which ticket the given attachment IDs belong to.
+// Already known attachments are left untouched.
+func (r *gormRepository) SaveAttachments(ctx context.Context, tenantID, invGateID string, attachmentIDs []string) error {
+	if len(attachmentIDs) == 0 {
+		return nil
+	}
+
+	rows := make([]TicketAttachment, 0, len(attachmentIDs))
+	for _, id := range attachmentIDs {
+		rows = append(rows, TicketAttachment{TenantID: tenantID, AttachmentID: id, InvGateID: invGateID})
+	}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+// GetAttachment returns the attachment-to-ticket mapping, or nil if the attachment is unknown.
+func (r *gormRepository) GetAttachment(ctx context.Context, tenantID, attachmentID string) (*TicketAttachment, error) {
+	var a TicketAttachment
+	err := r.db.WithContext(ctx).Where("tenant_id = ? AND attachment_id = ?", tenantID, attachmentID).First(&a).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+// GetAttachmentsByInvGateID returns the attachments recorded for a ticket.
+func (r *gormRepository) GetAttachmentsByInvGateID(ctx context.Context, tenantID, invGateID string) ([]TicketAttachment, error) {
+	var attachments []TicketAttachment
+	err := r.db.WithContext(ctx).Where("tenant_id = ? AND inv_gate_id = ?", tenantID, invGateID).Find(&attachments).Error
+	return attachments, err
+}
+
+// AnonymizeCreator replaces the email of a deleted user on all tickets of its
+// tenant it created or last changed.
+func (r *gormRepository) AnonymizeCreator(ctx context.Context, tenantID, creatorEmail, replacement string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		columns := []string{"creator_email", "created_by", "updated_by"}
+		for _, column := range columns {
+			err := tx.Model(&Ticket{}).Where("tenant_id = ? AND "+column+" = ?", tenantID, creatorEmail).Update(column, replacement).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Model(&TicketEvent{}).Where("tenant_id = ? AND actor_email = ?", tenantID, creatorEmail).UpdateColumns(map[string]interface{}{
+			"actor_email": replacement,
+			"ip_address":  "",
+		}).Error
+	})
+}
+
+// ListForSync returns the tickets of all tenants whose read model is due for
+// a refresh: never synced ones first, then the least recently synced. Tickets
+// closed before closedAfter are only picked up when marked stale.
+func (r *gormRepository) ListForSync(ctx context.Context, closedAfter time.Time, limit int) ([]*Ticket, error) {
+	var tickets []*Ticket
+	err := r.db.WithContext(ctx).
+		Where("inv_gate_id <> ''").
+		Where("synced_at IS NULL OR closed_at IS NULL OR closed_at > ?", closedAfter).
+		Order("synced_at IS NULL DESC, synced_at ASC").
+		Limit(limit).
+		Find(&tickets).Error
+	return tickets, err
+}
+
+// UpdateReadModel saves the fields synced from InvGate.
+func (r *gormRepository) UpdateReadModel(ctx context.Context, t *Ticket) error {
+	return r.db.WithContext(ctx).Model(&Ticket{}).Where("id = ?", t.ID).UpdateColumns(map[string]interface{}{
+		"title":              t.Title,
+		"description":        t.Description,
+		"pretty_id":          t.PrettyID,
+		"category_id":        t.CategoryID,
+		"type_id":            t.TypeID,
+		"priority_id":        t.PriorityID,
+		"status_id":          t.StatusID,
+		"assigned_id":        t.AssignedID,
+		"assigned_group_id":  t.AssignedGroupID,
+		"solved_at":          t.SolvedAt,
+		"closed_at":          t.ClosedAt,
+		"last_comment_at":    t.LastCommentAt,
+		"invgate_updated_at": t.InvGateUpdatedAt,
+		"synced_at":          t.SyncedAt,
+	}).Error
+}
+
+// MarkStale schedules a ticket for the next sync, e.g. after it was changed through this service.
+func (r *gormRepository) MarkStale(ctx context.Context, tenantID, invGateID string) error {
+	return r.db.WithContext(ctx).Model(&Ticket{}).
+		Where("tenant_id = ? AND inv_gate_id = ?", tenantID, invGateID).
+		UpdateColumn("synced_at", nil).Error
+}
+
+// ApplyUpdate saves the fields of a ticket editable through this service and
+// schedules it for the next sync, which picks up the changes InvGate derived.
+func (r *gormRepository) ApplyUpdate(ctx context.Context, t *Ticket) error {
+	return r.db.WithContext(ctx).Model(&Ticket{}).Where("id = ?", t.ID).UpdateColumns(map[string]interface{}{
+		"source_id":   t.SourceID,
+		"creator_id":  t.CreatorID,
+		"customer_id": t.CustomerID,
+		"category_id": t.CategoryID,
+		"type_id":     t.TypeID,
+		"priority_id": t.PriorityID,
+		"title":       t.Title,
+		"description": t.Description,
+		"updated_by":  t.UpdatedBy,
+		"updated_at":  time.Now().UTC(),
+		"synced_at":   nil,
+	}).Error
+}
+
+// TouchLastComment records a comment added through this service and schedules the ticket for the next sync.
+func (r *gormRepository) TouchLastComment(ctx context.Context, tenantID, invGateID string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&Ticket{}).
+		Where("tenant_id = ? AND inv_gate_id = ?", tenantID, invGateID).
+		UpdateColumns(map[string]interface{}{
+			"last_comment_at": at,
+			"synced_at":       nil,
+		}).Error
+}
+
+func (r *gormRepository) CreateEvent(ctx context.Context, event *TicketEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// ListEvents returns the history of a ticket, oldest first.
+func (r *gormRepository) ListEvents(ctx context.Context, tenantID, invGateID string) ([]TicketEvent, error) {
+	var events []TicketEvent
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND inv_gate_id = ?", tenantID, invGateID).
+		Order("created_at ASC, id ASC").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -5,42 +5,58 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"werk-ticketing/internal/constants"
 	"werk-ticketing/internal/invgate"
+	"werk-ticketing/internal/tenant"
+	"werk-ticketing/internal/ticketmeta"
 	"werk-ticketing/internal/user"
 )
 
 // Service handles ticket business logic.
 type Service interface {
 	CreateTicket(ctx context.Context, req TicketRequest, creatorEmail string) (map[string]interface{}, error)
-	GetTickets(ctx context.Context, creatorID string, page, limit int) (map[string]interface{}, error)
-	GetTicketDetail(ctx context.Context, ticketID string) (map[string]interface{}, error)
-	GetCategories(ctx context.Context) (map[string]interface{}, error)
+	GetTickets(ctx context.Context, query ListTicketsQuery) (map[string]interface{}, error)
+	GetTicketDetail(ctx context.Context, ticketID, requesterEmail string) (map[string]interface{}, error)
 	GetTicketMeta(ctx context.Context) (map[string]interface{}, error)
 	GetStatuses(ctx context.Context) (map[string]interface{}, error)
 	AddTicketComment(ctx context.Context, req TicketCommentRequest, authorEmail string) (map[string]interface{}, error)
-	GetTicketComments(ctx context.Context, ticketID int) (map[string]interface{}, error)
-	GetTicketAttachment(ctx context.Context, attachmentID string) ([]byte, string, string, error)
-	GetTicketAttachmentInfo(ctx context.Context, attachmentID string) (map[string]interface{}, error)
-	UpdateTicketSolution(ctx context.Context, req TicketSolutionRequest) (map[string]interface{}, error)
-	RejectTicketSolution(ctx context.Context, req TicketSolutionRejectRequest) (map[string]interface{}, error)
-	UpdateTicket(ctx context.Context, ticketID int, req TicketUpdateRequest) (map[string]interface{}, error)
+	GetTicketComments(ctx context.Context, ticketID int, requesterEmail, cursor string, limit int) (map[string]interface{}, error)
+	GetTicketAttachment(ctx context.Context, attachmentID, requesterEmail string) ([]byte, string, string, error)
+	GetTicketAttachmentInfo(ctx context.Context, attachmentID, requesterEmail string) (map[string]interface{}, error)
+	UpdateTicketSolution(ctx context.Context, req TicketSolutionRequest, requesterEmail string) (map[string]interface{}, error)
+	RejectTicketSolution(ctx context.Context, req TicketSolutionRejectRequest, requesterEmail string) (map[string]interface{}, error)
+	UpdateTicket(ctx context.Context, ticketID int, req TicketUpdateRequest, requesterEmail string) (map[string]interface{}, error)
 	GetInvGateUser(ctx context.Context, userID int) (map[string]interface{}, error)
 	GetArticlesByCategory(ctx context.Context, categoryID int) (map[string]interface{}, error)
+	GetTicketHistory(ctx context.Context, ticketID, requesterEmail string, includeSource bool) ([]TicketEventResponse, error)
+	ExportUserTickets(ctx context.Context, creatorEmail string) ([]TicketExport, error)
+	AnonymizeCreator(ctx context.Context, tenantID, creatorEmail, replacement string) error
 }
 
 type service struct {
 	client     invgate.Service
 	repository Repository
 	userRepo   user.Repository
+	meta       ticketmeta.Service
+	tenants    tenant.Service
+	cursors    cursorCodec
 	logger     *logrus.Logger
 }
 
-// NewService returns ticket service.
-func NewService(client invgate.Service, repo Repository, userRepo user.Repository, logger *logrus.Logger) Service {
-	return &service{
+// NewService returns ticket service and starts the sync of the local ticket
+// read model from InvGate. cursorSecret signs the pagination cursors.
+func NewService(client invgate.Service, repo Repository, userRepo user.Repository, meta ticketmeta.Service, tenants tenant.Service, cursorSecret []byte, logger *logrus.Logger) Service {
+	s := &service{
 		client:     client,
 		repository: repo,
 		userRepo:   userRepo,
+		meta:       meta,
+		tenants:    tenants,
+		cursors:    cursorCodec{secret: cursorSecret},
 		logger:     logger,
 	}
+
+	go s.runSync(constants.TicketSyncInterval)
+
+	return s
 }
@@ -0,0 +1,143 @@
+package ticket
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/tenant"
+	"werk-ticketing/internal/user"
+)
+
+// authorizeTicketAccess loads the local ticket for an InvGate ID and makes sure
+// the requester is the user who created it. Agents and admins may access any
+// ticket of their tenant; tickets of other tenants are not found.
+func (s *service) authorizeTicketAccess(ctx context.Context, invGateID, requesterEmail string) (*Ticket, error) {
+	if requesterEmail == "" {
+		return nil, errors.NewAppError(
+			errors.ErrCodeUnauthorized,
+			"user email not found",
+			nil,
+		)
+	}
+
+	t, err := s.repository.GetByInvGateID(ctx, tenant.IDFromContext(ctx), invGateID)
+	if err != nil {
+		s.logger.WithError(err).WithField("invGateID", invGateID).Error("failed to get ticket from repository")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to fetch ticket from database",
+			err,
+		)
+	}
+	if t == nil {
+		return nil, errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"ticket not found",
+			nil,
+		)
+	}
+
+	if strings.EqualFold(t.CreatorEmail, requesterEmail) {
+		return t, nil
+	}
+
+	requester, err := s.userRepo.GetByEmail(ctx, tenant.IDFromContext(ctx), requesterEmail)
+	if err != nil {
+		s.logger.WithError(err).WithField("requesterEmail", requesterEmail).Error("failed to get user by email")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to retrieve user information",
+			err,
+		)
+	}
+	if requester == nil || !requester.HasRole(user.RoleAgent, user.RoleAdmin) {
+		s.logger.WithFields(logrus.Fields{
+			"invGateID":      invGateID,
+			"requesterEmail": requesterEmail,
+		}).Warn("ticket access denied")
+		return nil, errors.NewAppError(
+			errors.ErrCodeForbidden,
+			"you do not have access to this ticket",
+			nil,
+		)
+	}
+
+	return t, nil
+}
+
+// authorizeAttachmentAccess resolves the ticket an attachment belongs to and
+// checks that the requester may access that ticket.
+func (s *service) authorizeAttachmentAccess(ctx context.Context, attachmentID, requesterEmail string) error {
+	attachment, err := s.repository.GetAttachment(ctx, tenant.IDFromContext(ctx), attachmentID)
+	if err != nil {
+		s.logger.WithError(err).WithField("attachmentID", attachmentID).Error("failed to get attachment from repository")
+		return errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to fetch attachment from database",
+			err,
+		)
+	}
+	if attachment == nil {
+		return errors.NewAppError(
+			errors.ErrCodeNotFound,
+			"attachment not found",
+			nil,
+		)
+	}
+
+	_, err = s.authorizeTicketAccess(ctx, attachment.InvGateID, requesterEmail)
+	return err
+}
+
+// recordAttachments stores the attachment IDs found in an InvGate payload so
+// they can later be traced back to the ticket. Failures are only logged.
+func (s *service) recordAttachments(ctx context.Context, invGateID string, attachmentIDs []string) {
+	if invGateID == "" || len(attachmentIDs) == 0 {
+		return
+	}
+
+	if err := s.repository.SaveAttachments(ctx, tenant.IDFromContext(ctx), invGateID, attachmentIDs); err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"invGateID":     invGateID,
+			"attachmentIDs": attachmentIDs,
+		}).Warn("failed to record ticket attachments")
+	}
+}
+
+// extractAttachmentIDs collects attachment IDs from an InvGate list field.
+// Items can either be plain IDs or embedded attachment objects with an "id" key.
+func extractAttachmentIDs(raw interface{}) []string {
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var ids []string
+	for _, item := range items {
+		if obj, ok := item.(map[string]interface{}); ok {
+			item, ok = obj["id"]
+			if !ok {
+				continue
+			}
+		}
+
+		if item == nil {
+			continue
+		}
+		id, err := convertToString(item)
+		if err != nil || id == "" {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// extractCommentAttachmentIDs collects attachment IDs from a single comment object.
+func extractCommentAttachmentIDs(comment map[string]interface{}) []string {
+	ids := extractAttachmentIDs(comment["attached_files"])
+	return append(ids, extractAttachmentIDs(comment["attachments"])...)
+}
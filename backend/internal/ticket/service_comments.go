@@ -2,14 +2,22 @@ package ticket
 
 import (
 	"context"
+	"strconv"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/tenant"
 )
 
 func (s *service) AddTicketComment(ctx context.Context, req TicketCommentRequest, authorEmail string) (map[string]interface{}, error) {
-	user, err := s.userRepo.GetByEmail(ctx, authorEmail)
+	ticketID := strconv.Itoa(req.RequestID)
+	if _, err := s.authorizeTicketAccess(ctx, ticketID, authorEmail); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, tenant.IDFromContext(ctx), authorEmail)
 	if err != nil {
 		s.logger.WithError(err).WithField("authorEmail", authorEmail).Error("failed to get user by email")
 		return nil, errors.NewAppError(
@@ -41,10 +49,24 @@ func (s *service) AddTicketComment(ctx context.Context, req TicketCommentRequest
 		)
 	}
 
+	s.recordAttachments(ctx, ticketID, extractCommentAttachmentIDs(resp))
+	if err := s.repository.TouchLastComment(ctx, tenant.IDFromContext(ctx), ticketID, time.Now().UTC()); err != nil {
+		s.logger.WithError(err).WithField("invGateID", ticketID).Warn("failed to record ticket comment time")
+	}
+	s.recordEvent(ctx, ticketID, EventCommented, authorEmail, commentDetails(resp))
+
 	return resp, nil
 }
 
-func (s *service) GetTicketComments(ctx context.Context, ticketID int) (map[string]interface{}, error) {
+// GetTicketComments returns the comments of a ticket. They are paginated when
+// a cursor or limit is given, otherwise all comments are returned so older
+// clients keep working.
+func (s *service) GetTicketComments(ctx context.Context, ticketID int, requesterEmail, cursor string, limit int) (map[string]interface{}, error) {
+	invGateID := strconv.Itoa(ticketID)
+	if _, err := s.authorizeTicketAccess(ctx, invGateID, requesterEmail); err != nil {
+		return nil, err
+	}
+
 	resp, err := s.client.GetTicketComments(ctx, ticketID)
 	if err != nil {
 		s.logger.WithError(err).WithField("requestID", ticketID).Error("failed to get ticket comments from InvGate")
@@ -54,6 +76,23 @@ func (s *service) GetTicketComments(ctx context.Context, ticketID int) (map[stri
 			err,
 		)
 	}
+
+	if comments, ok := resp["data"].([]interface{}); ok {
+		var attachmentIDs []string
+		for _, item := range comments {
+			if comment, ok := item.(map[string]interface{}); ok {
+				attachmentIDs = append(attachmentIDs, extractCommentAttachmentIDs(comment)...)
+			}
+		}
+		s.recordAttachments(ctx, invGateID, attachmentIDs)
+	}
+
+	if cursor != "" || limit > 0 {
+		if err := s.paginateComments(resp, ticketID, cursor, limit); err != nil {
+			return nil, err
+		}
+	}
+
 	return resp, nil
 }
 
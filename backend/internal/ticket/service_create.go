@@ -8,10 +8,11 @@ import (
 
 	"werk-ticketing/internal/errors"
 	"werk-ticketing/internal/invgate"
+	"werk-ticketing/internal/tenant"
 )
 
 func (s *service) CreateTicket(ctx context.Context, req TicketRequest, creatorEmail string) (map[string]interface{}, error) {
-	user, err := s.userRepo.GetByEmail(ctx, creatorEmail)
+	user, err := s.userRepo.GetByEmail(ctx, tenant.IDFromContext(ctx), creatorEmail)
 	if err != nil {
 		s.logger.WithError(err).WithField("creatorEmail", creatorEmail).Error("failed to get user by email")
 		return nil, errors.NewAppError(
@@ -28,6 +29,14 @@ func (s *service) CreateTicket(ctx context.Context, req TicketRequest, creatorEm
 		)
 	}
 
+	if !user.IsEmailVerified() {
+		return nil, errors.NewAppError(
+			errors.ErrCodeEmailNotVerified,
+			"please verify your email address before creating tickets",
+			nil,
+		)
+	}
+
 	invgateUserID := user.InvGateUserID
 	if invgateUserID == 0 {
 		s.logger.WithField("creatorEmail", creatorEmail).Error("user has no invgate_user_id")
@@ -38,6 +47,14 @@ func (s *service) CreateTicket(ctx context.Context, req TicketRequest, creatorEm
 		)
 	}
 
+	if t := tenant.FromContext(ctx); t != nil && !t.IsCategoryAllowed(req.CategoryID) {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"category is not available",
+			nil,
+		)
+	}
+
 	payload := invgate.CreateTicketPayload{
 		SourceID:    req.SourceID,
 		CreatorID:   invgateUserID,
@@ -88,7 +105,10 @@ func (s *service) CreateTicket(ctx context.Context, req TicketRequest, creatorEm
 		invGateID = ""
 	}
 
+	s.recordAttachments(ctx, invGateID, extractAttachmentIDs(invgateResp["attachments"]))
+
 	ticket := &Ticket{
+		TenantID:     tenant.IDFromContext(ctx),
 		InvGateID:    invGateID,
 		SourceID:     req.SourceID,
 		CreatorID:    invgateUserID,
@@ -102,6 +122,7 @@ func (s *service) CreateTicket(ctx context.Context, req TicketRequest, creatorEm
 		CreatedBy:    creatorEmail,
 		UpdatedBy:    creatorEmail,
 	}
+	applyDetail(ticket, invgateResp)
 
 	if err := s.repository.Create(ctx, ticket); err != nil {
 		s.logger.WithError(err).
@@ -119,6 +140,8 @@ func (s *service) CreateTicket(ctx context.Context, req TicketRequest, creatorEm
 		)
 	}
 
+	s.recordEvent(ctx, invGateID, EventCreated, creatorEmail, createdFields(ticket))
+
 	s.logger.WithFields(logrus.Fields{
 		"invGateID":    invGateID,
 		"title":        req.Title,
@@ -0,0 +1,207 @@
+package ticket
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"werk-ticketing/internal/errors"
+)
+
+const (
+	ticketCursorScope = "tickets"
+
+	defaultCommentPageSize = 20
+	maxCommentPageSize     = 100
+)
+
+// getTicketsByCursor returns the page of tickets next to a cursor. Unlike
+// offset pages it does not count the matching tickets.
+func (s *service) getTicketsByCursor(ctx context.Context, query ListTicketsQuery, filter ListFilter, limit int) (map[string]interface{}, error) {
+	from, err := s.cursors.decode(ticketCursorScope, query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Sort != SortCreatedAt {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"cursor pagination requires sort=created_at",
+			nil,
+		)
+	}
+
+	// One extra ticket tells whether there is another page in the walking direction
+	localTickets, err := s.repository.ListAfter(ctx, filter, from.keyset(), from.Backward, limit+1)
+	if err != nil {
+		s.logger.WithError(err).
+			WithField("creatorID", query.CreatorEmail).
+			Error("failed to get tickets from repository")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to fetch tickets from database",
+			err,
+		)
+	}
+
+	hasMore := len(localTickets) > limit
+	if hasMore {
+		if from.Backward {
+			localTickets = localTickets[1:]
+		} else {
+			localTickets = localTickets[:limit]
+		}
+	}
+	hasNext, hasPrev := pageDirections(from, hasMore)
+	nextCursor, prevCursor := s.ticketCursors(filter, from, localTickets, hasNext, hasPrev)
+
+	return map[string]interface{}{
+		"data": s.toListItems(ctx, localTickets),
+		"pagination": map[string]interface{}{
+			"limit":       limit,
+			"has_next":    hasNext,
+			"has_prev":    hasPrev,
+			"next_cursor": nextCursor,
+			"prev_cursor": prevCursor,
+		},
+	}, nil
+}
+
+// ticketCursors returns the cursors around a page of tickets. They are only
+// offered for the creation time sort, the order cursors are keyed on.
+func (s *service) ticketCursors(filter ListFilter, from *cursor, tickets []*Ticket, hasNext, hasPrev bool) (next, prev interface{}) {
+	if filter.Sort != SortCreatedAt {
+		return nil, nil
+	}
+
+	var first, last *cursor
+	if len(tickets) > 0 {
+		first = newCursor(ticketCursorScope, tickets[0].CreatedAt, tickets[0].ID)
+		last = newCursor(ticketCursorScope, tickets[len(tickets)-1].CreatedAt, tickets[len(tickets)-1].ID)
+	}
+	return s.cursors.around(from, first, last, hasNext, hasPrev)
+}
+
+// pageDirections tells which neighbour pages exist for a page read from a
+// cursor. The page the cursor came from always exists.
+func pageDirections(from *cursor, hasMore bool) (hasNext, hasPrev bool) {
+	if from.Backward {
+		return true, hasMore
+	}
+	return hasMore, true
+}
+
+// paginateComments replaces the comments of an InvGate comment response with
+// one page, oldest first. InvGate has no paging for comments, so they are
+// fetched as a whole and the cursor is applied here; responses are still
+// bounded by maxCommentPageSize.
+func (s *service) paginateComments(resp map[string]interface{}, ticketID int, rawCursor string, limit int) error {
+	scope := "comments:" + strconv.Itoa(ticketID)
+
+	var from *cursor
+	if rawCursor != "" {
+		var err error
+		if from, err = s.cursors.decode(scope, rawCursor); err != nil {
+			return err
+		}
+	}
+	if limit < 1 {
+		limit = defaultCommentPageSize
+	}
+	if limit > maxCommentPageSize {
+		limit = maxCommentPageSize
+	}
+
+	items, _ := resp["data"].([]interface{})
+	comments := make([]commentEntry, 0, len(items))
+	for _, item := range items {
+		comment, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		entry := commentEntry{comment: comment}
+		if at := parseInvGateTime(comment["created_at"]); at != nil {
+			entry.createdAt = *at
+		}
+		entry.id, _ = convertToString(comment["id"])
+		comments = append(comments, entry)
+	}
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].before(comments[j].createdAt, comments[j].id)
+	})
+
+	// start and end delimit the page within the sorted comments
+	start, end := 0, len(comments)
+	hasMore := false
+	if from == nil {
+		hasMore = end > limit
+		if hasMore {
+			end = limit
+		}
+	} else {
+		at := time.Unix(0, from.CreatedAt)
+		pos := sort.Search(len(comments), func(i int) bool {
+			return !comments[i].before(at, from.ID)
+		})
+		if from.Backward {
+			end = pos
+			start = max(end-limit, 0)
+			hasMore = start > 0
+		} else {
+			// Skip the comment at the cursor itself
+			if pos < len(comments) && comments[pos].createdAt.Equal(at) && comments[pos].id == from.ID {
+				pos++
+			}
+			start = pos
+			end = min(start+limit, len(comments))
+			hasMore = end < len(comments)
+		}
+	}
+
+	hasNext, hasPrev := hasMore, false
+	if from != nil {
+		hasNext, hasPrev = pageDirections(from, hasMore)
+	}
+
+	page := make([]interface{}, 0, end-start)
+	var first, last *cursor
+	for i, entry := range comments[start:end] {
+		page = append(page, entry.comment)
+		c := newCursor(scope, entry.createdAt, entry.id)
+		if i == 0 {
+			first = c
+		}
+		last = c
+	}
+	nextCursor, prevCursor := s.cursors.around(from, first, last, hasNext, hasPrev)
+
+	resp["data"] = page
+	resp["pagination"] = map[string]interface{}{
+		"limit":       limit,
+		"has_next":    hasNext,
+		"has_prev":    hasPrev,
+		"next_cursor": nextCursor,
+		"prev_cursor": prevCursor,
+	}
+	return nil
+}
+
+type commentEntry struct {
+	comment   map[string]interface{}
+	createdAt time.Time
+	id        string
+}
+
+// before orders comments by creation time, then by ID. InvGate IDs are
+// numeric and compared as numbers.
+func (e commentEntry) before(createdAt time.Time, id string) bool {
+	if !e.createdAt.Equal(createdAt) {
+		return e.createdAt.Before(createdAt)
+	}
+	a, errA := strconv.Atoi(e.id)
+	b, errB := strconv.Atoi(id)
+	if errA == nil && errB == nil {
+		return a < b
+	}
+	return e.id < id
+}
@@ -0,0 +1,49 @@
+package ticket
+
+import "testing"
+
+func commentResponse() map[string]interface{} {
+	return map[string]interface{}{"data": []interface{}{
+		map[string]interface{}{"id": 3, "created_at": "2025-01-03 10:00:00"},
+		map[string]interface{}{"id": 1, "created_at": "2025-01-01 10:00:00"},
+		map[string]interface{}{"id": 2, "created_at": "2025-01-02 10:00:00"},
+	}}
+}
+
+func TestPaginateCommentsFollowsCursor(t *testing.T) {
+	s := &service{cursors: cursorCodec{secret: []byte("test")}}
+
+	// Without a limit the default page size is used
+	resp := commentResponse()
+	if err := s.paginateComments(resp, 7, "", 0); err != nil {
+		t.Fatalf("paginateComments: %v", err)
+	}
+	if page := resp["data"].([]interface{}); len(page) != 3 {
+		t.Fatalf("first page has %d comments, want 3", len(page))
+	}
+
+	resp = commentResponse()
+	if err := s.paginateComments(resp, 7, "", 2); err != nil {
+		t.Fatalf("paginateComments: %v", err)
+	}
+	page := resp["data"].([]interface{})
+	pagination := resp["pagination"].(map[string]interface{})
+	if len(page) != 2 || page[0].(map[string]interface{})["id"] != 1 || pagination["has_next"] != true {
+		t.Fatalf("first page = %v, pagination %v", page, pagination)
+	}
+
+	next, _ := pagination["next_cursor"].(string)
+	resp = commentResponse()
+	if err := s.paginateComments(resp, 7, next, 2); err != nil {
+		t.Fatalf("paginateComments with cursor: %v", err)
+	}
+	page = resp["data"].([]interface{})
+	if len(page) != 1 || page[0].(map[string]interface{})["id"] != 3 {
+		t.Fatalf("second page = %v", page)
+	}
+
+	// Cursors of one ticket are rejected for another
+	if err := s.paginateComments(commentResponse(), 8, next, 2); err == nil {
+		t.Fatal("cursor accepted for another ticket")
+	}
+}
@@ -0,0 +1,76 @@
+package ticket
+
+import (
+	"context"
+	"sync"
+
+	"werk-ticketing/internal/constants"
+)
+
+// fetchTicketDetails loads the InvGate detail of several tickets. One batch
+// request is tried first; tickets it did not return are fetched one by one
+// by a bounded number of workers, each call with its own timeout. Tickets
+// that could not be loaded are reported in failed.
+func (s *service) fetchTicketDetails(ctx context.Context, invGateIDs []string) (details map[string]map[string]interface{}, failed map[string]error) {
+	details = make(map[string]map[string]interface{}, len(invGateIDs))
+	failed = make(map[string]error)
+	if len(invGateIDs) == 0 {
+		return details, failed
+	}
+
+	batchCtx, cancel := context.WithTimeout(ctx, constants.TicketListBatchTimeout)
+	batch, err := s.client.GetTicketsByIDs(batchCtx, invGateIDs)
+	cancel()
+	if err != nil {
+		s.logger.WithError(err).WithField("count", len(invGateIDs)).
+			Warn("failed to batch fetch tickets from InvGate, fetching one by one")
+	}
+
+	var missing []string
+	for _, id := range invGateIDs {
+		if detail, ok := batch[id]; ok {
+			details[id] = detail
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return details, failed
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan string)
+
+	workers := constants.TicketDetailConcurrency
+	if len(missing) < workers {
+		workers = len(missing)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				callCtx, cancel := context.WithTimeout(ctx, constants.TicketDetailTimeout)
+				detail, err := s.client.GetTicketDetail(callCtx, id)
+				cancel()
+
+				mu.Lock()
+				if err != nil {
+					failed[id] = err
+				} else {
+					details[id] = detail
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, id := range missing {
+		jobs <- id
+	}
+	close(jobs)
+	wg.Wait()
+
+	return details, failed
+}
@@ -0,0 +1,95 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/tenant"
+)
+
+// ExportUserTickets collects the local tickets of a user together with their
+// InvGate detail, comments and attachment IDs. InvGate failures for a single
+// ticket are recorded on that ticket so the export can still be completed.
+// ctx must be bound to the tenant of the user.
+func (s *service) ExportUserTickets(ctx context.Context, creatorEmail string) ([]TicketExport, error) {
+	tickets, err := s.repository.GetByCreatorEmail(ctx, tenant.IDFromContext(ctx), creatorEmail)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get tickets for export")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to fetch tickets from database",
+			err,
+		)
+	}
+
+	exports := make([]TicketExport, 0, len(tickets))
+	for _, t := range tickets {
+		export := TicketExport{Ticket: *t}
+		attachmentIDs := make(map[string]bool)
+
+		detail, err := s.client.GetTicketDetail(ctx, t.InvGateID)
+		if err != nil {
+			export.Errors = append(export.Errors, fmt.Sprintf("detail: %v", err))
+		} else {
+			export.Detail = detail
+			for _, id := range extractAttachmentIDs(detail["attachments"]) {
+				attachmentIDs[id] = true
+			}
+		}
+
+		if requestID, err := strconv.Atoi(t.InvGateID); err == nil {
+			comments, err := s.client.GetTicketComments(ctx, requestID)
+			if err != nil {
+				export.Errors = append(export.Errors, fmt.Sprintf("comments: %v", err))
+			} else {
+				export.Comments = comments
+				if items, ok := comments["data"].([]interface{}); ok {
+					for _, item := range items {
+						if comment, ok := item.(map[string]interface{}); ok {
+							for _, id := range extractCommentAttachmentIDs(comment) {
+								attachmentIDs[id] = true
+							}
+						}
+					}
+				}
+			}
+		}
+
+		recorded, err := s.repository.GetAttachmentsByInvGateID(ctx, t.TenantID, t.InvGateID)
+		if err != nil {
+			export.Errors = append(export.Errors, fmt.Sprintf("attachments: %v", err))
+		}
+		for _, a := range recorded {
+			attachmentIDs[a.AttachmentID] = true
+		}
+
+		export.AttachmentIDs = make([]string, 0, len(attachmentIDs))
+		for id := range attachmentIDs {
+			export.AttachmentIDs = append(export.AttachmentIDs, id)
+		}
+
+		export.Events, err = s.repository.ListEvents(ctx, t.TenantID, t.InvGateID)
+		if err != nil {
+			export.Errors = append(export.Errors, fmt.Sprintf("events: %v", err))
+		}
+		exports = append(exports, export)
+	}
+
+	return exports, nil
+}
+
+// AnonymizeCreator removes the email of a deleted user from the local tickets
+// of its tenant. The ticket content itself stays, it is also kept in InvGate.
+func (s *service) AnonymizeCreator(ctx context.Context, tenantID, creatorEmail, replacement string) error {
+	if err := s.repository.AnonymizeCreator(ctx, tenantID, creatorEmail, replacement); err != nil {
+		s.logger.WithError(err).Error("failed to anonymize ticket creator")
+		return errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to anonymize tickets",
+			err,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/tenant"
+)
+
+// maxSearchTerms caps the words of a full-text search.
+const maxSearchTerms = 10
+
+// buildListFilter validates the ticket list query and turns it into a
+// repository filter for the tenant in ctx.
+func buildListFilter(ctx context.Context, query ListTicketsQuery) (ListFilter, error) {
+	filter := ListFilter{
+		TenantID:     tenant.IDFromContext(ctx),
+		CreatorEmail: query.CreatorEmail,
+		Query:        fullTextQuery(query.Query),
+		Sort:         query.Sort,
+		Desc:         true,
+	}
+
+	var err error
+	if filter.StatusIDs, err = parseIDList("status_id", query.Status); err != nil {
+		return ListFilter{}, err
+	}
+	if filter.CategoryIDs, err = parseIDList("category_id", query.Category); err != nil {
+		return ListFilter{}, err
+	}
+	if filter.TypeIDs, err = parseIDList("type_id", query.Type); err != nil {
+		return ListFilter{}, err
+	}
+	if filter.PriorityIDs, err = parseIDList("priority_id", query.Priority); err != nil {
+		return ListFilter{}, err
+	}
+	if filter.CreatedFrom, err = parseTimeBound("created_from", query.CreatedFrom, false); err != nil {
+		return ListFilter{}, err
+	}
+	if filter.CreatedTo, err = parseTimeBound("created_to", query.CreatedTo, true); err != nil {
+		return ListFilter{}, err
+	}
+	if filter.UpdatedFrom, err = parseTimeBound("updated_from", query.UpdatedFrom, false); err != nil {
+		return ListFilter{}, err
+	}
+	if filter.UpdatedTo, err = parseTimeBound("updated_to", query.UpdatedTo, true); err != nil {
+		return ListFilter{}, err
+	}
+
+	switch filter.Sort {
+	case "":
+		filter.Sort = SortCreatedAt
+		if filter.Query != "" {
+			filter.Sort = SortRelevance
+		}
+	case SortRelevance:
+		if filter.Query == "" {
+			return ListFilter{}, errors.NewAppError(
+				errors.ErrCodeInvalidInput,
+				"sort by relevance requires a search query",
+				nil,
+			)
+		}
+	default:
+		if _, ok := sortColumns[filter.Sort]; !ok {
+			return ListFilter{}, errors.NewAppError(
+				errors.ErrCodeInvalidInput,
+				"sort must be one of: created_at, updated_at, status, category, type, priority, relevance",
+				nil,
+			)
+		}
+	}
+
+	switch strings.ToLower(query.Order) {
+	case "", "desc":
+	case "asc":
+		filter.Desc = false
+	default:
+		return ListFilter{}, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			"order must be one of: asc, desc",
+			nil,
+		)
+	}
+
+	return filter, nil
+}
+
+// parseIDList reads a comma separated list of positive IDs.
+func parseIDList(name, raw string) ([]int, error) {
+	var ids []int
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.Atoi(part)
+		if err != nil || id <= 0 {
+			return nil, errors.NewAppError(
+				errors.ErrCodeInvalidInput,
+				fmt.Sprintf("%s must be a comma separated list of positive integers", name),
+				nil,
+			)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseTimeBound reads a date range bound. A plain date used as upper bound
+// covers the whole day, since upper bounds are exclusive.
+func parseTimeBound(name, raw string, upper bool) (*time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		t = t.UTC()
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return nil, errors.NewAppError(
+			errors.ErrCodeInvalidInput,
+			fmt.Sprintf("%s must be a date (YYYY-MM-DD) or an RFC 3339 timestamp", name),
+			nil,
+		)
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// fullTextQuery turns free text into a MySQL boolean mode query in which
+// every word must match, as a prefix. Operators typed by the user are dropped.
+func fullTextQuery(q string) string {
+	words := strings.FieldsFunc(q, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > maxSearchTerms {
+		words = words[:maxSearchTerms]
+	}
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, "+"+word+"*")
+	}
+	return strings.Join(terms, " ")
+}
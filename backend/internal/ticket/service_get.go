@@ -2,15 +2,21 @@ package ticket
 
 import (
 	"context"
-	"fmt"
 
 	"werk-ticketing/internal/errors"
 )
 
-func (s *service) GetTickets(ctx context.Context, creatorID string, page, limit int) (map[string]interface{}, error) {
+func (s *service) GetTickets(ctx context.Context, query ListTicketsQuery) (map[string]interface{}, error) {
+	filter, err := buildListFilter(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := query.Page
 	if page < 1 {
 		page = 1
 	}
+	limit := query.Limit
 	if limit < 1 {
 		limit = 2
 	}
@@ -18,12 +24,16 @@ func (s *service) GetTickets(ctx context.Context, creatorID string, page, limit
 		limit = 100
 	}
 
+	if query.Cursor != "" {
+		return s.getTicketsByCursor(ctx, query, filter, limit)
+	}
+
 	offset := (page - 1) * limit
 
-	totalCount, err := s.repository.CountByCreatorEmail(ctx, creatorID)
+	totalCount, err := s.repository.Count(ctx, filter)
 	if err != nil {
 		s.logger.WithError(err).
-			WithField("creatorID", creatorID).
+			WithField("creatorID", query.CreatorEmail).
 			Error("failed to count tickets from repository")
 		return nil, errors.NewAppError(
 			errors.ErrCodeInternal,
@@ -32,10 +42,10 @@ func (s *service) GetTickets(ctx context.Context, creatorID string, page, limit
 		)
 	}
 
-	localTickets, err := s.repository.GetByCreatorEmailPaginated(ctx, creatorID, limit, offset)
+	localTickets, err := s.repository.List(ctx, filter, limit, offset)
 	if err != nil {
 		s.logger.WithError(err).
-			WithField("creatorID", creatorID).
+			WithField("creatorID", query.CreatorEmail).
 			Error("failed to get tickets from repository")
 		return nil, errors.NewAppError(
 			errors.ErrCodeInternal,
@@ -44,39 +54,16 @@ func (s *service) GetTickets(ctx context.Context, creatorID string, page, limit
 		)
 	}
 
-	var tickets []map[string]interface{}
-	for _, localTicket := range localTickets {
-		if localTicket.InvGateID == "" {
-			s.logger.WithField("ticketID", localTicket.ID).
-				Warn("skipping ticket without InvGate ID")
-			continue
-		}
-
-		ticketDetail, err := s.client.GetTicketDetail(ctx, localTicket.InvGateID)
-		if err != nil {
-			s.logger.WithError(err).
-				WithField("invGateID", localTicket.InvGateID).
-				Warn("failed to get ticket detail from InvGate, skipping")
-			continue
-		}
-
-		if statusID, ok := ticketDetail["status_id"]; ok {
-			ticketDetail["status"] = getStatusName(statusID)
-		}
-
-		ticketDetail["inv_gate_id"] = localTicket.InvGateID
-		ticketDetail["wrk_ticket_id"] = fmt.Sprintf("WRK-#%s", localTicket.InvGateID)
-
-		tickets = append(tickets, ticketDetail)
-	}
-
 	totalPages := int((totalCount + int64(limit) - 1) / int64(limit))
 	if totalPages == 0 {
 		totalPages = 1
 	}
 
+	// Cursors let clients switch to cursor pagination from any offset page
+	nextCursor, prevCursor := s.ticketCursors(filter, nil, localTickets, page < totalPages, page > 1)
+
 	return map[string]interface{}{
-		"data": tickets,
+		"data": s.toListItems(ctx, localTickets),
 		"pagination": map[string]interface{}{
 			"page":        page,
 			"limit":       limit,
@@ -84,11 +71,18 @@ func (s *service) GetTickets(ctx context.Context, creatorID string, page, limit
 			"total_pages": totalPages,
 			"has_next":    page < totalPages,
 			"has_prev":    page > 1,
+			"next_cursor": nextCursor,
+			"prev_cursor": prevCursor,
 		},
 	}, nil
 }
 
-func (s *service) GetTicketDetail(ctx context.Context, ticketID string) (map[string]interface{}, error) {
+func (s *service) GetTicketDetail(ctx context.Context, ticketID, requesterEmail string) (map[string]interface{}, error) {
+	t, err := s.authorizeTicketAccess(ctx, ticketID, requesterEmail)
+	if err != nil {
+		return nil, err
+	}
+
 	resp, err := s.client.GetTicketDetail(ctx, ticketID)
 	if err != nil {
 		s.logger.WithError(err).
@@ -102,8 +96,11 @@ func (s *service) GetTicketDetail(ctx context.Context, ticketID string) (map[str
 	}
 
 	if statusID, ok := resp["status_id"]; ok {
-		resp["status"] = getStatusName(statusID)
+		resp["status"] = s.meta.StatusName(ctx, statusID)
 	}
 
+	s.recordAttachments(ctx, ticketID, extractAttachmentIDs(resp["attachments"]))
+	s.refreshReadModel(ctx, t, resp)
+
 	return resp, nil
 }
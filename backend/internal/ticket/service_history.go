@@ -0,0 +1,138 @@
+package ticket
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/errors"
+	"werk-ticketing/internal/requestinfo"
+	"werk-ticketing/internal/tenant"
+)
+
+// FieldChange is the old and new value of a ticket field. From is nil when
+// the old value is not known locally.
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// GetTicketHistory returns the events of a ticket, oldest first. The client
+// IP of the events is only included when includeSource is set.
+func (s *service) GetTicketHistory(ctx context.Context, ticketID, requesterEmail string, includeSource bool) ([]TicketEventResponse, error) {
+	if _, err := s.authorizeTicketAccess(ctx, ticketID, requesterEmail); err != nil {
+		return nil, err
+	}
+
+	events, err := s.repository.ListEvents(ctx, tenant.IDFromContext(ctx), ticketID)
+	if err != nil {
+		s.logger.WithError(err).WithField("invGateID", ticketID).Error("failed to list ticket events")
+		return nil, errors.NewAppError(
+			errors.ErrCodeInternal,
+			"failed to fetch ticket history",
+			err,
+		)
+	}
+
+	resp := make([]TicketEventResponse, 0, len(events))
+	for _, e := range events {
+		item := TicketEventResponse{
+			ID:         e.ID,
+			Type:       e.Type,
+			ActorEmail: e.ActorEmail,
+			RequestID:  e.RequestID,
+			CreatedAt:  e.CreatedAt,
+		}
+		if e.Details != "" {
+			item.Details = json.RawMessage(e.Details)
+		}
+		if includeSource {
+			item.IPAddress = e.IPAddress
+		}
+		resp = append(resp, item)
+	}
+	return resp, nil
+}
+
+// recordEvent adds an event to the history of a ticket. The change was
+// already made in InvGate, so failures are only logged.
+func (s *service) recordEvent(ctx context.Context, invGateID, eventType, actorEmail string, details map[string]interface{}) {
+	if invGateID == "" {
+		return
+	}
+
+	info := requestinfo.FromContext(ctx)
+	event := &TicketEvent{
+		TenantID:   tenant.IDFromContext(ctx),
+		InvGateID:  invGateID,
+		Type:       eventType,
+		ActorEmail: actorEmail,
+		RequestID:  info.RequestID,
+		IPAddress:  info.IPAddress,
+	}
+	if len(details) > 0 {
+		encoded, err := json.Marshal(details)
+		if err != nil {
+			s.logger.WithError(err).WithField("invGateID", invGateID).Warn("failed to encode ticket event details")
+		} else {
+			event.Details = string(encoded)
+		}
+	}
+
+	if err := s.repository.CreateEvent(ctx, event); err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"invGateID": invGateID,
+			"type":      eventType,
+		}).Error("failed to record ticket event")
+	}
+}
+
+// createdFields lists the initial values of a new ticket.
+func createdFields(t *Ticket) map[string]interface{} {
+	return map[string]interface{}{
+		"title":       FieldChange{To: t.Title},
+		"category_id": FieldChange{To: t.CategoryID},
+		"type_id":     FieldChange{To: t.TypeID},
+		"priority_id": FieldChange{To: t.PriorityID},
+	}
+}
+
+// updateChanges compares an update with the local state of the ticket and
+// returns the fields it changes. date_ocurred is not kept locally, so it is
+// reported with an unknown old value.
+func updateChanges(t *Ticket, req TicketUpdateRequest) map[string]interface{} {
+	changes := make(map[string]interface{})
+	diffInt := func(field string, from int, to *int) {
+		if to != nil && *to != from {
+			changes[field] = FieldChange{From: from, To: *to}
+		}
+	}
+	diffString := func(field, from string, to *string) {
+		if to != nil && *to != from {
+			changes[field] = FieldChange{From: from, To: *to}
+		}
+	}
+
+	diffInt("source_id", t.SourceID, req.SourceID)
+	diffInt("creator_id", t.CreatorID, req.CreatorID)
+	diffInt("customer_id", t.CustomerID, req.CustomerID)
+	diffInt("category_id", t.CategoryID, req.CategoryID)
+	diffInt("type_id", t.TypeID, req.TypeID)
+	diffInt("priority_id", t.PriorityID, req.PriorityID)
+	diffString("title", t.Title, req.Title)
+	diffString("description", t.Description, req.Description)
+	if req.DateOcurred != nil {
+		changes["date_ocurred"] = FieldChange{To: *req.DateOcurred}
+	}
+
+	return changes
+}
+
+// commentDetails identifies the comment of a comment event.
+func commentDetails(resp map[string]interface{}) map[string]interface{} {
+	if id, ok := resp["id"]; ok && id != nil {
+		return map[string]interface{}{"comment_id": id}
+	}
+	return nil
+}
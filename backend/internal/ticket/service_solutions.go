@@ -2,15 +2,17 @@ package ticket
 
 import (
 	"context"
+	"strconv"
 	"strings"
 
 	"github.com/sirupsen/logrus"
 
 	"werk-ticketing/internal/errors"
 	"werk-ticketing/internal/invgate"
+	"werk-ticketing/internal/tenant"
 )
 
-func (s *service) UpdateTicketSolution(ctx context.Context, req TicketSolutionRequest) (map[string]interface{}, error) {
+func (s *service) UpdateTicketSolution(ctx context.Context, req TicketSolutionRequest, requesterEmail string) (map[string]interface{}, error) {
 	if req.RequestID <= 0 {
 		return nil, errors.NewAppError(
 			errors.ErrCodeInvalidInput,
@@ -35,6 +37,10 @@ func (s *service) UpdateTicketSolution(ctx context.Context, req TicketSolutionRe
 		)
 	}
 
+	if _, err := s.authorizeTicketAccess(ctx, strconv.Itoa(req.RequestID), requesterEmail); err != nil {
+		return nil, err
+	}
+
 	resp, err := s.client.SolutionAccept(ctx, invgate.SolutionAcceptPayload{
 		ID:      req.RequestID,
 		Comment: req.Comment,
@@ -52,10 +58,15 @@ func (s *service) UpdateTicketSolution(ctx context.Context, req TicketSolutionRe
 		)
 	}
 
+	s.markStale(ctx, tenant.IDFromContext(ctx), strconv.Itoa(req.RequestID))
+	s.recordEvent(ctx, strconv.Itoa(req.RequestID), EventSolutionAccepted, requesterEmail, map[string]interface{}{
+		"rating": req.Rating,
+	})
+
 	return resp, nil
 }
 
-func (s *service) RejectTicketSolution(ctx context.Context, req TicketSolutionRejectRequest) (map[string]interface{}, error) {
+func (s *service) RejectTicketSolution(ctx context.Context, req TicketSolutionRejectRequest, requesterEmail string) (map[string]interface{}, error) {
 	if req.RequestID <= 0 {
 		return nil, errors.NewAppError(
 			errors.ErrCodeInvalidInput,
@@ -72,6 +83,10 @@ func (s *service) RejectTicketSolution(ctx context.Context, req TicketSolutionRe
 		)
 	}
 
+	if _, err := s.authorizeTicketAccess(ctx, strconv.Itoa(req.RequestID), requesterEmail); err != nil {
+		return nil, err
+	}
+
 	resp, err := s.client.SolutionReject(ctx, invgate.SolutionRejectPayload{
 		ID:      req.RequestID,
 		Comment: req.Comment,
@@ -87,6 +102,9 @@ func (s *service) RejectTicketSolution(ctx context.Context, req TicketSolutionRe
 		)
 	}
 
+	s.markStale(ctx, tenant.IDFromContext(ctx), strconv.Itoa(req.RequestID))
+	s.recordEvent(ctx, strconv.Itoa(req.RequestID), EventSolutionRejected, requesterEmail, nil)
+
 	return resp, nil
 }
 
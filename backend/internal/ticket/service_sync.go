@@ -0,0 +1,270 @@
+package ticket
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/constants"
+)
+
+// runSync periodically refreshes the read model of local tickets from InvGate.
+func (s *service) runSync(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		synced, err := s.syncReadModel(ctx)
+		cancel()
+
+		if err != nil {
+			s.logger.WithError(err).Warn("failed to sync tickets from InvGate")
+			continue
+		}
+		if synced > 0 {
+			s.logger.WithField("tickets", synced).Debug("synced tickets from InvGate")
+		}
+	}
+}
+
+// syncReadModel refreshes one batch of tickets that are due for a sync and
+// returns how many were refreshed.
+func (s *service) syncReadModel(ctx context.Context) (int, error) {
+	closedAfter := time.Now().UTC().Add(-constants.TicketSyncClosedGrace)
+	tickets, err := s.repository.ListForSync(ctx, closedAfter, constants.TicketSyncBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	byTenant := make(map[string][]*Ticket)
+	for _, t := range tickets {
+		byTenant[t.TenantID] = append(byTenant[t.TenantID], t)
+	}
+
+	synced := 0
+	for tenantID, tenantTickets := range byTenant {
+		tenantCtx, err := s.tenants.WithTenant(ctx, tenantID)
+		if err != nil {
+			s.logger.WithError(err).WithField("tenantID", tenantID).Warn("skipping ticket sync of unknown tenant")
+			continue
+		}
+
+		invGateIDs := make([]string, 0, len(tenantTickets))
+		for _, t := range tenantTickets {
+			invGateIDs = append(invGateIDs, t.InvGateID)
+		}
+		details, failed := s.fetchTicketDetails(tenantCtx, invGateIDs)
+
+		for _, t := range tenantTickets {
+			now := time.Now().UTC()
+			if detail, ok := details[t.InvGateID]; ok {
+				previousUpdate := t.InvGateUpdatedAt
+				applyDetail(t, detail)
+				if t.LastCommentAt == nil || !sameTime(previousUpdate, t.InvGateUpdatedAt) {
+					s.refreshLastComment(tenantCtx, t)
+				}
+				synced++
+			} else {
+				// Retried after the other tickets had their turn
+				s.logger.WithError(failed[t.InvGateID]).
+					WithField("invGateID", t.InvGateID).
+					Warn("failed to sync ticket from InvGate")
+			}
+
+			t.SyncedAt = &now
+			if err := s.repository.UpdateReadModel(ctx, t); err != nil {
+				s.logger.WithError(err).WithField("ticketID", t.ID).Error("failed to save ticket read model")
+			}
+		}
+	}
+	return synced, nil
+}
+
+// refreshReadModel stores the state of an InvGate detail fetched for another
+// purpose. The sync time is left alone so the sync worker still refreshes
+// the last comment time.
+func (s *service) refreshReadModel(ctx context.Context, t *Ticket, detail map[string]interface{}) {
+	applyDetail(t, detail)
+	if err := s.repository.UpdateReadModel(ctx, t); err != nil {
+		s.logger.WithError(err).WithField("ticketID", t.ID).Warn("failed to save ticket read model")
+	}
+}
+
+// markStale schedules a ticket changed through this service for the next sync.
+func (s *service) markStale(ctx context.Context, tenantID, invGateID string) {
+	if err := s.repository.MarkStale(ctx, tenantID, invGateID); err != nil {
+		s.logger.WithError(err).WithField("invGateID", invGateID).Warn("failed to mark ticket for sync")
+	}
+}
+
+// refreshLastComment sets the time of the newest comment of the ticket.
+func (s *service) refreshLastComment(ctx context.Context, t *Ticket) {
+	requestID, err := strconv.Atoi(t.InvGateID)
+	if err != nil {
+		return
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, constants.TicketDetailTimeout)
+	resp, err := s.client.GetTicketComments(callCtx, requestID)
+	cancel()
+	if err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"invGateID": t.InvGateID,
+		}).Warn("failed to get ticket comments for sync")
+		return
+	}
+
+	items, _ := resp["data"].([]interface{})
+	for _, item := range items {
+		comment, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if at := parseInvGateTime(comment["created_at"]); at != nil && (t.LastCommentAt == nil || at.After(*t.LastCommentAt)) {
+			t.LastCommentAt = at
+		}
+	}
+}
+
+// applyDetail copies the state of an InvGate incident onto the read model.
+// Fields missing from the detail are left unchanged.
+func applyDetail(t *Ticket, detail map[string]interface{}) {
+	if title, ok := detail["title"].(string); ok && title != "" {
+		t.Title = title
+	}
+	if description, ok := detail["description"].(string); ok {
+		t.Description = description
+	}
+	if prettyID, ok := detail["pretty_id"]; ok && prettyID != nil {
+		t.PrettyID, _ = convertToString(prettyID)
+	}
+	setInt(&t.CategoryID, detail, "category_id")
+	setInt(&t.TypeID, detail, "type_id")
+	setInt(&t.PriorityID, detail, "priority_id")
+	setInt(&t.StatusID, detail, "status_id")
+	setInt(&t.AssignedID, detail, "assigned_id")
+	setInt(&t.AssignedGroupID, detail, "assigned_group_id")
+
+	if _, ok := detail["solved_at"]; ok {
+		t.SolvedAt = parseInvGateTime(detail["solved_at"])
+	}
+	if _, ok := detail["closed_at"]; ok {
+		t.ClosedAt = parseInvGateTime(detail["closed_at"])
+	}
+	if at := parseInvGateTime(detail["last_update"]); at != nil {
+		t.InvGateUpdatedAt = at
+	}
+}
+
+// setInt assigns a numeric field of an InvGate detail when it is present.
+// null resets it to 0, e.g. when the ticket is no longer assigned.
+func setInt(dst *int, detail map[string]interface{}, key string) {
+	v, ok := detail[key]
+	if !ok {
+		return
+	}
+	switch n := v.(type) {
+	case nil:
+		*dst = 0
+	case float64:
+		*dst = int(n)
+	case string:
+		if parsed, err := strconv.Atoi(n); err == nil {
+			*dst = parsed
+		}
+	}
+}
+
+// parseInvGateTime reads an InvGate timestamp, sent either as UNIX seconds or
+// as "2006-01-02 15:04:05" in UTC. Empty values and zero yield nil.
+func parseInvGateTime(v interface{}) *time.Time {
+	var at time.Time
+	switch val := v.(type) {
+	case float64:
+		if val <= 0 {
+			return nil
+		}
+		at = time.Unix(int64(val), 0)
+	case string:
+		val = strings.TrimSpace(val)
+		if val == "" || val == "0" {
+			return nil
+		}
+		if secs, err := strconv.ParseInt(val, 10, 64); err == nil {
+			at = time.Unix(secs, 0)
+			break
+		}
+		parsed, err := time.Parse("2006-01-02 15:04:05", val)
+		if err != nil {
+			return nil
+		}
+		at = parsed
+	default:
+		return nil
+	}
+	at = at.UTC()
+	return &at
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func (s *service) toListItems(ctx context.Context, tickets []*Ticket) []TicketListItem {
+	items := make([]TicketListItem, 0, len(tickets))
+	for _, t := range tickets {
+		items = append(items, s.toListItem(ctx, t))
+	}
+	return items
+}
+
+// toListItem describes a ticket by its read model.
+func (s *service) toListItem(ctx context.Context, t *Ticket) TicketListItem {
+	id, _ := strconv.Atoi(t.InvGateID)
+	item := TicketListItem{
+		ID:              id,
+		PrettyID:        t.PrettyID,
+		InvGateID:       t.InvGateID,
+		Title:           t.Title,
+		Description:     t.Description,
+		SourceID:        t.SourceID,
+		CreatorID:       t.CreatorID,
+		CustomerID:      t.CustomerID,
+		CategoryID:      t.CategoryID,
+		TypeID:          t.TypeID,
+		PriorityID:      t.PriorityID,
+		StatusID:        t.StatusID,
+		AssignedID:      t.AssignedID,
+		AssignedGroupID: t.AssignedGroupID,
+		CreatorEmail:    t.CreatorEmail,
+		CreatedAt:       t.CreatedAt.Unix(),
+		LastUpdate:      unixSeconds(t.InvGateUpdatedAt),
+		SolvedAt:        unixSeconds(t.SolvedAt),
+		ClosedAt:        unixSeconds(t.ClosedAt),
+		LastCommentAt:   unixSeconds(t.LastCommentAt),
+		SyncedAt:        unixSeconds(t.SyncedAt),
+	}
+	if t.InvGateID != "" {
+		item.WrkTicketID = "WRK-#" + t.InvGateID
+	}
+	if t.StatusID > 0 {
+		item.Status = s.meta.StatusName(ctx, t.StatusID)
+	}
+	return item
+}
+
+// unixSeconds converts an optional time to UNIX seconds, the format InvGate
+// and the clients use for timestamps.
+func unixSeconds(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	secs := t.Unix()
+	return &secs
+}
@@ -2,14 +2,16 @@ package ticket
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/sirupsen/logrus"
 
 	"werk-ticketing/internal/errors"
 	"werk-ticketing/internal/invgate"
+	"werk-ticketing/internal/tenant"
 )
 
-func (s *service) UpdateTicket(ctx context.Context, ticketID int, req TicketUpdateRequest) (map[string]interface{}, error) {
+func (s *service) UpdateTicket(ctx context.Context, ticketID int, req TicketUpdateRequest, requesterEmail string) (map[string]interface{}, error) {
 	if ticketID <= 0 {
 		return nil, errors.NewAppError(
 			errors.ErrCodeInvalidInput,
@@ -18,6 +20,12 @@ func (s *service) UpdateTicket(ctx context.Context, ticketID int, req TicketUpda
 		)
 	}
 
+	t, err := s.authorizeTicketAccess(ctx, strconv.Itoa(ticketID), requesterEmail)
+	if err != nil {
+		return nil, err
+	}
+	changes := updateChanges(t, req)
+
 	payload := invgate.UpdateTicketPayload{
 		ID: ticketID,
 	}
@@ -62,6 +70,15 @@ func (s *service) UpdateTicket(ctx context.Context, ticketID int, req TicketUpda
 		)
 	}
 
+	// The local copy is the base of the diff of the next update
+	applyUpdate(t, req)
+	t.UpdatedBy = requesterEmail
+	if err := s.repository.ApplyUpdate(ctx, t); err != nil {
+		s.logger.WithError(err).WithField("ticketID", ticketID).Warn("failed to save ticket update locally")
+		s.markStale(ctx, tenant.IDFromContext(ctx), strconv.Itoa(ticketID))
+	}
+	s.recordEvent(ctx, strconv.Itoa(ticketID), EventUpdated, requesterEmail, changes)
+
 	return resp, nil
 }
 
@@ -87,3 +104,30 @@ func (s *service) GetInvGateUser(ctx context.Context, userID int) (map[string]in
 	return resp, nil
 }
 
+// applyUpdate copies the fields set in an update onto the local ticket.
+func applyUpdate(t *Ticket, req TicketUpdateRequest) {
+	if req.SourceID != nil {
+		t.SourceID = *req.SourceID
+	}
+	if req.CreatorID != nil {
+		t.CreatorID = *req.CreatorID
+	}
+	if req.CustomerID != nil {
+		t.CustomerID = *req.CustomerID
+	}
+	if req.CategoryID != nil {
+		t.CategoryID = *req.CategoryID
+	}
+	if req.TypeID != nil {
+		t.TypeID = *req.TypeID
+	}
+	if req.PriorityID != nil {
+		t.PriorityID = *req.PriorityID
+	}
+	if req.Title != nil {
+		t.Title = *req.Title
+	}
+	if req.Description != nil {
+		t.Description = *req.Description
+	}
+}
@@ -0,0 +1,44 @@
+package ticketmeta
+
+import (
+	"context"
+	"strings"
+
+	"werk-ticketing/internal/user"
+)
+
+// DefaultLanguage is used when a request does not ask for a language.
+const DefaultLanguage = user.LanguageEnglish
+
+type languageKey struct{}
+
+// WithLanguage returns a context whose localized names are served in lang.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+// LanguageFromContext returns the language set by WithLanguage, or DefaultLanguage.
+func LanguageFromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(languageKey{}).(string); ok && user.IsValidLanguage(lang) {
+		return lang
+	}
+	return DefaultLanguage
+}
+
+// ParseLanguage picks the language of a request from an explicit lang value,
+// e.g. a query parameter, or else from the first supported language of an
+// Accept-Language header.
+func ParseLanguage(lang, acceptLanguage string) string {
+	if lang = strings.ToLower(strings.TrimSpace(lang)); user.IsValidLanguage(lang) {
+		return lang
+	}
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		primary, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if user.IsValidLanguage(primary) {
+			return primary
+		}
+	}
+	return DefaultLanguage
+}
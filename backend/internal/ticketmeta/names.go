@@ -0,0 +1,83 @@
+package ticketmeta
+
+import (
+	"strings"
+
+	"werk-ticketing/internal/user"
+)
+
+// Names holds the name of an entry in every supported language.
+type Names struct {
+	ID string `json:"id"`
+	EN string `json:"en"`
+}
+
+// In returns the name in lang.
+func (n Names) In(lang string) string {
+	if lang == user.LanguageIndonesian && n.ID != "" {
+		return n.ID
+	}
+	return n.EN
+}
+
+// entry is a type, priority or status as loaded from InvGate.
+type entry struct {
+	ID    int
+	Names Names
+}
+
+// Built-in tables, served while InvGate cannot be reached.
+var (
+	defaultTypes = []entry{
+		{1, Names{"Insiden", "Incident"}},
+		{2, Names{"Permintaan Layanan", "Service Request"}},
+		{3, Names{"Pertanyaan", "Question"}},
+		{4, Names{"Masalah", "Problem"}},
+		{5, Names{"Perubahan", "Change"}},
+		{6, Names{"Insiden Besar", "Major Incident"}},
+	}
+
+	defaultPriorities = []entry{
+		{1, Names{"Rendah", "Low"}},
+		{2, Names{"Sedang", "Medium"}},
+		{3, Names{"Tinggi", "High"}},
+		{4, Names{"Mendesak", "Urgent"}},
+		{5, Names{"Kritis", "Critical"}},
+	}
+
+	defaultStatuses = []entry{
+		{1, Names{"Baru", "New"}},
+		{2, Names{"Dibuka", "Open"}},
+		{3, Names{"Tertunda", "Pending"}},
+		{4, Names{"Menunggu", "Waiting"}},
+		{5, Names{"Selesai", "Resolved"}},
+		{6, Names{"Ditutup", "Closed"}},
+		{7, Names{"Ditolak", "Rejected"}},
+		{8, Names{"Dibatalkan", "Canceled"}},
+	}
+)
+
+// indonesianNames translates the names InvGate uses out of the box. Names an
+// InvGate admin added or renamed are shown as-is in both languages.
+var indonesianNames = buildIndonesianNames(defaultTypes, defaultPriorities, defaultStatuses)
+
+func buildIndonesianNames(tables ...[]entry) map[string]string {
+	names := make(map[string]string)
+	for _, table := range tables {
+		for _, e := range table {
+			names[strings.ToLower(e.Names.EN)] = e.Names.ID
+		}
+	}
+	// InvGate spells it both ways depending on the version
+	names["cancelled"] = "Dibatalkan"
+	return names
+}
+
+// localize builds the names of an entry InvGate returned under name.
+func localize(name string) Names {
+	indonesian, ok := indonesianNames[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		indonesian = name
+	}
+	return Names{ID: indonesian, EN: name}
+}
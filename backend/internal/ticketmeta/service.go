@@ -0,0 +1,270 @@
+package ticketmeta
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"werk-ticketing/internal/constants"
+	"werk-ticketing/internal/invgate"
+	"werk-ticketing/internal/tenant"
+)
+
+// Item is a type, priority or status with its name in the requested language.
+type Item struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Names Names  `json:"names"`
+}
+
+// Service serves the ticket types, priorities and statuses of the tenant
+// InvGate instance. Lists are cached per tenant and refreshed in the
+// background; while InvGate cannot be reached the last known lists, or the
+// built-in defaults, are served, so lookups never fail.
+type Service interface {
+	Types(ctx context.Context) []Item
+	Priorities(ctx context.Context) []Item
+	Statuses(ctx context.Context) []Item
+	StatusName(ctx context.Context, statusID interface{}) string
+	Invalidate(ctx context.Context)
+}
+
+// tables are the lists of one tenant.
+type tables struct {
+	types      []entry
+	priorities []entry
+	statuses   []entry
+	loadedAt   time.Time
+	ok         bool // false when built from defaults after a failed load
+}
+
+// fresh reports whether t can be served without reloading it.
+func (t *tables) fresh(now time.Time) bool {
+	if t.ok {
+		return now.Sub(t.loadedAt) < constants.TicketMetaCacheTTL
+	}
+	return now.Sub(t.loadedAt) < constants.TicketMetaRetryInterval
+}
+
+type service struct {
+	client  invgate.Service
+	tenants tenant.Service
+	logger  *logrus.Logger
+
+	mu    sync.Mutex
+	cache map[string]*tables // By tenant ID
+}
+
+// NewService instantiates the ticket meta service and starts its background refresh.
+func NewService(client invgate.Service, tenants tenant.Service, logger *logrus.Logger) Service {
+	s := &service{
+		client:  client,
+		tenants: tenants,
+		logger:  logger,
+		cache:   make(map[string]*tables),
+	}
+
+	go s.runRefresher(constants.TicketMetaRefreshInterval)
+
+	return s
+}
+
+func (s *service) Types(ctx context.Context) []Item {
+	return toItems(s.get(ctx).types, LanguageFromContext(ctx))
+}
+
+func (s *service) Priorities(ctx context.Context) []Item {
+	return toItems(s.get(ctx).priorities, LanguageFromContext(ctx))
+}
+
+func (s *service) Statuses(ctx context.Context) []Item {
+	return toItems(s.get(ctx).statuses, LanguageFromContext(ctx))
+}
+
+// StatusName returns the name of an InvGate status_id value in the language of
+// ctx, or an empty string for unknown statuses.
+func (s *service) StatusName(ctx context.Context, statusID interface{}) string {
+	id, ok := toInt(statusID)
+	if !ok {
+		return ""
+	}
+	for _, e := range s.get(ctx).statuses {
+		if e.ID == id {
+			return e.Names.In(LanguageFromContext(ctx))
+		}
+	}
+	return ""
+}
+
+// Invalidate drops the lists of the tenant of ctx, the next lookup reloads them.
+func (s *service) Invalidate(ctx context.Context) {
+	s.mu.Lock()
+	delete(s.cache, tenant.IDFromContext(ctx))
+	s.mu.Unlock()
+}
+
+// get returns the tables of the tenant of ctx, loading them when missing or stale.
+func (s *service) get(ctx context.Context) *tables {
+	tenantID := tenant.IDFromContext(ctx)
+
+	s.mu.Lock()
+	cached := s.cache[tenantID]
+	s.mu.Unlock()
+
+	if cached != nil && cached.fresh(time.Now()) {
+		return cached
+	}
+	return s.refresh(ctx, tenantID, cached)
+}
+
+// refresh loads the tables of a tenant from InvGate. When that fails the
+// previous tables, or the defaults, are kept for a short while before the
+// next attempt.
+func (s *service) refresh(ctx context.Context, tenantID string, previous *tables) *tables {
+	loaded, err := s.load(ctx)
+	if err != nil {
+		s.logger.WithError(err).WithField("tenantID", tenantID).Warn("failed to load ticket types, priorities and statuses from InvGate")
+
+		loaded = &tables{
+			types:      defaultTypes,
+			priorities: defaultPriorities,
+			statuses:   defaultStatuses,
+		}
+		if previous != nil && previous.ok {
+			copied := *previous
+			loaded = &copied
+		}
+		loaded.ok = false
+	}
+	loaded.loadedAt = time.Now()
+
+	s.mu.Lock()
+	s.cache[tenantID] = loaded
+	s.mu.Unlock()
+	return loaded
+}
+
+func (s *service) load(ctx context.Context) (*tables, error) {
+	types, err := s.client.GetIncidentTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	priorities, err := s.client.GetPriorities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := s.client.GetStatuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tables{
+		types:      parseEntries(types, defaultTypes),
+		priorities: parseEntries(priorities, defaultPriorities),
+		statuses:   parseEntries(statuses, defaultStatuses),
+		ok:         true,
+	}, nil
+}
+
+// runRefresher periodically reloads the tables of every tenant that has been
+// requested, so requests are served from memory.
+func (s *service) runRefresher(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.mu.Lock()
+		current := make(map[string]*tables, len(s.cache))
+		for tenantID, t := range s.cache {
+			current[tenantID] = t
+		}
+		s.mu.Unlock()
+
+		for tenantID, previous := range current {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			tenantCtx, err := s.tenants.WithTenant(ctx, tenantID)
+			if err != nil {
+				// The tenant was removed, stop refreshing it
+				s.logger.WithError(err).WithField("tenantID", tenantID).Warn("failed to refresh ticket meta of tenant")
+				s.mu.Lock()
+				delete(s.cache, tenantID)
+				s.mu.Unlock()
+				cancel()
+				continue
+			}
+			s.refresh(tenantCtx, tenantID, previous)
+			cancel()
+		}
+	}
+}
+
+// parseEntries reads the id/name list of an InvGate attribute response. An
+// empty list, e.g. from an unexpected response format, yields fallback.
+func parseEntries(resp map[string]interface{}, fallback []entry) []entry {
+	var items []interface{}
+	if arr, ok := resp["data"].([]interface{}); ok {
+		items = arr
+	} else {
+		for _, v := range resp {
+			if arr, ok := v.([]interface{}); ok {
+				items = arr
+				break
+			}
+		}
+	}
+
+	entries := make([]entry, 0, len(items))
+	for _, item := range items {
+		raw, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, ok := toInt(raw["id"])
+		if !ok {
+			continue
+		}
+		name, _ := raw["name"].(string)
+		if name == "" {
+			continue
+		}
+		entries = append(entries, entry{ID: id, Names: localize(name)})
+	}
+
+	if len(entries) == 0 {
+		return fallback
+	}
+	return entries
+}
+
+func toItems(entries []entry, lang string) []Item {
+	items := make([]Item, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, Item{
+			ID:    e.ID,
+			Name:  e.Names.In(lang),
+			Names: e.Names,
+		})
+	}
+	return items
+}
+
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	case string:
+		parsed, err := strconv.Atoi(n)
+		if err != nil {
+			return 0, false
+		}
+		return parsed, true
+	default:
+		return 0, false
+	}
+}
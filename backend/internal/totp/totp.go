@@ -0,0 +1,115 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Codes follow RFC 6238 with HMAC-SHA1, 6 digits and a 30 second period,
+// the defaults understood by common authenticator apps.
+const (
+	// Digits is the length of generated codes.
+	Digits = 6
+	// Period is the lifetime of a single code.
+	Period = 30 * time.Second
+
+	secretSize = 20 // 160 bits, as recommended by RFC 4226
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new random base32 encoded secret.
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// URI builds the otpauth:// URI used to enroll the secret in an authenticator app.
+func URI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprintf("%d", Digits))
+	params.Set("period", fmt.Sprintf("%d", int(Period.Seconds())))
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// Step returns the time step counter for t.
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period.Seconds())
+}
+
+// Code returns the code for the given secret at time t.
+func Code(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, Step(t)), nil
+}
+
+// Validate checks code against the secret, accepting codes from up to skew
+// steps before or after t to tolerate clock drift. It returns the matching
+// step so callers can reject a code that was already used.
+func Validate(secret, code string, t time.Time, skew int) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, false
+	}
+
+	current := Step(t)
+	for i := -skew; i <= skew; i++ {
+		step := current + int64(i)
+		if subtle.ConstantTimeCompare([]byte(hotp(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// hotp implements RFC 4226 with dynamic truncation.
+func hotp(key []byte, counter int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", Digits, value%mod)
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	normalized := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(secret), " ", ""))
+	normalized = strings.TrimRight(normalized, "=")
+	key, err := encoding.DecodeString(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("invalid totp secret: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,138 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 seed of the RFC 6238 appendix B test vectors.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// RFC 6238 appendix B lists 8 digit codes; with 6 digits the code is the
+// last 6 digits of the same truncated value.
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "94287082"},
+	{1111111109, "07081804"},
+	{1111111111, "14050471"},
+	{1234567890, "89005924"},
+	{2000000000, "69279037"},
+	{20000000000, "65353130"},
+}
+
+func TestCodeRFC6238(t *testing.T) {
+	for _, v := range rfcVectors {
+		got, err := Code(rfcSecret, time.Unix(v.unix, 0))
+		if err != nil {
+			t.Fatalf("Code(%d): %v", v.unix, err)
+		}
+		if want := v.code[len(v.code)-Digits:]; got != want {
+			t.Errorf("Code(%d) = %s, want %s", v.unix, got, want)
+		}
+	}
+}
+
+func TestCodeAcceptsUnpaddedLowercaseSecret(t *testing.T) {
+	secret := strings.ToLower(strings.TrimRight(rfcSecret, "="))
+	got, err := Code(secret, time.Unix(59, 0))
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+	if got != "287082" {
+		t.Fatalf("Code = %s, want 287082", got)
+	}
+}
+
+func TestValidateSkewWindow(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+
+	tests := []struct {
+		name   string
+		offset time.Duration // age of the code relative to now
+		skew   int
+		want   bool
+	}{
+		{name: "current step", skew: 1, want: true},
+		{name: "previous step", offset: -Period, skew: 1, want: true},
+		{name: "next step", offset: Period, skew: 1, want: true},
+		{name: "two steps old", offset: -2 * Period, skew: 1},
+		{name: "two steps ahead", offset: 2 * Period, skew: 1},
+		{name: "previous step without skew", offset: -Period},
+		{name: "two steps old with wider skew", offset: -2 * Period, skew: 2, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := now.Add(tt.offset)
+			code, err := Code(rfcSecret, at)
+			if err != nil {
+				t.Fatalf("Code: %v", err)
+			}
+
+			step, ok := Validate(rfcSecret, code, now, tt.skew)
+			if ok != tt.want {
+				t.Fatalf("Validate ok = %v, want %v", ok, tt.want)
+			}
+			if ok && step != Step(at) {
+				t.Fatalf("step = %d, want %d (current %d)", step, Step(at), current)
+			}
+		})
+	}
+}
+
+func TestValidateRejectsMalformedCodes(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870820", "abcdef", "94287082"} {
+		if _, ok := Validate(rfcSecret, code, now, 1); ok {
+			t.Errorf("Validate(%q) accepted", code)
+		}
+	}
+	if _, ok := Validate("not base32!", "287082", now, 1); ok {
+		t.Error("Validate accepted an invalid secret")
+	}
+}
+
+// TestValidateStepRejectsReplay checks the contract callers rely on to
+// reject replays: a code stays valid across the skew window, but always maps
+// to the same step, so storing the last used step blocks it.
+func TestValidateStepRejectsReplay(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, err := Code(rfcSecret, now)
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+
+	var lastUsed int64
+	use := func(at time.Time) bool {
+		step, ok := Validate(rfcSecret, code, at, 1)
+		if !ok || step <= lastUsed {
+			return false
+		}
+		lastUsed = step
+		return true
+	}
+
+	if !use(now) {
+		t.Fatal("first use rejected")
+	}
+	if use(now) {
+		t.Fatal("replay in the same period accepted")
+	}
+	if use(now.Add(Period)) {
+		t.Fatal("replay in the next period accepted")
+	}
+
+	next, err := Code(rfcSecret, now.Add(Period))
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+	code = next
+	if !use(now.Add(Period)) {
+		t.Fatal("code of the next period rejected")
+	}
+}
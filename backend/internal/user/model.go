@@ -1,25 +1,135 @@
 package user
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"werk-ticketing/internal/database"
+)
+
+// Supported user roles.
+const (
+	RoleRequester = "requester" // Regular employee, can only see own tickets
+	RoleAgent     = "agent"     // Support agent, can see tickets of other users
+	RoleAdmin     = "admin"     // Administrator, full access including admin endpoints
+)
+
+// IsValidRole reports whether role is one of the supported roles.
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleRequester, RoleAgent, RoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// Supported preferred languages.
+const (
+	LanguageIndonesian = "id"
+	LanguageEnglish    = "en"
+)
+
+// IsValidLanguage reports whether lang is one of the supported languages.
+func IsValidLanguage(lang string) bool {
+	switch lang {
+	case LanguageIndonesian, LanguageEnglish:
+		return true
+	default:
+		return false
+	}
+}
+
+// Profile holds the user fields the user may edit themselves.
+type Profile struct {
+	Name              string
+	LastName          string
+	Phone             string
+	PreferredLanguage string
+}
 
 // User represents the persisted user entity.
 // This model is used by GORM for auto migration.
 // When the application starts, GORM will automatically create/update the users table
 // based on this struct definition.
 type User struct {
-	ID            string    `gorm:"type:char(36);primaryKey;default:(UUID())"` // Local identifier, use UUID generated by DB
-	Name          string    `gorm:"size:100;not null"`
-	LastName      string    `gorm:"size:100;not null;column:last_name"` // Explicit column name to match migration
-	Email         string    `gorm:"size:190;not null;uniqueIndex"`
-	Password      string    `gorm:"size:255;not null"`
-	InvGateUserID int       `gorm:"not null;column:invgate_user_id"`
-	CreatedBy     string    `gorm:"size:190;column:created_by"` // Email of user who created this record
-	UpdatedBy     string    `gorm:"size:190;column:updated_by"` // Email of user who last updated this record
-	CreatedAt     time.Time `gorm:"autoCreateTime"`
-	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+	ID                string     `gorm:"type:char(36);primaryKey;default:(UUID())"`                            // Local identifier, use UUID generated by DB
+	TenantID          string     `gorm:"type:char(36);not null;default:'';index;uniqueIndex:idx_tenant_email"` // Tenant the user belongs to, emails are unique per tenant
+	Name              string     `gorm:"size:100;not null"`
+	LastName          string     `gorm:"size:100;not null;column:last_name"` // Explicit column name to match migration
+	Email             string     `gorm:"size:190;not null;uniqueIndex:idx_tenant_email"`
+	Phone             string     `gorm:"size:30"`
+	PreferredLanguage string     `gorm:"size:5;not null;default:id;column:preferred_language"` // id or en
+	Password          string     `gorm:"size:255;not null"`
+	InvGateUserID     int        `gorm:"not null;column:invgate_user_id"`
+	InvGateCompanyID  int        `gorm:"not null;default:0;column:invgate_company_id"`  // Scope chosen at registration, all 0 for the configured defaults
+	InvGateGroupID    int        `gorm:"not null;default:0;column:invgate_group_id"`    // Scope chosen at registration
+	InvGateLocationID int        `gorm:"not null;default:0;column:invgate_location_id"` // Scope chosen at registration
+	Role              string     `gorm:"size:20;not null;default:requester;index"`      // requester, agent or admin
+	EmailVerifiedAt   *time.Time `gorm:"column:email_verified_at"`                      // Nil until the user confirmed their email address
+	MFASecret         string     `gorm:"size:64;column:mfa_secret"`                     // Base32 TOTP secret, set on enrollment
+	MFAEnabledAt      *time.Time `gorm:"column:mfa_enabled_at"`                         // Set once enrollment was confirmed with a valid code
+	MFALastUsedStep   int64      `gorm:"not null;default:0;column:mfa_last_used_step"`  // Last accepted TOTP time step, prevents code replay
+	MFARecoveryCodes  string     `gorm:"type:text;column:mfa_recovery_codes"`           // Comma separated SHA-256 hashes of unused recovery codes
+	OIDCSubject       *string    `gorm:"size:255;uniqueIndex;column:oidc_subject"`      // Subject of the linked SSO account, nil for local-only users
+	DeactivatedAt     *time.Time `gorm:"index;column:deactivated_at"`                   // Set by an admin to block login, nil for active users
+	AnonymizedAt      *time.Time `gorm:"column:anonymized_at"`                          // Set when the account was deleted and its personal data removed
+	CreatedBy         string     `gorm:"size:190;column:created_by"`                    // Email of user who created this record
+	UpdatedBy         string     `gorm:"size:190;column:updated_by"`                    // Email of user who last updated this record
+	CreatedAt         time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time  `gorm:"autoUpdateTime"`
 }
 
 // TableName specifies the table name for GORM
 func (User) TableName() string {
 	return "users"
 }
+
+// BeforeCreate assigns the UUID in Go so the ID is known to the caller after insert.
+func (u *User) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == "" {
+		u.ID = database.NewUUID()
+	}
+	return nil
+}
+
+// HasRole reports whether the user has one of the given roles.
+// Users without a stored role are treated as requesters.
+func (u *User) HasRole(roles ...string) bool {
+	current := u.Role
+	if current == "" {
+		current = RoleRequester
+	}
+	for _, role := range roles {
+		if current == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEmailVerified reports whether the user confirmed their email address.
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsMFAEnabled reports whether two-factor authentication is active for the user.
+func (u *User) IsMFAEnabled() bool {
+	return u.MFAEnabledAt != nil && u.MFASecret != ""
+}
+
+// Profile returns the editable profile fields of the user.
+func (u *User) Profile() Profile {
+	return Profile{
+		Name:              u.Name,
+		LastName:          u.LastName,
+		Phone:             u.Phone,
+		PreferredLanguage: u.PreferredLanguage,
+	}
+}
+
+// IsActive reports whether the user may log in.
+func (u *User) IsActive() bool {
+	return u.DeactivatedAt == nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -12,10 +13,40 @@ import (
 // Repository abstracts data persistence for users.
 type Repository interface {
 	Create(ctx context.Context, user *User) error
-	GetByEmail(ctx context.Context, email string) (*User, error)
+	GetByEmail(ctx context.Context, tenantID, email string) (*User, error)
+	GetByID(ctx context.Context, id string) (*User, error)
+	GetByOIDCSubject(ctx context.Context, subject string) (*User, error)
+	UpdateRole(ctx context.Context, id, role, updatedBy string) error
+	UpdateProfile(ctx context.Context, id string, profile Profile, updatedBy string) error
+	UpdatePassword(ctx context.Context, id, passwordHash, updatedBy string) error
+	MarkEmailVerified(ctx context.Context, id string, verifiedAt time.Time) error
+	SetMFASecret(ctx context.Context, id, secret string) error
+	EnableMFA(ctx context.Context, id, recoveryCodes string, step int64, enabledAt time.Time) error
+	ConsumeMFAStep(ctx context.Context, id string, step int64) (bool, error)
+	ConsumeMFARecoveryCode(ctx context.Context, id, oldCodes, newCodes string) (bool, error)
+	LinkOIDCSubject(ctx context.Context, id, subject string) error
+	SetDeactivatedAt(ctx context.Context, id string, deactivatedAt *time.Time, updatedBy string) error
+	UpdateInvGateUserID(ctx context.Context, id string, invGateUserID int, updatedBy string) error
+	GetByInvGateUserID(ctx context.Context, tenantID string, invGateUserID int) (*User, error)
+	Anonymize(ctx context.Context, id, placeholderEmail string, at time.Time) error
+	List(ctx context.Context, filter ListFilter, limit, offset int) ([]User, int64, error)
 	Delete(ctx context.Context, id string) error
 }
 
+// Status filters accepted by ListFilter.
+const (
+	StatusActive      = "active"
+	StatusDeactivated = "deactivated"
+)
+
+// ListFilter narrows down List. Empty fields do not filter.
+type ListFilter struct {
+	TenantID string
+	Query    string // Matched against email, name and last name
+	Role     string
+	Status   string // StatusActive or StatusDeactivated
+}
+
 type gormRepository struct {
 	db *gorm.DB
 }
@@ -75,9 +106,11 @@ func isDuplicateKeyError(err error) bool {
 	return false
 }
 
-func (r *gormRepository) GetByEmail(ctx context.Context, email string) (*User, error) {
+// GetByEmail finds a user of a tenant; the same email may be registered with
+// several tenants.
+func (r *gormRepository) GetByEmail(ctx context.Context, tenantID, email string) (*User, error) {
 	var u User
-	err := r.db.WithContext(ctx).Where("email = ?", email).First(&u).Error
+	err := r.db.WithContext(ctx).Where("tenant_id = ? AND email = ?", tenantID, email).First(&u).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -87,6 +120,200 @@ func (r *gormRepository) GetByEmail(ctx context.Context, email string) (*User, e
 	return &u, nil
 }
 
+func (r *gormRepository) GetByID(ctx context.Context, id string) (*User, error) {
+	var u User
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&u).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *gormRepository) GetByOIDCSubject(ctx context.Context, subject string) (*User, error) {
+	var u User
+	err := r.db.WithContext(ctx).Where("oidc_subject = ?", subject).First(&u).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+// UpdateRole changes the role of a user and records who made the change.
+func (r *gormRepository) UpdateRole(ctx context.Context, id, role, updatedBy string) error {
+	return r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"role":       role,
+		"updated_by": updatedBy,
+	}).Error
+}
+
+// UpdateProfile stores the self-editable profile fields of a user.
+func (r *gormRepository) UpdateProfile(ctx context.Context, id string, profile Profile, updatedBy string) error {
+	return r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"name":               profile.Name,
+		"last_name":          profile.LastName,
+		"phone":              profile.Phone,
+		"preferred_language": profile.PreferredLanguage,
+		"updated_by":         updatedBy,
+	}).Error
+}
+
+// UpdatePassword replaces the stored bcrypt hash of a user.
+func (r *gormRepository) UpdatePassword(ctx context.Context, id, passwordHash, updatedBy string) error {
+	return r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"password":   passwordHash,
+		"updated_by": updatedBy,
+	}).Error
+}
+
+// MarkEmailVerified records when the user confirmed their email address.
+func (r *gormRepository) MarkEmailVerified(ctx context.Context, id string, verifiedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Update("email_verified_at", verifiedAt).Error
+}
+
+// SetMFASecret stores a pending TOTP secret. Two-factor authentication stays
+// disabled until EnableMFA is called.
+func (r *gormRepository) SetMFASecret(ctx context.Context, id, secret string) error {
+	return r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"mfa_secret":         secret,
+		"mfa_enabled_at":     nil,
+		"mfa_last_used_step": 0,
+		"mfa_recovery_codes": "",
+	}).Error
+}
+
+// EnableMFA activates two-factor authentication with the given recovery code hashes.
+func (r *gormRepository) EnableMFA(ctx context.Context, id, recoveryCodes string, step int64, enabledAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"mfa_enabled_at":     enabledAt,
+		"mfa_last_used_step": step,
+		"mfa_recovery_codes": recoveryCodes,
+	}).Error
+}
+
+// ConsumeMFAStep records a used TOTP time step. It returns false when the
+// step (or a later one) was already used, so a code cannot be replayed.
+func (r *gormRepository) ConsumeMFAStep(ctx context.Context, id string, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&User{}).
+		Where("id = ? AND mfa_last_used_step < ?", id, step).
+		Update("mfa_last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ConsumeMFARecoveryCode replaces the recovery code list only if it still
+// equals oldCodes, so the same recovery code cannot be used twice.
+func (r *gormRepository) ConsumeMFARecoveryCode(ctx context.Context, id, oldCodes, newCodes string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&User{}).
+		Where("id = ? AND mfa_recovery_codes = ?", id, oldCodes).
+		Update("mfa_recovery_codes", newCodes)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// LinkOIDCSubject links an existing user to an SSO account.
+func (r *gormRepository) LinkOIDCSubject(ctx context.Context, id, subject string) error {
+	return r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Update("oidc_subject", subject).Error
+}
+
+// SetDeactivatedAt deactivates a user, or reactivates it when deactivatedAt is nil.
+func (r *gormRepository) SetDeactivatedAt(ctx context.Context, id string, deactivatedAt *time.Time, updatedBy string) error {
+	return r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"deactivated_at": deactivatedAt,
+		"updated_by":     updatedBy,
+	}).Error
+}
+
+// UpdateInvGateUserID links the local user to another InvGate user.
+func (r *gormRepository) UpdateInvGateUserID(ctx context.Context, id string, invGateUserID int, updatedBy string) error {
+	return r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"invgate_user_id": invGateUserID,
+		"updated_by":      updatedBy,
+	}).Error
+}
+
+// GetByInvGateUserID finds a user of a tenant; InvGate user IDs are only
+// unique within one InvGate instance.
+func (r *gormRepository) GetByInvGateUserID(ctx context.Context, tenantID string, invGateUserID int) (*User, error) {
+	var u User
+	err := r.db.WithContext(ctx).Where("tenant_id = ? AND invgate_user_id = ?", tenantID, invGateUserID).First(&u).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+// List returns one page of users matching the filter, newest first, together
+// with the total number of matching users.
+func (r *gormRepository) List(ctx context.Context, filter ListFilter, limit, offset int) ([]User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&User{})
+	if filter.TenantID != "" {
+		query = query.Where("tenant_id = ?", filter.TenantID)
+	}
+	if filter.Query != "" {
+		like := "%" + escapeLike(filter.Query) + "%"
+		query = query.Where("email LIKE ? OR name LIKE ? OR last_name LIKE ?", like, like, like)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	switch filter.Status {
+	case StatusActive:
+		query = query.Where("deactivated_at IS NULL")
+	case StatusDeactivated:
+		query = query.Where("deactivated_at IS NOT NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []User
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// Anonymize removes the personal data of a deleted account. The row itself is
+// kept, deactivated, so references to the user ID stay valid.
+func (r *gormRepository) Anonymize(ctx context.Context, id, placeholderEmail string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"name":               "Deleted",
+		"last_name":          "User",
+		"email":              placeholderEmail,
+		"phone":              "",
+		"password":           "",
+		"mfa_secret":         "",
+		"mfa_enabled_at":     nil,
+		"mfa_recovery_codes": "",
+		"oidc_subject":       nil,
+		"deactivated_at":     at,
+		"anonymized_at":      at,
+		"created_by":         "",
+		"updated_by":         "",
+	}).Error
+}
+
+// escapeLike escapes the wildcard characters of a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(s)
+}
+
 func (r *gormRepository) Delete(ctx context.Context, id string) error {
 	return r.db.WithContext(ctx).Delete(&User{}, "id = ?", id).Error
 }
@@ -0,0 +1,45 @@
+package usertoken
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"werk-ticketing/internal/database"
+)
+
+// Token purposes.
+const (
+	PurposePasswordReset     = "password_reset"
+	PurposeEmailVerification = "email_verification"
+)
+
+// UserToken is a single-use, expiring token sent to a user out of band (e.g. by email).
+// Only the SHA-256 hash of the token is stored.
+type UserToken struct {
+	ID        string     `gorm:"type:char(36);primaryKey"`
+	UserID    string     `gorm:"type:char(36);not null;index"`
+	Purpose   string     `gorm:"size:30;not null;index"`
+	TokenHash string     `gorm:"size:64;not null;uniqueIndex"` // hex encoded SHA-256 of the token
+	ExpiresAt time.Time  `gorm:"not null;index"`
+	UsedAt    *time.Time // Set once the token has been consumed
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (UserToken) TableName() string {
+	return "user_tokens"
+}
+
+// BeforeCreate assigns the token row ID when the caller did not set one.
+func (t *UserToken) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = database.NewUUID()
+	}
+	return nil
+}
+
+// IsUsable reports whether the token has not been used and is not expired.
+func (t *UserToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,61 @@
+package usertoken
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Repository abstracts data persistence for user tokens.
+type Repository interface {
+	Create(ctx context.Context, token *UserToken) error
+	GetByHash(ctx context.Context, purpose, tokenHash string) (*UserToken, error)
+	MarkUsed(ctx context.Context, id string) (bool, error)
+	InvalidateForUser(ctx context.Context, userID, purpose string) error
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository builds a Gorm-backed user token repository.
+func NewRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+func (r *gormRepository) Create(ctx context.Context, token *UserToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *gormRepository) GetByHash(ctx context.Context, purpose, tokenHash string) (*UserToken, error) {
+	var t UserToken
+	err := r.db.WithContext(ctx).Where("purpose = ? AND token_hash = ?", purpose, tokenHash).First(&t).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// MarkUsed consumes a token. It returns false when the token was already used,
+// so only one of several concurrent requests can consume it.
+func (r *gormRepository) MarkUsed(ctx context.Context, id string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&UserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now().UTC())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// InvalidateForUser marks all unused tokens of a purpose as used, e.g. when a new one is issued.
+func (r *gormRepository) InvalidateForUser(ctx context.Context, userID, purpose string) error {
+	return r.db.WithContext(ctx).Model(&UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now().UTC()).Error
+}
@@ -0,0 +1,24 @@
+package usertoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// Generate returns a new random token and the hash to persist for it.
+func Generate() (plain, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	plain = base64.RawURLEncoding.EncodeToString(b)
+	return plain, Hash(plain), nil
+}
+
+// Hash returns the hex encoded SHA-256 hash of a token.
+func Hash(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
@@ -6,19 +6,36 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"werk-ticketing/internal/admin"
+	"werk-ticketing/internal/apikey"
 	"werk-ticketing/internal/auth"
+	"werk-ticketing/internal/cache"
+	"werk-ticketing/internal/category"
 	"werk-ticketing/internal/config"
 	"werk-ticketing/internal/constants"
 	"werk-ticketing/internal/database"
 	"werk-ticketing/internal/invgate"
+	"werk-ticketing/internal/jwtkeys"
+	"werk-ticketing/internal/loginattempt"
+	"werk-ticketing/internal/mailer"
+	"werk-ticketing/internal/oidc"
+	"werk-ticketing/internal/privacy"
+	"werk-ticketing/internal/profile"
+	"werk-ticketing/internal/registration"
 	"werk-ticketing/internal/router"
+	"werk-ticketing/internal/secretbox"
+	"werk-ticketing/internal/session"
+	"werk-ticketing/internal/tenant"
 	"werk-ticketing/internal/ticket"
+	"werk-ticketing/internal/ticketmeta"
 	"werk-ticketing/internal/user"
+	"werk-ticketing/internal/usertoken"
 )
 
 func main() {
@@ -40,8 +57,21 @@ func main() {
 	// Auto migrate all models
 	// This ensures all tables are created/updated when the application starts
 	if err := db.AutoMigrate(
-		&user.User{},     // Users table
-		&ticket.Ticket{}, // Tickets table
+		&user.User{},                 // Users table
+		&ticket.Ticket{},             // Tickets table
+		&ticket.TicketAttachment{},   // Attachment to ticket mapping
+		&ticket.TicketEvent{},        // History of changes made to tickets
+		&session.Session{},           // Login sessions and refresh token rotation
+		&auth.RevokedToken{},         // Revoked JWT IDs (mysql blacklist backend)
+		&usertoken.UserToken{},       // Single-use tokens such as password reset links
+		&loginattempt.LoginAttempt{}, // Failed login tracking per email and IP
+		&oidc.LoginState{},           // Pending single sign-on logins
+		&apikey.APIKey{},             // Personal API keys for scripted ticket access
+		&privacy.DataExport{},        // Personal data export jobs
+		&privacy.AccountDeletion{},   // Audit log of deleted accounts
+		&registration.Invite{},       // Admin-issued registration invite codes
+		&tenant.Tenant{},             // Tenants and their InvGate connections
+		&category.Override{},         // Local icons and descriptions of InvGate categories
 	); err != nil {
 		log.Fatalf("auto migrate error: %v", err)
 	}
@@ -55,26 +85,145 @@ func main() {
 	})
 	logger.SetLevel(logrus.InfoLevel)
 
+	// The default tenant mirrors the ARMMADA_* configuration. Rows created
+	// before multi-tenancy belong to it.
+	tenantSecrets, err := secretbox.NewFromBase64(cfg.TenantSecretKey)
+	if err != nil {
+		log.Fatalf("tenant secret key error: %v", err)
+	}
+	tenantService := tenant.NewService(tenant.NewRepository(db, tenantSecrets), logger)
+	if err := tenantService.SealPasswords(context.Background()); err != nil {
+		log.Fatalf("tenant password encryption error: %v", err)
+	}
+	defaultTenant, err := tenantService.EnsureDefault(context.Background(), tenant.Tenant{
+		Name:                cfg.DefaultTenantName,
+		Hosts:               cfg.DefaultTenantHosts,
+		InvGateBaseURL:      cfg.ArmMadaBaseURL,
+		InvGateUsername:     cfg.ArmMadaUsername,
+		InvGatePassword:     cfg.ArmMadaPassword,
+		InvGatePageKey:      cfg.ArmMadaPageKey,
+		CompanyID:           cfg.ArmMadaCompanyID,
+		GroupID:             cfg.ArmMadaGroupID,
+		LocationID:          cfg.ArmMadaLocationID,
+		AllowedCategories:   cfg.AllowedCategoryIDs,
+		RegistrationDomains: cfg.RegistrationDomains,
+	})
+	if err != nil {
+		log.Fatalf("default tenant error: %v", err)
+	}
+	if err := tenantService.AssignOrphans(
+		context.Background(),
+		defaultTenant.ID,
+		user.User{}.TableName(),
+		ticket.Ticket{}.TableName(),
+		ticket.TicketAttachment{}.TableName(),
+		registration.Invite{}.TableName(),
+	); err != nil {
+		log.Fatalf("default tenant error: %v", err)
+	}
+
 	// Initialize services
-	invgateClient := invgate.NewService(cfg)
+	referenceCache, err := cache.NewFromConfig(cfg, logger)
+	if err != nil {
+		log.Fatalf("cache error: %v", err)
+	}
+	invgateClient := invgate.WithCache(invgate.NewService(cfg), referenceCache)
 	userRepo := user.NewRepository(db)
 	ticketRepo := ticket.NewRepository(db)
-	ticketService := ticket.NewService(invgateClient, ticketRepo, userRepo, logger)
+	sessionRepo := session.NewRepository(db)
+	userTokenRepo := usertoken.NewRepository(db)
+	loginAttemptRepo := loginattempt.NewRepository(db)
+	ticketMeta := ticketmeta.NewService(invgateClient, tenantService, logger)
+	ticketService := ticket.NewService(invgateClient, ticketRepo, userRepo, ticketMeta, tenantService, []byte(cfg.CursorSecret), logger)
 	ticketHandler := ticket.NewHandler(ticketService)
+	categoryService := category.NewService(invgateClient, category.NewRepository(db), tenantService, logger)
+	categoryHandler := category.NewHandler(categoryService)
+
+	tokenBlacklist, err := auth.NewTokenBlacklistFromConfig(cfg, db, logger)
+	if err != nil {
+		log.Fatalf("token blacklist error: %v", err)
+	}
+
+	mail, err := mailer.NewFromConfig(cfg, logger)
+	if err != nil {
+		log.Fatalf("mailer error: %v", err)
+	}
+
+	jwtKeys, err := jwtkeys.NewFromConfig(cfg, logger)
+	if err != nil {
+		log.Fatalf("jwt keys error: %v", err)
+	}
+
+	var oidcProvider *oidc.Provider
+	if cfg.OIDCEnabled() {
+		oidcProvider = oidc.NewProvider(oidc.Config{
+			IssuerURL:    cfg.OIDCIssuerURL,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  cfg.OIDCRedirectURL,
+			Scopes:       strings.Fields(cfg.OIDCScopes),
+		}, nil)
+		logger.WithField("issuer", cfg.OIDCIssuerURL).Info("single sign-on enabled")
+	}
+
+	defaultScope := registration.Scope{
+		CompanyID:  cfg.ArmMadaCompanyID,
+		GroupID:    cfg.ArmMadaGroupID,
+		LocationID: cfg.ArmMadaLocationID,
+	}
+	registrationDomains, err := registration.ParseDomainRules(cfg.RegistrationDomains, defaultScope)
+	if err != nil {
+		log.Fatalf("registration domains error: %v", err)
+	}
+	registrationService := registration.NewService(registration.NewRepository(db), registrationDomains, defaultScope, logger)
 
 	authService := auth.NewService(
 		userRepo,
+		sessionRepo,
+		userTokenRepo,
+		loginAttemptRepo,
+		tokenBlacklist,
 		invgateClient,
-		cfg.JWTSecret,
+		mail,
+		jwtKeys,
+		cfg.AppBaseURL,
+		cfg.MFAIssuer,
 		logger,
-		cfg.ArmMadaCompanyID,
-		cfg.ArmMadaGroupID,
-		cfg.ArmMadaLocationID,
+		registrationService,
+		cfg.DeferScopesUntilVerified,
+		oidcProvider,
+		oidc.NewStateRepository(db),
 	)
 	authHandler := auth.NewHandler(authService)
 
+	adminService := admin.NewService(userRepo, loginAttemptRepo, authService, registrationService, tenantService, invgateClient, referenceCache, ticketMeta, logger)
+	adminHandler := admin.NewHandler(adminService)
+
+	apiKeyRepo := apikey.NewRepository(db)
+	apiKeyService := apikey.NewService(apiKeyRepo, userRepo, logger)
+	apiKeyHandler := apikey.NewHandler(apiKeyService)
+
+	profileService := profile.NewService(userRepo, invgateClient, logger)
+	profileHandler := profile.NewHandler(profileService)
+
+	privacyService := privacy.NewService(
+		privacy.NewRepository(db),
+		userRepo,
+		sessionRepo,
+		apiKeyRepo,
+		userTokenRepo,
+		loginAttemptRepo,
+		ticketService,
+		authService,
+		invgateClient,
+		tenantService,
+		cfg.DataExportDir,
+		logger,
+	)
+	privacyHandler := privacy.NewHandler(privacyService)
+
 	// Setup router
-	appRouter := router.NewRouter(authHandler, ticketHandler, authService, logger)
+	appRouter := router.NewRouter(authHandler, adminHandler, apiKeyHandler, ticketHandler, categoryHandler, profileHandler, privacyHandler, authService, apiKeyService, tenantService, logger)
 	ginRouter := appRouter.SetupRoutes()
 
 	// Create HTTP server
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS ticket_attachments (
+    attachment_id VARCHAR(100) NOT NULL PRIMARY KEY,
+    inv_gate_id VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_inv_gate_id (inv_gate_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,6 @@
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'requester',
+    ADD INDEX idx_role (role);
+
+-- Promote the first administrator manually, e.g.:
+-- UPDATE users SET role = 'admin' WHERE email = 'admin@example.com';
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS sessions (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    user_id CHAR(36) NOT NULL,
+    user_email VARCHAR(190) NOT NULL,
+    refresh_token_id VARCHAR(64) NOT NULL,
+    device VARCHAR(255) NULL,
+    ip_address VARCHAR(64) NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL,
+    revoked_reason VARCHAR(50) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_refresh_token_id (refresh_token_id),
+    INDEX idx_user_id (user_id),
+    INDEX idx_user_email (user_email),
+    INDEX idx_expires_at (expires_at),
+    INDEX idx_revoked_at (revoked_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
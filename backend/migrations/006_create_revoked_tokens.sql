@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    token_id VARCHAR(64) NOT NULL PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN email_verified_at TIMESTAMP NULL AFTER role;
+
+-- Accounts created before email verification existed are treated as verified
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS login_attempts (
+    scope VARCHAR(10) NOT NULL,
+    `key` VARCHAR(190) NOT NULL,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP NOT NULL,
+    next_attempt_at TIMESTAMP NULL,
+    locked_until TIMESTAMP NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (scope, `key`),
+    INDEX idx_last_failure_at (last_failure_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
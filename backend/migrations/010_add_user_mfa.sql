@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN mfa_secret VARCHAR(64) NULL AFTER email_verified_at,
+    ADD COLUMN mfa_enabled_at TIMESTAMP NULL AFTER mfa_secret,
+    ADD COLUMN mfa_last_used_step BIGINT NOT NULL DEFAULT 0 AFTER mfa_enabled_at,
+    ADD COLUMN mfa_recovery_codes TEXT NULL AFTER mfa_last_used_step;
@@ -0,0 +1,12 @@
+ALTER TABLE users
+    ADD COLUMN oidc_subject VARCHAR(255) NULL AFTER mfa_recovery_codes,
+    ADD UNIQUE INDEX idx_oidc_subject (oidc_subject);
+
+CREATE TABLE IF NOT EXISTS oidc_login_states (
+    state VARCHAR(64) NOT NULL PRIMARY KEY,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
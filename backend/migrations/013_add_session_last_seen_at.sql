@@ -0,0 +1,5 @@
+ALTER TABLE sessions
+    ADD COLUMN last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER expires_at;
+
+-- Existing sessions were last seen when their refresh token was last rotated
+UPDATE sessions SET last_seen_at = updated_at;
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN phone VARCHAR(30) NULL AFTER email,
+    ADD COLUMN preferred_language VARCHAR(5) NOT NULL DEFAULT 'id' AFTER phone;
@@ -0,0 +1,33 @@
+ALTER TABLE users
+    ADD COLUMN anonymized_at TIMESTAMP NULL AFTER deactivated_at;
+
+CREATE TABLE IF NOT EXISTS data_exports (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    user_id CHAR(36) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    file_path VARCHAR(500) NULL,
+    error VARCHAR(500) NULL,
+    expires_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_user_id (user_id),
+    INDEX idx_status (status),
+    INDEX idx_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS account_deletions (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    user_id CHAR(36) NOT NULL,
+    email_hash VARCHAR(64) NOT NULL,
+    invgate_user_id INT NOT NULL DEFAULT 0,
+    invgate_action VARCHAR(20) NOT NULL,
+    requested_by VARCHAR(190) NOT NULL,
+    request_ip VARCHAR(64) NULL,
+    status VARCHAR(20) NOT NULL,
+    error VARCHAR(500) NULL,
+    completed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_user_id (user_id),
+    INDEX idx_email_hash (email_hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,24 @@
+ALTER TABLE users
+    ADD COLUMN invgate_company_id INT NOT NULL DEFAULT 0 AFTER invgate_user_id,
+    ADD COLUMN invgate_group_id INT NOT NULL DEFAULT 0 AFTER invgate_company_id,
+    ADD COLUMN invgate_location_id INT NOT NULL DEFAULT 0 AFTER invgate_group_id;
+
+CREATE TABLE IF NOT EXISTS registration_invites (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    code_hash VARCHAR(64) NOT NULL,
+    email VARCHAR(190) NULL,
+    invgate_company_id INT NOT NULL DEFAULT 0,
+    invgate_group_id INT NOT NULL DEFAULT 0,
+    invgate_location_id INT NOT NULL DEFAULT 0,
+    note VARCHAR(255) NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    used_by VARCHAR(190) NULL,
+    revoked_at TIMESTAMP NULL,
+    created_by VARCHAR(190) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_code_hash (code_hash),
+    INDEX idx_email (email),
+    INDEX idx_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS tenants (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    slug VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    hosts VARCHAR(500) NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    invgate_base_url VARCHAR(255) NOT NULL,
+    invgate_username VARCHAR(190) NOT NULL,
+    invgate_password VARCHAR(255) NOT NULL,
+    invgate_page_key VARCHAR(100) NULL,
+    invgate_company_id INT NOT NULL DEFAULT 0,
+    invgate_group_id INT NOT NULL DEFAULT 0,
+    invgate_location_id INT NOT NULL DEFAULT 0,
+    allowed_category_ids VARCHAR(1000) NULL,
+    registration_domains VARCHAR(1000) NULL,
+    created_by VARCHAR(190) NULL,
+    updated_by VARCHAR(190) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_slug (slug),
+    INDEX idx_is_default (is_default)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Existing rows are assigned to the default tenant on startup
+ALTER TABLE users
+    ADD COLUMN tenant_id CHAR(36) NOT NULL DEFAULT '' AFTER id,
+    ADD INDEX idx_tenant_id (tenant_id);
+
+ALTER TABLE tickets
+    ADD COLUMN tenant_id CHAR(36) NOT NULL DEFAULT '' AFTER id,
+    ADD INDEX idx_tenant_id (tenant_id);
+
+ALTER TABLE registration_invites
+    ADD COLUMN tenant_id CHAR(36) NOT NULL DEFAULT '' AFTER id,
+    ADD INDEX idx_tenant_id (tenant_id);
+
+-- Attachment IDs are only unique within one InvGate instance
+ALTER TABLE ticket_attachments
+    ADD COLUMN tenant_id CHAR(36) NOT NULL DEFAULT '' FIRST,
+    DROP PRIMARY KEY,
+    ADD PRIMARY KEY (tenant_id, attachment_id);
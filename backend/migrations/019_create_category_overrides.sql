@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS category_overrides (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    tenant_id CHAR(36) NOT NULL DEFAULT '',
+    category_id INT NOT NULL,
+    icon VARCHAR(100) NULL,
+    description VARCHAR(500) NULL,
+    updated_by VARCHAR(190) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_tenant_category (tenant_id, category_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,14 @@
+ALTER TABLE tickets
+    ADD COLUMN status_id INT NOT NULL DEFAULT 0,
+    ADD COLUMN assigned_id INT NOT NULL DEFAULT 0,
+    ADD COLUMN assigned_group_id INT NOT NULL DEFAULT 0,
+    ADD COLUMN solved_at TIMESTAMP NULL,
+    ADD COLUMN closed_at TIMESTAMP NULL,
+    ADD COLUMN last_comment_at TIMESTAMP NULL,
+    ADD COLUMN invgate_updated_at TIMESTAMP NULL,
+    ADD COLUMN synced_at TIMESTAMP NULL,
+    ADD INDEX idx_tickets_status_id (status_id),
+    ADD INDEX idx_tickets_closed_at (closed_at),
+    ADD INDEX idx_tickets_synced_at (synced_at);
+
+-- Existing tickets keep synced_at NULL and are loaded by the sync worker on its first runs
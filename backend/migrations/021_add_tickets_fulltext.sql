@@ -0,0 +1,2 @@
+ALTER TABLE tickets
+    ADD FULLTEXT INDEX idx_tickets_search (title, description);
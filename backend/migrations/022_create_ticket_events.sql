@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS ticket_events (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    tenant_id CHAR(36) NOT NULL DEFAULT '',
+    inv_gate_id VARCHAR(100) NOT NULL,
+    type VARCHAR(30) NOT NULL,
+    actor_email VARCHAR(190) NOT NULL,
+    details TEXT NULL,
+    request_id VARCHAR(64) NULL,
+    ip_address VARCHAR(64) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_ticket_events_ticket (tenant_id, inv_gate_id),
+    INDEX idx_ticket_events_actor_email (actor_email),
+    INDEX idx_ticket_events_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,5 @@
+ALTER TABLE tickets
+    ADD COLUMN pretty_id VARCHAR(50) NULL AFTER inv_gate_id;
+
+-- Filled in by the sync worker; mark all tickets for the next runs
+UPDATE tickets SET synced_at = NULL;
@@ -0,0 +1,4 @@
+-- Passwords are stored AES-GCM encrypted and base64 encoded; existing plain
+-- text passwords are encrypted by the application on startup
+ALTER TABLE tenants
+    MODIFY COLUMN invgate_password VARCHAR(512) NOT NULL;
@@ -0,0 +1,5 @@
+-- The same email may be registered with several tenants. The unique index
+-- created for the email column in 001 is named after the column.
+ALTER TABLE users
+    DROP INDEX email,
+    ADD UNIQUE INDEX idx_tenant_email (tenant_id, email);
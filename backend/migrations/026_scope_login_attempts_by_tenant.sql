@@ -0,0 +1,6 @@
+-- Email keys are prefixed with the tenant ID. Counters stored under the old
+-- keys are short lived and simply dropped.
+ALTER TABLE login_attempts
+    MODIFY COLUMN `key` VARCHAR(255) NOT NULL;
+
+DELETE FROM login_attempts WHERE scope = 'email';
@@ -1,12 +1,22 @@
 import { http, createFormData } from './http'
 import type { Comment, CommentsResponse, CreateCommentPayload } from './types'
 
+const COMMENTS_PAGE_SIZE = 100
+
 export const commentsApi = {
+  // Comments are served a page at a time; follow the cursors to get all of them
   getByTicketId: async (ticketId: number): Promise<Comment[]> => {
-    const response = await http.get<CommentsResponse>(
-      `/tickets/${ticketId}/comments`
-    )
-    return response.data.data
+    const comments: Comment[] = []
+    let cursor: string | null = null
+    do {
+      const query: string = cursor ? `&cursor=${encodeURIComponent(cursor)}` : ''
+      const response = await http.get<CommentsResponse>(
+        `/tickets/${ticketId}/comments?limit=${COMMENTS_PAGE_SIZE}${query}`
+      )
+      comments.push(...response.data.data)
+      cursor = response.data.pagination?.has_next ? response.data.pagination.next_cursor : null
+    } while (cursor)
+    return comments
   },
 
   create: async (
@@ -72,8 +72,17 @@ export interface TicketsResponse {
   pagination?: PaginationMeta
 }
 
+export interface CursorPaginationMeta {
+  limit: number
+  has_next: boolean
+  has_prev: boolean
+  next_cursor: string | null
+  prev_cursor: string | null
+}
+
 export interface CommentsResponse {
   data: Comment[]
+  pagination: CursorPaginationMeta
 }
 
 export interface CategoriesResponse {
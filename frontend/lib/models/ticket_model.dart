@@ -316,15 +316,20 @@ class CommentRequest {
 
 class CommentListResponse {
   final List<Comment> data;
+  final String? nextCursor;
 
-  CommentListResponse({required this.data});
+  CommentListResponse({required this.data, this.nextCursor});
 
   factory CommentListResponse.fromJson(Map<String, dynamic> json) {
+    final pagination = json['pagination'] as Map<String, dynamic>?;
     return CommentListResponse(
       data: (json['data'] as List<dynamic>?)
               ?.map((item) => Comment.fromJson(item as Map<String, dynamic>))
               .toList() ??
           [],
+      nextCursor: pagination?['has_next'] == true
+          ? pagination!['next_cursor'] as String?
+          : null,
     );
   }
 }
@@ -8,6 +8,8 @@ import '../utils/api_client.dart';
 import '../utils/attachments_utils.dart';
 
 class TicketService {
+  static const int commentsPageSize = 100;
+
   /// Create ticket tanpa attachment (JSON body).
   Future<Ticket> createTicket(TicketRequest request) async {
     final response = await ApiClient.post(
@@ -119,15 +121,37 @@ class TicketService {
     }
   }
 
+  // Comments are fetched a page at a time, following the cursors until all
+  // of them are loaded
   Future<CommentListResponse> getComments(int ticketId) async {
-    final response = await ApiClient.get('/tickets/$ticketId/comments');
+    final comments = <Comment>[];
+    String? cursor;
+
+    do {
+      final queryParams = <String, String>{
+        'limit': commentsPageSize.toString(),
+      };
+      if (cursor != null) {
+        queryParams['cursor'] = cursor;
+      }
+
+      final response = await ApiClient.get(
+        '/tickets/$ticketId/comments',
+        queryParams: queryParams,
+      );
 
-    if (response.statusCode == 200) {
-      return CommentListResponse.fromJson(ApiClient.parseResponse(response));
-    } else {
-      final error = ApiClient.parseResponse(response);
-      throw Exception(error['error'] ?? 'Failed to fetch comments');
-    }
+      if (response.statusCode != 200) {
+        final error = ApiClient.parseResponse(response);
+        throw Exception(error['error'] ?? 'Failed to fetch comments');
+      }
+
+      final page =
+          CommentListResponse.fromJson(ApiClient.parseResponse(response));
+      comments.addAll(page.data);
+      cursor = page.nextCursor;
+    } while (cursor != null);
+
+    return CommentListResponse(data: comments);
   }
 
   Future<Comment> addComment(int ticketId, CommentRequest request) async {